@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"math"
+	"slices"
+)
+
+// Summary describes the distribution of values derived from the elements of a
+// Collection, as created by Aggregate.
+type Summary struct {
+	// Count is the number of values.
+	Count int
+
+	// Sum is the sum of the values.
+	Sum float64
+
+	// Min is the smallest value, or NaN if there are no values.
+	Min float64
+
+	// Max is the largest value, or NaN if there are no values.
+	Max float64
+
+	// Mean is the arithmetic mean of the values, or NaN if there are no values.
+	Mean float64
+
+	// values in ascending order
+	values []float64
+}
+
+// Aggregate summarizes the distribution of the values produced by applying fn
+// to each element of col, e.g. the lengths of a set of strings.
+//
+// The values are retained by the Summary for computing percentiles and
+// histograms, at the cost of one float64 per element of col.
+func Aggregate[T any](col Collection[T], fn func(T) float64) Summary {
+	values := make([]float64, 0, col.Size())
+	sum := 0.0
+	for item := range col.Items() {
+		v := fn(item)
+		values = append(values, v)
+		sum += v
+	}
+	slices.Sort(values)
+
+	if len(values) == 0 {
+		return Summary{Min: math.NaN(), Max: math.NaN(), Mean: math.NaN()}
+	}
+
+	return Summary{
+		Count:  len(values),
+		Sum:    sum,
+		Min:    values[0],
+		Max:    values[len(values)-1],
+		Mean:   sum / float64(len(values)),
+		values: values,
+	}
+}
+
+// Percentile returns the p-th percentile of the values, where p is in the
+// range [0, 100]. Values of p outside that range are clamped.
+//
+// The percentile is interpolated linearly between the two closest values, such
+// that Percentile(0) is Min, Percentile(50) is the median, and Percentile(100)
+// is Max.
+//
+// Returns NaN if there are no values.
+func (s Summary) Percentile(p float64) float64 {
+	if len(s.values) == 0 {
+		return math.NaN()
+	}
+
+	rank := min(max(p, 0), 100) / 100 * float64(len(s.values)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return s.values[lo] + (s.values[hi]-s.values[lo])*(rank-float64(lo))
+}
+
+// Histogram returns the number of values in each of the buckets delimited by
+// bounds, which must be in ascending order.
+//
+// The result contains len(bounds)+1 counts, where count i is the number of
+// values v such that bounds[i-1] < v <= bounds[i], and the last count is the
+// number of values greater than every bound.
+func (s Summary) Histogram(bounds ...float64) []int {
+	counts := make([]int, len(bounds)+1)
+	for _, v := range s.values {
+		i, _ := slices.BinarySearch(bounds, v)
+		counts[i]++
+	}
+	return counts
+}
@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"math"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestAggregate(t *testing.T) {
+	s := From([]string{"a", "bb", "ccc", "dddd", "eeeee"})
+	length := func(s string) float64 { return float64(len(s)) }
+
+	summary := Aggregate[string](s, length)
+	must.Eq(t, 5, summary.Count)
+	must.Eq(t, 15, summary.Sum)
+	must.Eq(t, 1, summary.Min)
+	must.Eq(t, 5, summary.Max)
+	must.Eq(t, 3, summary.Mean)
+
+	must.Eq(t, 1, summary.Percentile(0))
+	must.Eq(t, 3, summary.Percentile(50))
+	must.Eq(t, 4.6, summary.Percentile(90))
+	must.Eq(t, 5, summary.Percentile(100))
+	must.Eq(t, 5, summary.Percentile(200))
+	must.Eq(t, 1, summary.Percentile(-1))
+
+	must.Eq(t, []int{2, 2, 1}, summary.Histogram(2, 4))
+	must.Eq(t, []int{5}, summary.Histogram())
+	must.Eq(t, []int{0, 5}, summary.Histogram(0))
+}
+
+func TestAggregate_empty(t *testing.T) {
+	summary := Aggregate[int](NewTreeSet[int](cmp.Compare[int]), func(i int) float64 {
+		return float64(i)
+	})
+	must.Eq(t, 0, summary.Count)
+	must.Eq(t, 0, summary.Sum)
+	must.True(t, math.IsNaN(summary.Min))
+	must.True(t, math.IsNaN(summary.Max))
+	must.True(t, math.IsNaN(summary.Mean))
+	must.True(t, math.IsNaN(summary.Percentile(50)))
+	must.Eq(t, []int{0, 0}, summary.Histogram(1))
+}
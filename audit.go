@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+const (
+	auditFormat  = "go-set/audit"
+	auditVersion = 1
+)
+
+// auditHeader is the first line of an audit export.
+type auditHeader struct {
+	Format   string `json:"format"`
+	Version  int    `json:"version"`
+	Size     int    `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// auditRecord is a line of an audit export describing one element.
+type auditRecord struct {
+	ID      string          `json:"id"`
+	Element json.RawMessage `json:"element"`
+}
+
+// ExportAudit writes the elements of col to w in a line-delimited JSON format
+// suitable for archiving point-in-time exports of membership, e.g. of an
+// access control list for compliance purposes. The export is read by
+// ImportAudit.
+//
+// The first line is a header recording the format version, the number of
+// elements, and a checksum of the set. Each subsequent line records one
+// element along with its ID, e.g.
+//
+//	{"format":"go-set/audit","version":1,"size":2,"checksum":"sha256:..."}
+//	{"id":"2b8b8159...","element":"alice"}
+//	{"id":"6ebe9b6e...","element":"bob"}
+//
+// The ID of an element is the hex encoded SHA-256 hash of its JSON encoding,
+// and so is stable across exports. Elements are written in ascending order of
+// ID, with elements of the same JSON encoding written once, so identical sets
+// always produce identical exports. The checksum is the root of the Digest of
+// the IDs, as created by ExportDigest.
+//
+// Returns an error if an element cannot be encoded as JSON, or writing to w
+// fails.
+func ExportAudit[T any](w io.Writer, col Collection[T]) error {
+	records := make([]auditRecord, 0, col.Size())
+	for item := range col.Items() {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		records = append(records, auditRecord{ID: hex.EncodeToString(sum[:]), Element: data})
+	}
+	slices.SortFunc(records, func(a, b auditRecord) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	records = slices.CompactFunc(records, func(a, b auditRecord) bool {
+		return a.ID == b.ID
+	})
+
+	enc := json.NewEncoder(w)
+	err := enc.Encode(auditHeader{
+		Format:   auditFormat,
+		Version:  auditVersion,
+		Size:     len(records),
+		Checksum: auditChecksum(records),
+	})
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportAudit reads an export written by ExportAudit from r, inserting each
+// element into a set created by create, e.g.
+//
+//	s, err := set.ImportAudit(f, func() *set.Set[string] {
+//	  return set.New[string](0)
+//	})
+//
+// Returns an error wrapping ErrInvalidDigest if the export is not of a
+// supported version, or its elements do not match their IDs, or the IDs are
+// not in ascending order, or the elements do not match the size and checksum
+// recorded by its header.
+func ImportAudit[T any, C Collection[T]](r io.Reader, create func() C) (C, error) {
+	var zero C
+	dec := json.NewDecoder(r)
+
+	var header auditHeader
+	if err := dec.Decode(&header); err != nil {
+		return zero, fmt.Errorf("failed to read audit header: %w", err)
+	}
+	if header.Format != auditFormat || header.Version != auditVersion {
+		return zero, fmt.Errorf("%w: unsupported audit format %q version %d", ErrInvalidDigest, header.Format, header.Version)
+	}
+
+	col := create()
+	var records []auditRecord
+	for {
+		var record auditRecord
+		err := dec.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return zero, fmt.Errorf("failed to read audit record: %w", err)
+		}
+
+		sum := sha256.Sum256(record.Element)
+		if id := hex.EncodeToString(sum[:]); id != record.ID {
+			return zero, fmt.Errorf("%w: element %s has id %s, want %s", ErrInvalidDigest, record.Element, id, record.ID)
+		}
+		if len(records) > 0 && records[len(records)-1].ID >= record.ID {
+			return zero, fmt.Errorf("%w: id %s is out of order", ErrInvalidDigest, record.ID)
+		}
+		records = append(records, record)
+
+		var item T
+		if err := json.Unmarshal(record.Element, &item); err != nil {
+			return zero, fmt.Errorf("failed to decode element %s: %w", record.Element, err)
+		}
+		col.Insert(item)
+	}
+
+	if len(records) != header.Size {
+		return zero, fmt.Errorf("%w: audit has %d elements, want %d", ErrInvalidDigest, len(records), header.Size)
+	}
+	if checksum := auditChecksum(records); checksum != header.Checksum {
+		return zero, fmt.Errorf("%w: audit has checksum %s, want %s", ErrInvalidDigest, checksum, header.Checksum)
+	}
+	return col, nil
+}
+
+// auditChecksum returns the Merkle Tree Hash of the IDs of records, which must
+// be in ascending order.
+func auditChecksum(records []auditRecord) string {
+	hashes := make([][]byte, 0, len(records))
+	for _, record := range records {
+		hash, _ := hex.DecodeString(record.ID)
+		hashes = append(hashes, hash)
+	}
+	return "sha256:" + hex.EncodeToString(merkleRoot(hashes))
+}
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestExportAudit(t *testing.T) {
+	var a, b bytes.Buffer
+	must.NoError(t, ExportAudit[string](&a, From([]string{"bob", "alice", "carol"})))
+	must.NoError(t, ExportAudit[string](&b, TreeSetFrom([]string{"carol", "bob", "alice"}, cmp.Compare[string])))
+	must.Eq(t, a.String(), b.String())
+
+	lines := strings.Split(strings.TrimSuffix(a.String(), "\n"), "\n")
+	must.SliceLen(t, 4, lines)
+
+	// the checksum is the root of the digest of the encoded elements
+	digest := ExportDigest[string](Of("alice", "bob", "carol"), func(s string) []byte {
+		data, _ := json.Marshal(s)
+		sum := sha256.Sum256(data)
+		return sum[:]
+	})
+	var header auditHeader
+	must.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+	must.Eq(t, auditHeader{
+		Format:   "go-set/audit",
+		Version:  1,
+		Size:     3,
+		Checksum: "sha256:" + hex.EncodeToString(digest.Root),
+	}, header)
+
+	sum := sha256.Sum256([]byte(`"alice"`))
+	must.StrContains(t, a.String(), `{"id":"`+hex.EncodeToString(sum[:])+`","element":"alice"}`+"\n")
+
+	t.Run("empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		must.NoError(t, ExportAudit[int](&buf, New[int](0)))
+		must.Eq(t, `{"format":"go-set/audit","version":1,"size":0,"checksum":"sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}`+"\n", buf.String())
+	})
+}
+
+func TestImportAudit(t *testing.T) {
+	export := func(items ...int) string {
+		var buf bytes.Buffer
+		must.NoError(t, ExportAudit[int](&buf, From(items)))
+		return buf.String()
+	}
+	create := func() *TreeSet[int] {
+		return NewTreeSet[int](cmp.Compare[int])
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		s, err := ImportAudit(strings.NewReader(export(3, 1, 2)), create)
+		must.NoError(t, err)
+		must.Eq(t, []int{1, 2, 3}, s.Slice())
+
+		s, err = ImportAudit(strings.NewReader(export()), create)
+		must.NoError(t, err)
+		must.Empty(t, s)
+	})
+
+	t.Run("tampered element", func(t *testing.T) {
+		data := strings.Replace(export(1, 2), `"element":2`, `"element":4`, 1)
+		_, err := ImportAudit(strings.NewReader(data), create)
+		must.ErrorIs(t, err, ErrInvalidDigest)
+	})
+
+	t.Run("removed element", func(t *testing.T) {
+		lines := strings.SplitAfter(export(1, 2, 3), "\n")
+		data := lines[0] + lines[1] + lines[3]
+		_, err := ImportAudit(strings.NewReader(data), create)
+		must.ErrorIs(t, err, ErrInvalidDigest)
+		must.StrContains(t, err.Error(), "audit has 2 elements, want 3")
+	})
+
+	t.Run("substituted element", func(t *testing.T) {
+		header := strings.SplitAfter(export(1, 2), "\n")[0]
+		body := strings.SplitAfter(export(1, 3), "\n")
+		_, err := ImportAudit(strings.NewReader(header+body[1]+body[2]), create)
+		must.ErrorIs(t, err, ErrInvalidDigest)
+		must.StrContains(t, err.Error(), "audit has checksum")
+	})
+
+	t.Run("out of order", func(t *testing.T) {
+		lines := strings.SplitAfter(export(1, 2), "\n")
+		data := lines[0] + lines[2] + lines[1]
+		_, err := ImportAudit(strings.NewReader(data), create)
+		must.ErrorIs(t, err, ErrInvalidDigest)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := ImportAudit(strings.NewReader(`{"format":"go-set/audit","version":2}`), create)
+		must.ErrorIs(t, err, ErrInvalidDigest)
+		must.EqError(t, err, `set: invalid digest: unsupported audit format "go-set/audit" version 2`)
+
+		_, err = ImportAudit(strings.NewReader(``), create)
+		must.Error(t, err)
+	})
+}
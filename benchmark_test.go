@@ -5,8 +5,13 @@ package set
 
 import (
 	"cmp"
+	"encoding/json"
+	"fmt"
 	"math/rand"
+	"runtime"
+	"slices"
 	"sort"
+	"sync"
 	"testing"
 )
 
@@ -187,3 +192,291 @@ func BenchmarkTreeSet_Contains(b *testing.B) {
 		})
 	}
 }
+
+// The Compare benchmarks measure the integer fast path of TreeSet, by
+// comparing cmp.Compare with an equivalent CompareFunc which is not
+// recognized, e.g.
+//
+//	go test -run=- -bench=TreeSet_Compare
+
+var compareCases = []struct {
+	name    string
+	compare CompareFunc[int]
+}{
+	{name: "cmp.Compare", compare: cmp.Compare[int]},
+	{name: "func", compare: func(a, b int) int { return cmp.Compare(a, b) }},
+}
+
+func BenchmarkTreeSet_CompareInsert(b *testing.B) {
+	for _, cc := range compareCases {
+		for _, tc := range cases {
+			items := random[int](tc.size)
+			b.Run(cc.name+"/"+tc.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_ = TreeSetFrom(items, cc.compare)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkTreeSet_CompareContains(b *testing.B) {
+	for _, cc := range compareCases {
+		for _, tc := range cases {
+			items := random[int](tc.size)
+			ts := TreeSetFrom(items, cc.compare)
+			b.Run(cc.name+"/"+tc.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_ = ts.Contains(items[i%len(items)])
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkCompactTreeSet_Insert(b *testing.B) {
+	for _, tc := range cases {
+		ts := CompactTreeSetFrom[int](random[int](tc.size), cmp.Compare[int])
+		b.Run(tc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ts.Insert(i)
+			}
+		})
+	}
+}
+
+func BenchmarkCompactTreeSet_Contains(b *testing.B) {
+	for _, tc := range cases {
+		ts := CompactTreeSetFrom[int](random[int](tc.size), cmp.Compare[int])
+		b.Run(tc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = ts.Contains(i)
+			}
+		})
+	}
+}
+
+// The Memory benchmarks report the bytes allocated per element when building a
+// set from scratch, e.g.
+//
+//	go test -run=- -bench=Memory
+
+// bytesPerElement reports the average number of bytes allocated per element
+// over the b.N iterations of build.
+func bytesPerElement(b *testing.B, n int, build func()) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for i := 0; i < b.N; i++ {
+		build()
+	}
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.TotalAlloc-before.TotalAlloc)/float64(b.N*n), "B/element")
+}
+
+func BenchmarkTreeSet_Memory(b *testing.B) {
+	for _, tc := range cases {
+		items := random[int](tc.size)
+		b.Run(tc.name, func(b *testing.B) {
+			bytesPerElement(b, tc.size, func() {
+				_ = TreeSetFrom[int](items, cmp.Compare[int])
+			})
+		})
+	}
+}
+
+func BenchmarkCompactTreeSet_Memory(b *testing.B) {
+	for _, tc := range cases {
+		items := random[int](tc.size)
+		b.Run(tc.name, func(b *testing.B) {
+			bytesPerElement(b, tc.size, func() {
+				_ = CompactTreeSetFrom[int](items, cmp.Compare[int])
+			})
+		})
+	}
+}
+
+func BenchmarkSkipSet_Insert(b *testing.B) {
+	for _, tc := range cases {
+		s := SkipSetFrom[int](random[int](tc.size), cmp.Compare[int])
+		b.Run(tc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.Insert(i)
+			}
+		})
+	}
+}
+
+func BenchmarkSkipSet_Contains(b *testing.B) {
+	for _, tc := range cases {
+		s := SkipSetFrom[int](random[int](tc.size), cmp.Compare[int])
+		b.Run(tc.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					_ = s.Contains(i)
+				}
+			})
+		})
+	}
+}
+
+// implementations creates each kind of set from the given items, for the bulk
+// operation benchmarks, which are named e.g. BenchmarkUnion/TreeSet/1000 for
+// comparison across implementations and sizes with benchstat.
+var implementations = []struct {
+	name   string
+	create func(items []int) Collection[int]
+}{
+	{name: "Set", create: func(items []int) Collection[int] { return From(items) }},
+	{name: "HashSet", create: func(items []int) Collection[int] {
+		return HashSetFromFunc(items, func(i int) int { return i })
+	}},
+	{name: "TreeSet", create: func(items []int) Collection[int] { return TreeSetFrom(items, cmp.Compare[int]) }},
+	{name: "CompactTreeSet", create: func(items []int) Collection[int] { return CompactTreeSetFrom(items, cmp.Compare[int]) }},
+	{name: "SkipSet", create: func(items []int) Collection[int] { return SkipSetFrom(items, cmp.Compare[int]) }},
+}
+
+// benchmarkBulk runs op against pairs of sets of each implementation and size,
+// where half the elements of each set are also in the other.
+func benchmarkBulk(b *testing.B, op func(a, b Collection[int])) {
+	for _, impl := range implementations {
+		for _, tc := range cases {
+			b.Run(impl.name+"/"+tc.name, func(b *testing.B) {
+				items := random[int](tc.size + tc.size/2)
+				x := impl.create(items[:tc.size])
+				y := impl.create(items[tc.size/2:])
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					op(x, y)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkUnion(b *testing.B) {
+	benchmarkBulk(b, func(x, y Collection[int]) {
+		_ = x.Union(y)
+	})
+}
+
+func BenchmarkUnionIter(b *testing.B) {
+	cols := make([]Collection[int], 64)
+	for i := range cols {
+		cols[i] = From(ints(1000)[i*10:])
+	}
+
+	b.Run("pairwise", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var result Collection[int] = New[int](0)
+			for _, col := range cols {
+				result = result.Union(col)
+			}
+		}
+	})
+
+	b.Run("iter", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = UnionIter(slices.Values(cols))
+		}
+	})
+}
+
+func BenchmarkIntersect(b *testing.B) {
+	benchmarkBulk(b, func(x, y Collection[int]) {
+		_ = x.Intersect(y)
+	})
+}
+
+func BenchmarkDifference(b *testing.B) {
+	benchmarkBulk(b, func(x, y Collection[int]) {
+		_ = x.Difference(y)
+	})
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	benchmarkBulk(b, func(x, _ Collection[int]) {
+		if _, err := json.Marshal(x); err != nil {
+			b.Fatal(err)
+		}
+	})
+}
+
+// lockedSet guards a Set with a sync.RWMutex, as the baseline for the
+// concurrent sets in BenchmarkContention.
+type lockedSet struct {
+	lock sync.RWMutex
+	set  *Set[int]
+}
+
+func (s *lockedSet) Insert(item int) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.set.Insert(item)
+}
+
+func (s *lockedSet) Remove(item int) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.set.Remove(item)
+}
+
+func (s *lockedSet) Contains(item int) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.set.Contains(item)
+}
+
+// BenchmarkContention measures the concurrent sets under a read-mostly
+// workload of one write per 100 operations, at increasing numbers of
+// goroutines, e.g. BenchmarkContention/ReadMostlySet/16. Writes alternate
+// between inserting and removing elements outside the initial range, so that
+// each modifies the set.
+func BenchmarkContention(b *testing.B) {
+	type concurrentSet interface {
+		Insert(int) bool
+		Remove(int) bool
+		Contains(int) bool
+	}
+
+	impls := []struct {
+		name   string
+		create func(items []int) concurrentSet
+	}{
+		{name: "RWMutex", create: func(items []int) concurrentSet { return &lockedSet{set: From(items)} }},
+		{name: "SkipSet", create: func(items []int) concurrentSet { return SkipSetFrom(items, cmp.Compare[int]) }},
+		{name: "ReadMostlySet", create: func(items []int) concurrentSet { return ReadMostlySetFrom(items) }},
+	}
+
+	const size = 1_000
+	for _, impl := range impls {
+		for _, goroutines := range []int{1, 4, 16, 64} {
+			b.Run(fmt.Sprintf("%s/%d", impl.name, goroutines), func(b *testing.B) {
+				s := impl.create(ints(size))
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				var wg sync.WaitGroup
+				for g := 0; g < goroutines; g++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for i := g; i < b.N; i += goroutines {
+							if i%100 == 0 {
+								// pairs of writes insert then remove a new element
+								if w := i / 100; w%2 == 0 {
+									s.Insert(size + w/2)
+								} else {
+									s.Remove(size + w/2)
+								}
+							} else {
+								_ = s.Contains(i % size)
+							}
+						}
+					}()
+				}
+				wg.Wait()
+			})
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import "fmt"
+
+// Enum represents the integer types that may be stored in a bitmask by ToBits
+// and FromBits.
+//
+// Typically an Enum is a named integer type with a set of constants defined
+// by iota, e.g. a set of feature flags.
+type Enum interface {
+	~int | ~uint | ~int64 | ~uint64 | ~int32 | ~uint32 | ~int16 | ~uint16 | ~int8 | ~uint8
+}
+
+// maxBits is the number of distinct elements that fit in a bitmask.
+const maxBits = 64
+
+// ToBits converts s into a bitmask, where each element e of s sets the bit at
+// position e.
+//
+// An error wrapping ErrOutOfRange is returned if any element of s is outside
+// the range [0, 64).
+func ToBits[E Enum](s *Set[E]) (uint64, error) {
+	var bits uint64
+	for item := range s.items {
+		if item < 0 || uint64(item) >= maxBits {
+			return 0, fmt.Errorf("%w for bitmask (%v)", ErrOutOfRange, item)
+		}
+		bits |= 1 << uint64(item)
+	}
+	return bits, nil
+}
+
+// FromBits creates a new Set containing an element e for each bit set at
+// position e in bits.
+func FromBits[E Enum](bits uint64) *Set[E] {
+	s := New[E](0)
+	for i := 0; i < maxBits; i++ {
+		if bits&(1<<i) != 0 {
+			s.items[E(i)] = sentinel
+		}
+	}
+	return s
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+type feature uint8
+
+const (
+	featureA feature = iota
+	featureB
+	featureC
+	featureD
+)
+
+func TestToBits(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		bits, err := ToBits(New[feature](0))
+		must.NoError(t, err)
+		must.Zero(t, bits)
+	})
+
+	t.Run("some", func(t *testing.T) {
+		bits, err := ToBits(From([]feature{featureA, featureC}))
+		must.NoError(t, err)
+		must.Eq(t, 0b0101, bits)
+	})
+
+	t.Run("max", func(t *testing.T) {
+		bits, err := ToBits(From([]int{63}))
+		must.NoError(t, err)
+		must.Eq(t, 1<<63, bits)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		_, err := ToBits(From([]int{1, 64}))
+		must.ErrorIs(t, err, ErrOutOfRange)
+		must.ErrorContains(t, err, "(64)")
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		_, err := ToBits(From([]int8{-1}))
+		must.ErrorIs(t, err, ErrOutOfRange)
+		must.ErrorContains(t, err, "(-1)")
+	})
+}
+
+func TestFromBits(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		s := FromBits[feature](0)
+		must.Empty(t, s)
+	})
+
+	t.Run("some", func(t *testing.T) {
+		s := FromBits[feature](0b1010)
+		must.True(t, s.EqualSlice([]feature{featureB, featureD}))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		bits := uint64(1<<0 | 1<<17 | 1<<63)
+		s := FromBits[uint](bits)
+		must.Size(t, 3, s)
+		result, err := ToBits(s)
+		must.NoError(t, err)
+		must.Eq(t, bits, result)
+	})
+}
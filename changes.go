@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// Change describes whether an element is inserted into or removed from a set,
+// as produced by Changes.
+type Change int
+
+const (
+	// Removal indicates an element is to be removed.
+	Removal Change = iota + 1
+
+	// Insertion indicates an element is to be inserted.
+	Insertion
+)
+
+// String returns "-" for a Removal and "+" for an Insertion.
+func (c Change) String() string {
+	switch c {
+	case Removal:
+		return "-"
+	case Insertion:
+		return "+"
+	default:
+		return "?"
+	}
+}
+
+// Changes returns a generator function producing the changes needed to
+// transform prev into current, for use with the range keyword.
+//
+//	for change, element := range set.Changes(prev, current) { ... }
+//
+// Every Removal (each element of prev not in current) is produced before any
+// Insertion (each element of current not in prev). Elements are produced in
+// the iteration order of prev and current respectively, and so for ordered
+// sets such as TreeSet the removals and insertions are each in order.
+//
+// Typically prev is a copy of current made at some earlier point, e.g. via
+// Copy, for propagating the difference to a cache or user interface.
+func Changes[T any](prev, current Collection[T]) iter.Seq2[Change, T] {
+	return func(yield func(Change, T) bool) {
+		for item := range prev.Items() {
+			if !current.Contains(item) && !yield(Removal, item) {
+				return
+			}
+		}
+		for item := range current.Items() {
+			if !prev.Contains(item) && !yield(Insertion, item) {
+				return
+			}
+		}
+	}
+}
+
+// Apply applies the changes produced by Changes to col.
+//
+// Returns true if col was modified, false otherwise.
+func Apply[T any](col Collection[T], changes iter.Seq2[Change, T]) bool {
+	modified := false
+	for change, item := range changes {
+		switch change {
+		case Removal:
+			modified = col.Remove(item) || modified
+		case Insertion:
+			modified = col.Insert(item) || modified
+		}
+	}
+	return modified
+}
+
+// Conflict describes a change of a delta which is inconsistent with the set to
+// which the delta is applied, as reported by ApplyDelta.
+//
+// A Conflict is either the Insertion of an element already in the set, or the
+// Removal of an element not in the set.
+type Conflict[T any] struct {
+	Change  Change
+	Element T
+}
+
+// String returns the change followed by the element, e.g. "+1" or "-2".
+func (c Conflict[T]) String() string {
+	return fmt.Sprintf("%s%v", c.Change, c.Element)
+}
+
+// DeltaError is the error returned by ApplyDelta when a delta conflicts with
+// the set to which it is applied. A DeltaError wraps ErrConflict.
+type DeltaError[T any] struct {
+	Conflicts []Conflict[T]
+}
+
+// Error returns a description of each conflict of e.
+func (e *DeltaError[T]) Error() string {
+	conflicts := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		conflicts = append(conflicts, c.String())
+	}
+	return fmt.Sprintf("%v: %s", ErrConflict, strings.Join(conflicts, ", "))
+}
+
+// Unwrap returns ErrConflict.
+func (e *DeltaError[T]) Unwrap() error {
+	return ErrConflict
+}
+
+// ApplyDelta removes each element of removed from col, and inserts each element
+// of added into col.
+//
+// Unlike InsertSlice and RemoveSlice, ApplyDelta does not ignore elements of
+// added already in col, nor elements of removed not in col. Such elements
+// indicate col has diverged from the set the delta was computed against, and
+// so in that case col is not modified, and a *DeltaError[T] is returned listing
+// each conflict, in the order of removed then added.
+//
+// The added and removed slices are assumed to be set-like; duplicate elements
+// within either slice are not reported as conflicts.
+func ApplyDelta[T any](col Collection[T], added, removed []T) error {
+	var conflicts []Conflict[T]
+	for _, item := range removed {
+		if !col.Contains(item) {
+			conflicts = append(conflicts, Conflict[T]{Change: Removal, Element: item})
+		}
+	}
+	for _, item := range added {
+		if col.Contains(item) {
+			conflicts = append(conflicts, Conflict[T]{Change: Insertion, Element: item})
+		}
+	}
+	if len(conflicts) > 0 {
+		return &DeltaError[T]{Conflicts: conflicts}
+	}
+
+	col.RemoveSlice(removed)
+	col.InsertSlice(added)
+	return nil
+}
+
+// diff is the JSON encoding of the changes between two sets.
+type diff[T any] struct {
+	Add    []T `json:"add"`
+	Remove []T `json:"remove"`
+}
+
+// DiffJSON serializes the changes needed to transform prev into current, as
+// produced by Changes, into a JSON object listing the elements to insert and
+// the elements to remove, e.g.
+//
+//	{"add":[4,6],"remove":[1]}
+//
+// Use ApplyDiffJSON to apply the changes to another set, e.g. a replica of
+// prev in another service.
+func DiffJSON[T any](prev, current Collection[T]) ([]byte, error) {
+	d := diff[T]{Add: make([]T, 0), Remove: make([]T, 0)}
+	for change, item := range Changes(prev, current) {
+		switch change {
+		case Insertion:
+			d.Add = append(d.Add, item)
+		case Removal:
+			d.Remove = append(d.Remove, item)
+		}
+	}
+	return json.Marshal(d)
+}
+
+// ApplyDiffJSON deserializes changes produced by DiffJSON, and applies them to
+// col via ApplyDelta.
+//
+// If col has diverged from the set the changes were computed against, col is
+// not modified, and a *DeltaError[T] is returned listing each conflict.
+func ApplyDiffJSON[T any](col Collection[T], data []byte) error {
+	var d diff[T]
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	return ApplyDelta(col, d.Add, d.Remove)
+}
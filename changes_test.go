@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestChanges(t *testing.T) {
+	collect := func(prev, current Collection[int]) []string {
+		var result []string
+		for change, item := range Changes(prev, current) {
+			result = append(result, fmt.Sprintf("%s%d", change, item))
+		}
+		return result
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		must.SliceEmpty(t, collect(New[int](0), New[int](0)))
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		must.SliceEmpty(t, collect(Of(1, 2, 3), Of(3, 2, 1)))
+	})
+
+	t.Run("ordered", func(t *testing.T) {
+		prev := TreeSetOf(cmp.Compare[int], 1, 2, 3, 5)
+		current := TreeSetOf(cmp.Compare[int], 0, 2, 4, 5, 6)
+		must.Eq(t, []string{"-1", "-3", "+0", "+4", "+6"}, collect(prev, current))
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		prev := TreeSetOf(cmp.Compare[int], 1, 2, 3)
+		current := TreeSetOf(cmp.Compare[int], 4, 5)
+		count := 0
+		for range Changes[int](prev, current) {
+			count++
+			if count == 4 {
+				break
+			}
+		}
+		must.Eq(t, 4, count)
+	})
+}
+
+func TestApply(t *testing.T) {
+	prev := Of(1, 2, 3, 5)
+	current := HashSetFromFunc([]int{0, 2, 4, 5, 6}, func(i int) int { return i })
+
+	cache := prev.Copy()
+	must.True(t, Apply[int](cache, Changes[int](prev, current)))
+	must.True(t, cache.EqualSet(current))
+
+	must.False(t, Apply[int](cache, Changes[int](current, current)))
+}
+
+func TestApplyDelta(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		s := Of(1, 2, 3)
+		must.NoError(t, ApplyDelta[int](s, nil, nil))
+		must.Eq(t, Of(1, 2, 3), s)
+	})
+
+	t.Run("applied", func(t *testing.T) {
+		s := TreeSetOf(cmp.Compare[int], 1, 2, 3)
+		must.NoError(t, ApplyDelta[int](s, []int{4, 5}, []int{1, 3}))
+		must.Eq(t, []int{2, 4, 5}, s.Slice())
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		s := TreeSetOf(cmp.Compare[int], 1, 2, 3)
+		err := ApplyDelta[int](s, []int{3, 4}, []int{1, 7})
+		must.ErrorIs(t, err, ErrConflict)
+		must.EqError(t, err, "set: conflicting change: -7, +3")
+
+		var delta *DeltaError[int]
+		must.True(t, errors.As(err, &delta))
+		must.Eq(t, []Conflict[int]{
+			{Change: Removal, Element: 7},
+			{Change: Insertion, Element: 3},
+		}, delta.Conflicts)
+
+		// not modified
+		must.Eq(t, []int{1, 2, 3}, s.Slice())
+	})
+
+	t.Run("readd", func(t *testing.T) {
+		s := Of(1, 2)
+		err := ApplyDelta[int](s, []int{2}, []int{2})
+		must.ErrorIs(t, err, ErrConflict)
+		must.Eq(t, Of(1, 2), s)
+	})
+}
+
+func TestDiffJSON(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		bs, err := DiffJSON[int](Of(1), Of(1))
+		must.NoError(t, err)
+		must.Eq(t, `{"add":[],"remove":[]}`, string(bs))
+	})
+
+	t.Run("roundtrip", func(t *testing.T) {
+		prev := TreeSetOf(cmp.Compare[int], 1, 2, 3, 5)
+		current := TreeSetOf(cmp.Compare[int], 0, 2, 4, 5)
+		bs, err := DiffJSON[int](prev, current)
+		must.NoError(t, err)
+		must.Eq(t, `{"add":[0,4],"remove":[1,3]}`, string(bs))
+
+		replica := Of(1, 2, 3, 5)
+		must.NoError(t, ApplyDiffJSON[int](replica, bs))
+		must.True(t, replica.EqualSet(current))
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		replica := Of(2, 3)
+		err := ApplyDiffJSON[int](replica, []byte(`{"add":[3],"remove":[1]}`))
+		must.ErrorIs(t, err, ErrConflict)
+		must.Eq(t, Of(2, 3), replica)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		err := ApplyDiffJSON[int](Of(1), []byte(`{"add":["a"]}`))
+		must.Error(t, err)
+	})
+}
@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Command setgen generates concrete, non-generic wrapper types around the
+// generic sets of the set package.
+//
+// Wrapper types remove type parameter noise at call sites, produce friendlier
+// documentation, and give a natural place to add methods specific to an
+// element type. Typical use is via go:generate,
+//
+//	//go:generate go run github.com/hashicorp/go-set/v3/cmd/setgen -name StringSet -elem string
+//	//go:generate go run github.com/hashicorp/go-set/v3/cmd/setgen -name NodeSet -elem *Node -kind hash -hash string
+//	//go:generate go run github.com/hashicorp/go-set/v3/cmd/setgen -name IDSet -elem int -kind tree -compare cmp.Compare[int] -import cmp
+//
+// By default the output is written to a file named after the wrapper type,
+// e.g. string_set.go, in the current directory.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// options are the command line arguments of setgen.
+type options struct {
+	Name    string // name of the generated wrapper type
+	Elem    string // element type of the set
+	Kind    string // one of set, hash, tree
+	Hash    string // hash type of a HashSet
+	HashFn  string // hash function of a HashSet (optional)
+	Compare string // compare function of a TreeSet
+	Package string // package of the generated file
+	Imports []string
+	Output  string
+}
+
+type imports []string
+
+func (i *imports) String() string {
+	return strings.Join(*i, ",")
+}
+
+func (i *imports) Set(value string) error {
+	*i = append(*i, value)
+	return nil
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "setgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	var (
+		opts options
+		imps imports
+	)
+
+	flags := flag.NewFlagSet("setgen", flag.ContinueOnError)
+	flags.StringVar(&opts.Name, "name", "", "name of the generated type (required)")
+	flags.StringVar(&opts.Elem, "elem", "", "element type of the set (required)")
+	flags.StringVar(&opts.Kind, "kind", "set", "backing set implementation: set, hash, or tree")
+	flags.StringVar(&opts.Hash, "hash", "", "hash type H of a hash set")
+	flags.StringVar(&opts.HashFn, "hashfn", "", "hash function of a hash set (default elem.Hash)")
+	flags.StringVar(&opts.Compare, "compare", "", "compare function of a tree set")
+	flags.StringVar(&opts.Package, "package", os.Getenv("GOPACKAGE"), "package name of the generated file")
+	flags.StringVar(&opts.Output, "output", "", "output file name (default <name>.go in snake case)")
+	flags.Var(&imps, "import", "additional import path required by the generated code (repeatable)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	opts.Imports = imps
+
+	if opts.Output == "" {
+		opts.Output = snake(opts.Name) + ".go"
+	}
+
+	source, err := generate(opts)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(opts.Output, source, 0o644)
+}
+
+// generate creates the formatted Go source of the wrapper type described by
+// opts.
+func generate(opts options) ([]byte, error) {
+	if opts.Name == "" {
+		return nil, errors.New("-name is required")
+	}
+	if opts.Elem == "" {
+		return nil, errors.New("-elem is required")
+	}
+	if opts.Package == "" {
+		return nil, errors.New("-package is required when not run via go generate")
+	}
+
+	var tmpl *template.Template
+	switch opts.Kind {
+	case "set":
+		tmpl = setTemplate
+	case "hash":
+		if opts.Hash == "" {
+			return nil, errors.New("-hash is required for kind hash")
+		}
+		tmpl = hashTemplate
+	case "tree":
+		if opts.Compare == "" {
+			return nil, errors.New("-compare is required for kind tree")
+		}
+		tmpl = treeTemplate
+	default:
+		return nil, fmt.Errorf("unknown kind %q", opts.Kind)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, opts); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// snake converts a CamelCase identifier into snake_case.
+func snake(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			boundary := i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1])))
+			if boundary {
+				sb.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
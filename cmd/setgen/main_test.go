@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		source, err := generate(options{
+			Name:    "StringSet",
+			Elem:    "string",
+			Kind:    "set",
+			Package: "example",
+		})
+		must.NoError(t, err)
+		must.StrContains(t, string(source), "type StringSet struct {\n\tset *set.Set[string]\n}")
+		must.StrContains(t, string(source), "func NewStringSet(size int) *StringSet {")
+		must.StrContains(t, string(source), "func (s *StringSet) Union(o *StringSet) *StringSet {")
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		source, err := generate(options{
+			Name:    "NodeSet",
+			Elem:    "*Node",
+			Kind:    "hash",
+			Hash:    "string",
+			HashFn:  "hashNode",
+			Package: "example",
+		})
+		must.NoError(t, err)
+		must.StrContains(t, string(source), "set.NewHashSetFunc[*Node, string](size, hashNode)")
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		source, err := generate(options{
+			Name:    "IDSet",
+			Elem:    "int",
+			Kind:    "tree",
+			Compare: "cmp.Compare[int]",
+			Imports: []string{"cmp"},
+			Package: "example",
+		})
+		must.NoError(t, err)
+		must.StrContains(t, string(source), "\t\"cmp\"\n")
+		must.StrContains(t, string(source), "set.TreeSetFrom[int](items, cmp.Compare[int])")
+		must.StrContains(t, string(source), "func (s *IDSet) Min() int {")
+	})
+
+	t.Run("missing hash", func(t *testing.T) {
+		_, err := generate(options{Name: "A", Elem: "int", Kind: "hash", Package: "p"})
+		must.ErrorContains(t, err, "-hash is required")
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		_, err := generate(options{Name: "A", Elem: "int", Kind: "list", Package: "p"})
+		must.ErrorContains(t, err, `unknown kind "list"`)
+	})
+}
+
+func TestSnake(t *testing.T) {
+	must.Eq(t, "string_set", snake("StringSet"))
+	must.Eq(t, "node_id_set", snake("NodeIDSet"))
+}
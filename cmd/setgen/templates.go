@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import "text/template"
+
+const header = `// Code generated by setgen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"iter"
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+
+	"github.com/hashicorp/go-set/v3"
+)
+`
+
+const methods = `
+// Unwrap returns the underlying generic set of s.
+func (s *{{.Name}}) Unwrap() {{template "backing" .}} {
+	return s.set
+}
+
+// Insert item into s.
+//
+// Return true if s was modified (item was not already in s), false otherwise.
+func (s *{{.Name}}) Insert(item {{.Elem}}) bool {
+	return s.set.Insert(item)
+}
+
+// InsertSlice will insert each item in items into s.
+//
+// Return true if s was modified (at least one item was not already in s), false otherwise.
+func (s *{{.Name}}) InsertSlice(items []{{.Elem}}) bool {
+	return s.set.InsertSlice(items)
+}
+
+// Remove will remove item from s.
+//
+// Return true if s was modified (item was present), false otherwise.
+func (s *{{.Name}}) Remove(item {{.Elem}}) bool {
+	return s.set.Remove(item)
+}
+
+// RemoveSlice will remove each item in items from s.
+//
+// Return true if s was modified (any item was present), false otherwise.
+func (s *{{.Name}}) RemoveSlice(items []{{.Elem}}) bool {
+	return s.set.RemoveSlice(items)
+}
+
+// RemoveFunc will remove each element from s that satisfies condition f.
+//
+// Return true if s was modified, false otherwise.
+func (s *{{.Name}}) RemoveFunc(f func({{.Elem}}) bool) bool {
+	return s.set.RemoveFunc(f)
+}
+
+// Contains returns whether item is present in s.
+func (s *{{.Name}}) Contains(item {{.Elem}}) bool {
+	return s.set.Contains(item)
+}
+
+// ContainsSlice returns whether all elements in items are present in s.
+func (s *{{.Name}}) ContainsSlice(items []{{.Elem}}) bool {
+	return s.set.ContainsSlice(items)
+}
+
+// Subset returns whether o is a subset of s.
+func (s *{{.Name}}) Subset(o *{{.Name}}) bool {
+	return s.set.Subset(o.set)
+}
+
+// Size returns the cardinality of s.
+func (s *{{.Name}}) Size() int {
+	return s.set.Size()
+}
+
+// Empty returns true if s contains no elements, false otherwise.
+func (s *{{.Name}}) Empty() bool {
+	return s.set.Empty()
+}
+
+// Union returns a set that contains all elements of s and o combined.
+func (s *{{.Name}}) Union(o *{{.Name}}) *{{.Name}} {
+	return &{{.Name}}{set: s.set.Union(o.set).({{template "backing" .}})}
+}
+
+// Difference returns a set that contains elements of s that are not in o.
+func (s *{{.Name}}) Difference(o *{{.Name}}) *{{.Name}} {
+	return &{{.Name}}{set: s.set.Difference(o.set).({{template "backing" .}})}
+}
+
+// Intersect returns a set that contains elements that are present in both s and o.
+func (s *{{.Name}}) Intersect(o *{{.Name}}) *{{.Name}} {
+	return &{{.Name}}{set: s.set.Intersect(o.set).({{template "backing" .}})}
+}
+
+// Copy creates a copy of s.
+func (s *{{.Name}}) Copy() *{{.Name}} {
+	return &{{.Name}}{set: s.set.Copy()}
+}
+
+// Equal returns whether s and o contain the same elements.
+func (s *{{.Name}}) Equal(o *{{.Name}}) bool {
+	return s.set.Equal(o.set)
+}
+
+// Slice creates a copy of s as a slice.
+func (s *{{.Name}}) Slice() []{{.Elem}} {
+	return s.set.Slice()
+}
+
+// String creates a string representation of s.
+func (s *{{.Name}}) String() string {
+	return s.set.String()
+}
+
+// Items returns a generator function for iterating each element in s by using
+// the range keyword.
+func (s *{{.Name}}) Items() iter.Seq[{{.Elem}}] {
+	return s.set.Items()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *{{.Name}}) MarshalJSON() ([]byte, error) {
+	return s.set.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *{{.Name}}) UnmarshalJSON(data []byte) error {
+	if s.set == nil {
+		*s = *New{{.Name}}(0)
+	}
+	return s.set.UnmarshalJSON(data)
+}
+`
+
+var setTemplate = template.Must(template.New("set").Parse(header + `
+// {{.Name}} is a set of {{.Elem}} elements, backed by a set.Set.
+type {{.Name}} struct {
+	set {{template "backing" .}}
+}
+
+// New{{.Name}} creates a new {{.Name}} with initial underlying capacity of size.
+func New{{.Name}}(size int) *{{.Name}} {
+	return &{{.Name}}{set: set.New[{{.Elem}}](size)}
+}
+
+// {{.Name}}From creates a new {{.Name}} containing each item in items.
+func {{.Name}}From(items []{{.Elem}}) *{{.Name}} {
+	return &{{.Name}}{set: set.From[{{.Elem}}](items)}
+}
+` + methods + `
+{{- define "backing"}}*set.Set[{{.Elem}}]{{end}}`))
+
+var hashTemplate = template.Must(template.New("hash").Parse(header + `
+// {{.Name}} is a set of {{.Elem}} elements, backed by a set.HashSet.
+type {{.Name}} struct {
+	set {{template "backing" .}}
+}
+
+// New{{.Name}} creates a new {{.Name}} with initial underlying capacity of size.
+func New{{.Name}}(size int) *{{.Name}} {
+	return &{{.Name}}{set: {{template "constructor" .}}}
+}
+
+// {{.Name}}From creates a new {{.Name}} containing each item in items.
+func {{.Name}}From(items []{{.Elem}}) *{{.Name}} {
+	s := New{{.Name}}(len(items))
+	s.InsertSlice(items)
+	return s
+}
+` + methods + `
+{{- define "backing"}}*set.HashSet[{{.Elem}}, {{.Hash}}]{{end}}
+{{- define "constructor"}}
+{{- if .HashFn}}set.NewHashSetFunc[{{.Elem}}, {{.Hash}}](size, {{.HashFn}})
+{{- else}}set.NewHashSet[{{.Elem}}, {{.Hash}}](size)
+{{- end}}
+{{- end}}`))
+
+var treeTemplate = template.Must(template.New("tree").Parse(header + `
+// {{.Name}} is an ordered set of {{.Elem}} elements, backed by a set.TreeSet.
+type {{.Name}} struct {
+	set {{template "backing" .}}
+}
+
+// New{{.Name}} creates a new empty {{.Name}}.
+//
+// The size parameter is ignored, and exists for consistency with other
+// generated set types.
+func New{{.Name}}(size int) *{{.Name}} {
+	return &{{.Name}}{set: set.NewTreeSet[{{.Elem}}]({{.Compare}})}
+}
+
+// {{.Name}}From creates a new {{.Name}} containing each item in items.
+func {{.Name}}From(items []{{.Elem}}) *{{.Name}} {
+	return &{{.Name}}{set: set.TreeSetFrom[{{.Elem}}](items, {{.Compare}})}
+}
+
+// Min returns the smallest element in s.
+//
+// Must not be called on an empty set.
+func (s *{{.Name}}) Min() {{.Elem}} {
+	return s.set.Min()
+}
+
+// Max returns the largest element in s.
+//
+// Must not be called on an empty set.
+func (s *{{.Name}}) Max() {{.Elem}} {
+	return s.set.Max()
+}
+` + methods + `
+{{- define "backing"}}*set.TreeSet[{{.Elem}}]{{end}}`))
@@ -0,0 +1,384 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package collation provides locale-aware ordering of string sets, using the
+// collation algorithms of golang.org/x/text/collate.
+//
+// Ordering strings by their bytes (e.g. via cmp.Compare) is rarely what a user
+// expects for non-English text. A collate.Collator orders strings according to
+// the conventions of a given language, e.g.
+//
+//	c := collate.New(language.German)
+//	ts := set.NewTreeSet[string](collation.Compare(c))
+//
+// Comparing two strings with a Collator is relatively expensive, as each string
+// must be decoded and mapped to collation elements on every comparison. Set
+// avoids that cost by computing the sort key of each element once, when the
+// element is inserted, and ordering elements by their sort keys.
+package collation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/hashicorp/go-set/v3"
+	"golang.org/x/text/collate"
+)
+
+// Compare creates a set.CompareFunc which orders strings according to c.
+//
+// A collate.Collator is not safe for concurrent use, and so neither is the
+// returned CompareFunc. The sort key of each string is derived on every
+// comparison; use Set for an ordered set that caches sort keys.
+func Compare(c *collate.Collator) set.CompareFunc[string] {
+	return c.CompareString
+}
+
+// entry is an element of a Set along with its collation sort key.
+type entry struct {
+	value string
+	key   []byte
+}
+
+func compareEntries(a, b entry) int {
+	return bytes.Compare(a.key, b.key)
+}
+
+// Set provides an ordered set of strings, ordered according to the rules of
+// a collate.Collator.
+//
+// The underlying data structure is a set.TreeSet, where the collation sort key
+// of each element is computed when the element is inserted and stored alongside
+// it. Elements are then ordered by comparing sort keys, which is much cheaper
+// than comparing the strings themselves via the Collator.
+//
+// Two strings with the same sort key (e.g. differing only in case, for a
+// Collator created with collate.IgnoreCase) are considered the same element.
+//
+// Not thread safe, and not safe for concurrent modification, including by the
+// use of the same Collator elsewhere.
+type Set struct {
+	collator *collate.Collator
+	buf      collate.Buffer
+	tree     *set.TreeSet[entry]
+}
+
+// New creates an empty Set ordered according to c.
+func New(c *collate.Collator) *Set {
+	return &Set{
+		collator: c,
+		tree:     set.NewTreeSet[entry](compareEntries),
+	}
+}
+
+// From creates a Set ordered according to c, containing each item in items.
+func From(c *collate.Collator, items []string) *Set {
+	s := New(c)
+	s.InsertSlice(items)
+	return s
+}
+
+// stored creates an entry for item with a sort key owned by the entry.
+func (s *Set) stored(item string) entry {
+	key := s.collator.KeyFromString(&s.buf, item)
+	e := entry{value: item, key: bytes.Clone(key)}
+	s.buf.Reset()
+	return e
+}
+
+// probe calls f with an entry for item, whose sort key is only valid for the
+// duration of the call.
+func (s *Set) probe(item string, f func(entry) bool) bool {
+	e := entry{value: item, key: s.collator.KeyFromString(&s.buf, item)}
+	result := f(e)
+	s.buf.Reset()
+	return result
+}
+
+// Key returns the collation sort key of item, as used to order the elements
+// of s.
+func (s *Set) Key(item string) []byte {
+	return s.stored(item).key
+}
+
+// Insert item into s.
+//
+// Returns true if s was modified (item was not already in s), false otherwise.
+func (s *Set) Insert(item string) bool {
+	return s.tree.Insert(s.stored(item))
+}
+
+// InsertSlice will insert each item in items into s.
+//
+// Return true if s was modified (at least one item was not already in s), false otherwise.
+func (s *Set) InsertSlice(items []string) bool {
+	modified := false
+	for _, item := range items {
+		if s.Insert(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// InsertSet will insert each element of col into s.
+//
+// Return true if s was modified (at least one item of col was not already in s), false otherwise.
+func (s *Set) InsertSet(col set.Collection[string]) bool {
+	modified := false
+	for item := range col.Items() {
+		if s.Insert(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// Remove item from s.
+//
+// Returns true if s was modified (item was in s), false otherwise.
+func (s *Set) Remove(item string) bool {
+	return s.probe(item, s.tree.Remove)
+}
+
+// RemoveSlice will remove each item in items from s.
+//
+// Return true if s was modified (any item was in s), false otherwise.
+func (s *Set) RemoveSlice(items []string) bool {
+	modified := false
+	for _, item := range items {
+		if s.Remove(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// RemoveSet will remove each element in col from s.
+//
+// Returns true if s was modified (at least one item in col was in s), false otherwise.
+func (s *Set) RemoveSet(col set.Collection[string]) bool {
+	modified := false
+	for item := range col.Items() {
+		if s.Remove(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// RemoveFunc will remove each element from s that satisfies condition f.
+//
+// Return true if s was modified, false otherwise.
+func (s *Set) RemoveFunc(f func(string) bool) bool {
+	return s.tree.RemoveFunc(func(e entry) bool {
+		return f(e.value)
+	})
+}
+
+// Min returns the first element of s in collation order.
+//
+// Must not be called on an empty set.
+func (s *Set) Min() string {
+	return s.tree.Min().value
+}
+
+// Max returns the last element of s in collation order.
+//
+// Must not be called on an empty set.
+func (s *Set) Max() string {
+	return s.tree.Max().value
+}
+
+// Contains returns whether item is present in s.
+func (s *Set) Contains(item string) bool {
+	return s.probe(item, s.tree.Contains)
+}
+
+// ContainsSlice returns whether all elements in items are present in s.
+func (s *Set) ContainsSlice(items []string) bool {
+	for _, item := range items {
+		if !s.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// Subset returns whether col is a subset of s.
+func (s *Set) Subset(col set.Collection[string]) bool {
+	if col.Size() > s.Size() {
+		return false
+	}
+	for item := range col.Items() {
+		if !s.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// ProperSubset returns whether col is a proper subset of s.
+func (s *Set) ProperSubset(col set.Collection[string]) bool {
+	if s.Size() <= col.Size() {
+		return false
+	}
+	return s.Subset(col)
+}
+
+// Clear removes every element from s.
+func (s *Set) Clear() {
+	s.tree = set.NewTreeSet[entry](compareEntries)
+}
+
+// Size returns the number of elements in s.
+func (s *Set) Size() int {
+	return s.tree.Size()
+}
+
+// Empty returns true if there are no elements in s.
+func (s *Set) Empty() bool {
+	return s.Size() == 0
+}
+
+// Union returns a set that contains all elements of s and col combined.
+//
+// The result is ordered according to the Collator of s.
+func (s *Set) Union(col set.Collection[string]) set.Collection[string] {
+	result := s.Copy()
+	result.InsertSet(col)
+	return result
+}
+
+// Difference returns a set that contains elements of s that are not in col.
+//
+// The result is ordered according to the Collator of s.
+func (s *Set) Difference(col set.Collection[string]) set.Collection[string] {
+	result := s.Copy()
+	result.tree.RemoveFunc(func(e entry) bool {
+		return col.Contains(e.value)
+	})
+	return result
+}
+
+// Intersect returns a set that contains elements that are present in both s and col.
+//
+// The result is ordered according to the Collator of s.
+func (s *Set) Intersect(col set.Collection[string]) set.Collection[string] {
+	result := s.Copy()
+	result.tree.RemoveFunc(func(e entry) bool {
+		return !col.Contains(e.value)
+	})
+	return result
+}
+
+// Copy creates a copy of s, sharing the Collator of s.
+func (s *Set) Copy() *Set {
+	return &Set{
+		collator: s.collator,
+		tree:     s.tree.Copy(),
+	}
+}
+
+// Slice returns the elements of s as a slice, in collation order.
+func (s *Set) Slice() []string {
+	return s.AppendSlice(make([]string, 0, s.Size()))
+}
+
+// AppendSlice appends the elements of s to dst in collation order, and returns
+// the extended slice.
+func (s *Set) AppendSlice(dst []string) []string {
+	dst = slices.Grow(dst, s.Size())
+	for e := range s.tree.Items() {
+		dst = append(dst, e.value)
+	}
+	return dst
+}
+
+// String creates a string representation of s, using "%v" printf formatting
+// each element into a string. The result contains elements in collation order.
+func (s *Set) String() string {
+	return s.StringFunc(func(element string) string {
+		return fmt.Sprintf("%v", element)
+	})
+}
+
+// StringFunc creates a string representation of s, using f to transform each
+// element into a string. The result contains elements in collation order.
+func (s *Set) StringFunc(f func(string) string) string {
+	return s.tree.StringFunc(func(e entry) string {
+		return f(e.value)
+	})
+}
+
+// EqualSet returns whether s and col contain the same elements.
+func (s *Set) EqualSet(col set.Collection[string]) bool {
+	return s.Size() == col.Size() && s.Subset(col)
+}
+
+// EqualSlice returns whether s and items contain the same elements.
+//
+// The items slice may contain duplicates.
+func (s *Set) EqualSlice(items []string) bool {
+	return s.EqualSet(From(s.collator, items))
+}
+
+// EqualSliceSet returns whether s and items contain exactly the same elements,
+// where the elements of items are expected to be set-like. If items contains
+// duplicates EqualSliceSet returns false. For comparing s to a slice that may
+// contain duplicate elements, use EqualSlice instead.
+//
+// To detect if a slice is a subset of s, use ContainsSlice.
+func (s *Set) EqualSliceSet(items []string) bool {
+	if s.Size() != len(items) {
+		return false
+	}
+	seen := New(s.collator)
+	for _, item := range items {
+		if !s.Contains(item) || !seen.Insert(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// Items returns a generator function for iterating each element in s by using
+// the range keyword. Elements are produced in collation order.
+//
+//	for element := range s.Items() { ... }
+func (s *Set) Items() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for e := range s.tree.Items() {
+			if !yield(e.value) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+//
+// Elements are encoded in collation order.
+func (s *Set) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Slice())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// The Set must have been created with New or From, so that it has a Collator;
+// an error is returned when unmarshaling into the zero value of Set.
+func (s *Set) UnmarshalJSON(data []byte) error {
+	if s.collator == nil {
+		return errors.New("set: cannot unmarshal into zero value collation.Set, which must be created by its constructor")
+	}
+	slice := make([]string, 0)
+	if err := json.Unmarshal(data, &slice); err != nil {
+		return err
+	}
+	s.InsertSlice(slice)
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package collation
+
+import (
+	"cmp"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/go-set/v3"
+	"github.com/shoenig/test/must"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+var _ set.Collection[string] = (*Set)(nil)
+
+func TestCompare(t *testing.T) {
+	words := []string{"zebra", "Äpfel", "apple", "Zucker", "Ökonomie", "ohne"}
+
+	bytewise := set.TreeSetFrom[string](words, cmp.Compare[string])
+	must.Eq(t, []string{"Zucker", "apple", "ohne", "zebra", "Äpfel", "Ökonomie"}, bytewise.Slice())
+
+	german := set.TreeSetFrom[string](words, Compare(collate.New(language.German)))
+	must.Eq(t, []string{"Äpfel", "apple", "ohne", "Ökonomie", "zebra", "Zucker"}, german.Slice())
+
+	swedish := set.TreeSetFrom[string](words, Compare(collate.New(language.Swedish)))
+	must.Eq(t, []string{"apple", "ohne", "zebra", "Zucker", "Äpfel", "Ökonomie"}, swedish.Slice())
+}
+
+func TestSet_order(t *testing.T) {
+	words := []string{"zebra", "Äpfel", "apple", "Zucker", "Ökonomie", "ohne"}
+
+	s := From(collate.New(language.Swedish), words)
+	must.Eq(t, []string{"apple", "ohne", "zebra", "Zucker", "Äpfel", "Ökonomie"}, s.Slice())
+	must.Eq(t, "apple", s.Min())
+	must.Eq(t, "Ökonomie", s.Max())
+	must.Eq(t, "[apple ohne zebra Zucker Äpfel Ökonomie]", s.String())
+
+	must.True(t, s.Insert("ära"))
+	must.False(t, s.Insert("ära"))
+	must.Eq(t, "Äpfel", s.Slice()[4])
+	must.True(t, s.Contains("ära"))
+	must.True(t, s.Remove("ära"))
+	must.False(t, s.Contains("ära"))
+	must.Size(t, len(words), s)
+}
+
+func TestSet_ignoreCase(t *testing.T) {
+	s := New(collate.New(language.English, collate.IgnoreCase))
+	must.True(t, s.Insert("Nomad"))
+	must.False(t, s.Insert("nomad"))
+	must.True(t, s.Contains("NOMAD"))
+	must.Eq(t, []string{"Nomad"}, s.Slice())
+	must.True(t, s.Remove("nOmAd"))
+	must.Empty(t, s)
+}
+
+func TestSet_Collection(t *testing.T) {
+	c := collate.New(language.English)
+	a := From(c, []string{"a", "b", "c", "d", "e"})
+	b := set.From([]string{"d", "e", "f", "g"})
+
+	must.Eq(t, "[a b c d e f g]", a.Union(b).String())
+	must.Eq(t, "[a b c]", a.Difference(b).String())
+	must.Eq(t, "[d e]", a.Intersect(b).String())
+
+	must.True(t, a.Subset(set.From([]string{"a", "e"})))
+	must.False(t, a.Subset(b))
+	must.True(t, a.ProperSubset(set.From([]string{"a", "b", "c", "d"})))
+	must.False(t, a.ProperSubset(set.From([]string{"a", "b", "c", "d", "e"})))
+
+	must.True(t, a.EqualSlice([]string{"e", "d", "c", "b", "a", "a"}))
+	must.False(t, a.EqualSliceSet([]string{"e", "d", "c", "b", "a", "a"}))
+	must.True(t, a.EqualSliceSet([]string{"e", "d", "c", "b", "a"}))
+	must.True(t, a.EqualSet(set.From([]string{"a", "b", "c", "d", "e"})))
+
+	cp := a.Copy()
+	cp.Remove("c")
+	must.True(t, a.Contains("c"))
+	must.False(t, cp.Contains("c"))
+
+	must.True(t, a.RemoveFunc(func(s string) bool { return s < "c" }))
+	must.Eq(t, []string{"c", "d", "e"}, a.Slice())
+	must.True(t, a.RemoveSet(set.From([]string{"c", "z"})))
+	must.Eq(t, []string{"d", "e"}, a.Slice())
+
+	a.Clear()
+	must.True(t, a.Empty())
+	must.True(t, a.Insert("d"))
+	must.Eq(t, []string{"d"}, a.Slice())
+}
+
+func TestSet_JSON(t *testing.T) {
+	c := collate.New(language.Swedish)
+	s := From(c, []string{"Äpfel", "zebra", "apple"})
+	bs, err := json.Marshal(s)
+	must.NoError(t, err)
+	must.Eq(t, `["apple","zebra","Äpfel"]`, string(bs))
+
+	result := New(c)
+	must.NoError(t, json.Unmarshal(bs, result))
+	must.True(t, s.EqualSet(result))
+
+	var zero Set
+	err = json.Unmarshal(bs, &zero)
+	must.ErrorContains(t, err, "zero value collation.Set")
+}
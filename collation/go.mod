@@ -0,0 +1,13 @@
+module github.com/hashicorp/go-set/v3/collation
+
+go 1.23
+
+require (
+	github.com/hashicorp/go-set/v3 v3.0.0
+	github.com/shoenig/test v1.12.0
+	golang.org/x/text v0.21.0
+)
+
+require github.com/google/go-cmp v0.6.0 // indirect
+
+replace github.com/hashicorp/go-set/v3 => ../
@@ -3,7 +3,13 @@
 
 package set
 
-import "iter"
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+)
 
 // Collection is a minimal common interface that all sets implement.
 
@@ -50,8 +56,9 @@ type Collection[T any] interface {
 	// Contains returns whether an element is present in the set.
 	Contains(T) bool
 
-	// ContainsSlice returns whether the set contains the same set of elements as
-	// the given slice. The elements of the slice may contain duplicates.
+	// ContainsSlice returns whether every element of the given slice is present
+	// in the set, i.e. whether the slice is a subset of the set. The elements of
+	// the slice may contain duplicates.
 	ContainsSlice([]T) bool
 
 	// Subset returns whether the given Collection is a subset of the set.
@@ -103,7 +110,13 @@ type Collection[T any] interface {
 	StringFunc(func(T) string) string
 
 	// EqualSet returns whether this set and a given Collection contain the same
-	// elements.
+	// elements, regardless of the underlying implementation of either.
+	//
+	// The sets are equal if they are the same size and each element of this
+	// set is contained in the other, according to the Contains method of the
+	// other. The result is symmetric as long as both sets agree on which
+	// elements are equal (e.g. a HashSet whose hash function distinguishes
+	// every element, or a TreeSet whose CompareFunc is consistent with ==).
 	EqualSet(Collection[T]) bool
 
 	// EqualSlice returns whether this set and a given slice contain the same
@@ -119,10 +132,65 @@ type Collection[T any] interface {
 	//
 	// Note: iteration order depends on the underlying implementation.
 	//
+	// Note: the set must not be modified during iteration. Whether doing so is
+	// safe depends on the underlying implementation; use Snapshot for iterating
+	// a set that is modified along the way.
+	//
 	//	for element := range s.Items() { ... }
 	Items() iter.Seq[T]
 }
 
+// OrderedCollection is a Collection whose elements are ordered by a CompareFunc,
+// as implemented by TreeSet, CompactTreeSet, and SkipSet.
+//
+// Generic algorithms may check for an OrderedCollection via a type assertion
+// to choose a better strategy at runtime, e.g. merging the elements of two
+// ordered sets in a single pass when their Compare methods agree.
+//
+//	if ordered, ok := col.(set.OrderedCollection[T]); ok { ... }
+type OrderedCollection[T any] interface {
+	Collection[T]
+	Comparator[T]
+}
+
+// ConcurrentCollection is a Collection whose methods are safe for concurrent
+// use by multiple goroutines, as implemented by SkipSet and ReadMostlySet.
+//
+// As with OrderedCollection, generic algorithms may check for a
+// ConcurrentCollection via a type assertion, e.g. to avoid copying a set
+// before sharing it between goroutines.
+type ConcurrentCollection[T any] interface {
+	Collection[T]
+
+	// concurrent is a marker, as only the implementations of this package
+	// are known to be safe for concurrent use.
+	concurrent()
+}
+
+// Snapshot returns a generator function for use with the range keyword
+// enabling iteration of each element in col, as of the time Snapshot is called.
+//
+// Unlike Items, col may be freely modified during iteration. Elements inserted
+// into col are not produced, and elements removed from col are still produced,
+// regardless of the implementation of col. The cost is the allocation of a
+// slice containing each element of col.
+//
+//	for element := range Snapshot(s) {
+//	  if condition(element) {
+//	    s.Remove(element)
+//	  }
+//	}
+func Snapshot[T any](col Collection[T]) iter.Seq[T] {
+	items := col.Slice()
+	return func(yield func(T) bool) {
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
 // InsertSliceFunc inserts all elements from items into col, applying the transform
 // function to each element before insertion.
 //
@@ -161,6 +229,348 @@ func SliceFunc[T, E any](s Collection[T], transform func(T) E) []E {
 	return slice
 }
 
+// SortedSlice creates a copy of the elements of col as a slice, in ascending
+// order.
+func SortedSlice[T cmp.Ordered](col Collection[T]) []T {
+	result := col.Slice()
+	slices.Sort(result)
+	return result
+}
+
+// ToMap creates a map from the elements of col, with the key and value for
+// each element produced by entry, e.g. for passing a set to an API which
+// takes a map. The map is allocated with capacity for every element.
+//
+// If entry produces the same key for more than one element, the map contains
+// the value of whichever element is iterated last.
+func ToMap[T any, K comparable, V any](col Collection[T], entry func(T) (K, V)) map[K]V {
+	result := make(map[K]V, col.Size())
+	for item := range col.Items() {
+		k, v := entry(item)
+		result[k] = v
+	}
+	return result
+}
+
+// ToMapKeys creates a map whose keys are the elements of col, in the form of a
+// set commonly used by map-based APIs.
+func ToMapKeys[T comparable](col Collection[T]) map[T]struct{} {
+	result := make(map[T]struct{}, col.Size())
+	for item := range col.Items() {
+		result[item] = struct{}{}
+	}
+	return result
+}
+
+// EnsureNonEmpty returns the first element produced by iterating col (i.e. the
+// smallest element of an ordered set), e.g. for code which relies on a set
+// having at least one element.
+//
+// Returns an error wrapping ErrEmptySet if col is empty; the error names the
+// type of col. Use Must to panic instead, e.g.
+//
+//	leader := set.Must(set.EnsureNonEmpty[string](peers))
+func EnsureNonEmpty[T any](col Collection[T]) (T, error) {
+	for item := range col.Items() {
+		return item, nil
+	}
+	var zero T
+	return zero, emptyError("EnsureNonEmpty", col)
+}
+
+// IntersectBy returns the elements of a whose key is also the key of some
+// element of b, where the key of each element is computed via keyA and keyB
+// respectively. The result is in the iteration order of a.
+//
+// IntersectBy enables set algebra over collections of different element types
+// which share an identity, without building a set of keys for each.
+func IntersectBy[A, B any, K comparable](a Collection[A], b Collection[B], keyA func(A) K, keyB func(B) K) []A {
+	keys := keysOf(b, keyB)
+	result := make([]A, 0, min(a.Size(), keys.Size()))
+	for item := range a.Items() {
+		if keys.Contains(keyA(item)) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// DifferenceBy returns the elements of a whose key is not the key of any
+// element of b, where the key of each element is computed via keyA and keyB
+// respectively. The result is in the iteration order of a.
+func DifferenceBy[A, B any, K comparable](a Collection[A], b Collection[B], keyA func(A) K, keyB func(B) K) []A {
+	keys := keysOf(b, keyB)
+	result := make([]A, 0, a.Size())
+	for item := range a.Items() {
+		if !keys.Contains(keyA(item)) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// keysOf creates a Set of the keys of the elements of col.
+func keysOf[T any, K comparable](col Collection[T], key func(T) K) *Set[K] {
+	keys := New[K](col.Size())
+	for item := range col.Items() {
+		keys.Insert(key(item))
+	}
+	return keys
+}
+
+// UnionInto stores the union of cols into dst, replacing the existing elements
+// of dst. Reusing the same dst across many operations avoids allocating a new
+// set for each result, as the underlying capacity of dst is retained where the
+// implementation allows (i.e. for Set and HashSet).
+//
+// dst may also be one of cols.
+//
+// Returns the number of elements in dst.
+func UnionInto[T any](dst Collection[T], cols ...Collection[T]) int {
+	return store(dst, cols, func(yield func(T) bool) {
+		for _, col := range cols {
+			for item := range col.Items() {
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// UnionIter returns a Set containing the union of each collection produced by
+// cols, e.g. for aggregating the results of many concurrent jobs.
+//
+// Unlike chaining pairwise Union, which creates and grows an intermediate set
+// for each step, UnionIter allocates the result once, with capacity for the
+// combined size of every collection, and inserts each element directly. The
+// collections produced by cols are retained until the result is complete, and
+// so must not be modified during the call.
+func UnionIter[T comparable](cols iter.Seq[Collection[T]]) *Set[T] {
+	var (
+		all   []Collection[T]
+		total int
+	)
+	for col := range cols {
+		all = append(all, col)
+		total += col.Size()
+	}
+	result := New[T](total)
+	for _, col := range all {
+		for item := range col.Items() {
+			result.items[item] = sentinel
+		}
+	}
+	return result
+}
+
+// IntersectInto stores the intersection of cols into dst, replacing the
+// existing elements of dst. The intersection of no collections is empty.
+//
+// dst may also be one of cols.
+//
+// Returns the number of elements in dst.
+func IntersectInto[T any](dst Collection[T], cols ...Collection[T]) int {
+	return store(dst, cols, func(yield func(T) bool) {
+		if len(cols) == 0 {
+			return
+		}
+		smallest := slices.MinFunc(cols, func(a, b Collection[T]) int {
+			return cmp.Compare(a.Size(), b.Size())
+		})
+		for item := range smallest.Items() {
+			if containsAll(cols, item) && !yield(item) {
+				return
+			}
+		}
+	})
+}
+
+// DifferenceInto stores the elements of col that are not in any of others into
+// dst, replacing the existing elements of dst.
+//
+// dst may also be col or one of others.
+//
+// Returns the number of elements in dst.
+func DifferenceInto[T any](dst, col Collection[T], others ...Collection[T]) int {
+	return store(dst, append([]Collection[T]{col}, others...), func(yield func(T) bool) {
+		for item := range col.Items() {
+			if !containsAny(others, item) && !yield(item) {
+				return
+			}
+		}
+	})
+}
+
+// Op is a set operation performed by CombineInto.
+type Op int
+
+const (
+	// OpUnion combines operands into their union, as with UnionInto.
+	OpUnion Op = iota + 1
+
+	// OpIntersect combines operands into their intersection, as with
+	// IntersectInto.
+	OpIntersect
+
+	// OpDifference combines operands into the elements of the first that
+	// are not in any of the rest, as with DifferenceInto.
+	OpDifference
+)
+
+// String returns the name of o, e.g. "union".
+func (o Op) String() string {
+	switch o {
+	case OpUnion:
+		return "union"
+	case OpIntersect:
+		return "intersect"
+	case OpDifference:
+		return "difference"
+	default:
+		return fmt.Sprintf("Op(%d)", int(o))
+	}
+}
+
+// CombineInto stores the result of applying op to operands into dst,
+// replacing the existing elements of dst. The result is inserted directly
+// into dst as it is computed, so the caller chooses its representation, e.g.
+// storing the intersection of two HashSets into a TreeSet for ordered output,
+// without first computing the result and then converting it.
+//
+// The difference of no operands is empty. dst may also be one of operands.
+//
+// Returns the number of elements in dst. Panics if op is not a known Op.
+func CombineInto[T any](dst Collection[T], op Op, operands ...Collection[T]) int {
+	switch op {
+	case OpUnion:
+		return UnionInto(dst, operands...)
+	case OpIntersect:
+		return IntersectInto(dst, operands...)
+	case OpDifference:
+		if len(operands) == 0 {
+			clearCollection(dst)
+			return 0
+		}
+		return DifferenceInto(dst, operands[0], operands[1:]...)
+	default:
+		panic(fmt.Sprintf("set: CombineInto with unknown %v", op))
+	}
+}
+
+// store replaces the elements of dst with those of result, which is computed
+// from inputs.
+func store[T any](dst Collection[T], inputs []Collection[T], result iter.Seq[T]) int {
+	if slices.Contains(inputs, dst) {
+		// dst is an input, so result must be computed before dst is cleared
+		items := slices.Collect(result)
+		clearCollection(dst)
+		dst.InsertSlice(items)
+		return dst.Size()
+	}
+
+	clearCollection(dst)
+	for item := range result {
+		dst.Insert(item)
+	}
+	return dst.Size()
+}
+
+// clearCollection removes every element from col, using its Clear method if
+// it has one.
+func clearCollection[T any](col Collection[T]) {
+	if c, ok := col.(interface{ Clear() }); ok {
+		c.Clear()
+		return
+	}
+	col.RemoveSlice(col.Slice())
+}
+
+func containsAll[T any](cols []Collection[T], item T) bool {
+	for _, col := range cols {
+		if !col.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAny[T any](cols []Collection[T], item T) bool {
+	for _, col := range cols {
+		if col.Contains(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// ctxCheckInterval is the number of elements processed by a context-aware
+// operation between checks for cancellation of its context.
+const ctxCheckInterval = 1 << 12
+
+// checkpoint periodically checks whether a context has been canceled.
+type checkpoint struct {
+	ctx   context.Context
+	count int
+}
+
+// check returns an error wrapping ErrPartialResult and the error of the
+// context, if it has been canceled. The context is only consulted once every
+// ctxCheckInterval calls.
+func (c *checkpoint) check() error {
+	c.count++
+	if c.count%ctxCheckInterval != 1 {
+		return nil
+	}
+	if err := c.ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrPartialResult, err)
+	}
+	return nil
+}
+
+func insertCtx[T any](ctx context.Context, destination Collection[T], cols ...Collection[T]) error {
+	c := checkpoint{ctx: ctx}
+	for _, col := range cols {
+		for item := range col.Items() {
+			if err := c.check(); err != nil {
+				return err
+			}
+			destination.Insert(item)
+		}
+	}
+	return nil
+}
+
+func differenceCtx[T any](ctx context.Context, destination, a, b Collection[T]) error {
+	c := checkpoint{ctx: ctx}
+	for item := range a.Items() {
+		if err := c.check(); err != nil {
+			return err
+		}
+		if !b.Contains(item) {
+			destination.Insert(item)
+		}
+	}
+	return nil
+}
+
+func intersectCtx[T any](ctx context.Context, destination, a, b Collection[T]) error {
+	big, small := a, b
+	if a.Size() < b.Size() {
+		big, small = b, a
+	}
+	c := checkpoint{ctx: ctx}
+	for item := range small.Items() {
+		if err := c.check(); err != nil {
+			return err
+		}
+		if big.Contains(item) {
+			destination.Insert(item)
+		}
+	}
+	return nil
+}
+
 func insert[T any](destination, col Collection[T]) {
 	for item := range col.Items() {
 		destination.Insert(item)
@@ -182,6 +592,28 @@ func intersect[T any](destination, a, b Collection[T]) {
 	}
 }
 
+func insertFunc[T any](destination Collection[T], predicate func(T) bool, cols ...Collection[T]) {
+	for _, col := range cols {
+		for item := range col.Items() {
+			if predicate(item) {
+				destination.Insert(item)
+			}
+		}
+	}
+}
+
+func intersectFunc[T any](destination, a, b Collection[T], predicate func(T) bool) {
+	big, small := a, b
+	if a.Size() < b.Size() {
+		big, small = b, a
+	}
+	for item := range small.Items() {
+		if predicate(item) && big.Contains(item) {
+			destination.Insert(item)
+		}
+	}
+}
+
 func containsSlice[T any](col Collection[T], items []T) bool {
 	for _, item := range items {
 		if !col.Contains(item) {
@@ -191,6 +623,48 @@ func containsSlice[T any](col Collection[T], items []T) bool {
 	return true
 }
 
+func missingFrom[T any](col Collection[T], items []T) []T {
+	result := make([]T, 0)
+	for _, item := range items {
+		if !col.Contains(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// classifySlice partitions items by membership of col, using the empty set
+// seen to detect repeated items.
+func classifySlice[T any](col, seen Collection[T], items []T) (present, absent, duplicates []T) {
+	present, absent, duplicates = make([]T, 0), make([]T, 0), make([]T, 0)
+	for _, item := range items {
+		switch {
+		case !seen.Insert(item):
+			duplicates = append(duplicates, item)
+		case col.Contains(item):
+			present = append(present, item)
+		default:
+			absent = append(absent, item)
+		}
+	}
+	return present, absent, duplicates
+}
+
+// equalSliceSet returns whether col and items contain exactly the same
+// elements, where seen is an empty collection used to detect duplicates in
+// items, which cause a false result.
+func equalSliceSet[T any](col, seen Collection[T], items []T) bool {
+	if len(items) != col.Size() {
+		return false
+	}
+	for _, item := range items {
+		if !col.Contains(item) || !seen.Insert(item) {
+			return false
+		}
+	}
+	return true
+}
+
 func equalSet[T any](a, b Collection[T]) bool {
 	// fast paths: sets are empty or different sizes
 	sizeA, sizeB := a.Size(), b.Size()
@@ -210,6 +684,16 @@ func equalSet[T any](a, b Collection[T]) bool {
 	return true
 }
 
+// contentHash combines the mixed hash of each element of col by addition,
+// which does not depend on the order in which the elements are iterated.
+func contentHash[T any](col Collection[T], hash func(T) uint64) uint64 {
+	var sum uint64
+	for item := range col.Items() {
+		sum += mix(hash(item))
+	}
+	return mix(sum + uint64(col.Size()))
+}
+
 func removeSet[T any](s, col Collection[T]) bool {
 	modified := false
 	for item := range col.Items() {
@@ -230,6 +714,20 @@ func removeFunc[T any](col Collection[T], predicate func(T) bool) bool {
 	return col.RemoveSlice(remove)
 }
 
+func removeFuncN[T any](col Collection[T], predicate func(T) bool, n int) int {
+	remove := make([]T, 0, min(max(n, 0), col.Size()))
+	for item := range col.Items() {
+		if len(remove) == cap(remove) {
+			break
+		}
+		if predicate(item) {
+			remove = append(remove, item)
+		}
+	}
+	col.RemoveSlice(remove)
+	return len(remove)
+}
+
 func subset[T any](a, b Collection[T]) bool {
 	if b.Size() > a.Size() {
 		return false
@@ -243,3 +741,10 @@ func subset[T any](a, b Collection[T]) bool {
 
 	return true
 }
+
+// checkSplitN panics if k is not a valid number of sets for SplitN.
+func checkSplitN(k int) {
+	if k < 1 {
+		panic(fmt.Sprintf("set: SplitN into %d sets, must be at least 1", k))
+	}
+}
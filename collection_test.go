@@ -5,6 +5,8 @@ package set
 
 import (
 	"cmp"
+	"context"
+	"slices"
 	"sort"
 	"strconv"
 	"testing"
@@ -77,6 +79,37 @@ func TestSliceFunc(t *testing.T) {
 	})
 }
 
+func TestSortedSlice(t *testing.T) {
+	must.SliceEmpty(t, SortedSlice[int](New[int](0)))
+	must.Eq(t, []int{1, 2, 3, 4}, SortedSlice[int](From([]int{3, 1, 4, 2})))
+	must.Eq(t, []string{"a", "b", "c"}, SortedSlice[string](HashSetFromFunc([]string{"c", "a", "b"}, func(s string) string {
+		return s
+	})))
+}
+
+func TestToMap(t *testing.T) {
+	s := TreeSetFrom([]int{1, 2, 3}, cmp.Compare[int])
+	result := ToMap[int](s, func(element int) (string, int) {
+		return strconv.Itoa(element), element * element
+	})
+	must.Eq(t, map[string]int{"1": 1, "2": 4, "3": 9}, result)
+
+	// colliding keys keep the value of the last element
+	result = ToMap[int](s, func(element int) (string, int) {
+		return "key", element
+	})
+	must.Eq(t, map[string]int{"key": 3}, result)
+
+	must.MapEmpty(t, ToMap[int](New[int](0), func(element int) (int, int) {
+		return element, element
+	}))
+}
+
+func TestToMapKeys(t *testing.T) {
+	must.Eq(t, map[string]struct{}{"a": {}, "b": {}}, ToMapKeys[string](Of("a", "b")))
+	must.MapEmpty(t, ToMapKeys[int](New[int](0)))
+}
+
 func TestInsertSetFunc(t *testing.T) {
 	t.Run("set", func(t *testing.T) {
 		a := From(ints(3))
@@ -245,3 +278,498 @@ func TestEqualSet(t *testing.T) {
 		must.False(t, a.EqualSet(b))
 	})
 }
+
+func TestEqualSet_implementations(t *testing.T) {
+	identity := func(i int) int { return i }
+	create := map[string]func([]int) Collection[int]{
+		"set":     func(items []int) Collection[int] { return From(items) },
+		"hashset": func(items []int) Collection[int] { return HashSetFromFunc(items, identity) },
+		"treeset": func(items []int) Collection[int] { return TreeSetFrom(items, cmp.Compare[int]) },
+		"compact": func(items []int) Collection[int] { return CompactTreeSetFrom(items, cmp.Compare[int]) },
+		"skipset": func(items []int) Collection[int] { return SkipSetFrom(items, cmp.Compare[int]) },
+	}
+
+	cases := []struct {
+		name  string
+		a, b  []int
+		equal bool
+	}{
+		{name: "empty", a: nil, b: []int{}, equal: true},
+		{name: "same", a: []int{1, 2, 3}, b: []int{3, 2, 1}, equal: true},
+		{name: "duplicates", a: []int{1, 2, 2, 3}, b: []int{3, 3, 2, 1}, equal: true},
+		{name: "subset", a: []int{1, 2}, b: []int{1, 2, 3}, equal: false},
+		{name: "different", a: []int{1, 2, 3}, b: []int{1, 2, 4}, equal: false},
+	}
+
+	for nameA, createA := range create {
+		for nameB, createB := range create {
+			for _, tc := range cases {
+				a, b := createA(tc.a), createB(tc.b)
+				must.Eq(t, tc.equal, a.EqualSet(b), must.Sprintf("%s vs %s: %s", nameA, nameB, tc.name))
+				must.Eq(t, tc.equal, b.EqualSet(a), must.Sprintf("%s vs %s: %s", nameB, nameA, tc.name))
+			}
+		}
+	}
+}
+
+func TestSliceComparisons(t *testing.T) {
+	type sliceComparer interface {
+		ContainsSlice([]int) bool
+		EqualSlice([]int) bool
+		EqualSliceSet([]int) bool
+	}
+
+	items := []int{1, 2, 3}
+	create := map[string]sliceComparer{
+		"set":     From(items),
+		"hashset": HashSetFromFunc(items, func(i int) int { return i }),
+		"treeset": TreeSetFrom(items, cmp.Compare[int]),
+		"compact": CompactTreeSetFrom(items, cmp.Compare[int]),
+		"skipset": SkipSetFrom(items, cmp.Compare[int]),
+	}
+
+	cases := []struct {
+		name          string
+		items         []int
+		containsSlice bool
+		equalSlice    bool
+		equalSliceSet bool
+	}{
+		{name: "empty", items: nil, containsSlice: true},
+		{name: "subset", items: []int{3, 1}, containsSlice: true},
+		{name: "equal", items: []int{3, 1, 2}, containsSlice: true, equalSlice: true, equalSliceSet: true},
+		{name: "duplicates", items: []int{1, 2, 2, 3}, containsSlice: true, equalSlice: true},
+		{name: "same size duplicates", items: []int{1, 1, 2}, containsSlice: true},
+		{name: "superset", items: []int{1, 2, 3, 4}},
+		{name: "different", items: []int{1, 2, 4}},
+	}
+
+	for name, s := range create {
+		for _, tc := range cases {
+			label := must.Sprintf("%s: %s", name, tc.name)
+			must.Eq(t, tc.containsSlice, s.ContainsSlice(tc.items), label)
+			must.Eq(t, tc.equalSlice, s.EqualSlice(tc.items), label)
+			must.Eq(t, tc.equalSliceSet, s.EqualSliceSet(tc.items), label)
+		}
+	}
+}
+
+func TestMissingFrom(t *testing.T) {
+	type missingFrom interface {
+		MissingFrom([]int) []int
+	}
+
+	items := []int{1, 2, 3}
+	cases := map[string]missingFrom{
+		"set":        From(items),
+		"hashset":    HashSetFromFunc(items, func(i int) int { return i }),
+		"treeset":    TreeSetFrom(items, cmp.Compare[int]),
+		"compact":    CompactTreeSetFrom(items, cmp.Compare[int]),
+		"skipset":    SkipSetFrom(items, cmp.Compare[int]),
+		"readmostly": ReadMostlySetFrom(items),
+	}
+
+	for name, s := range cases {
+		t.Run(name, func(t *testing.T) {
+			must.Eq(t, []int{}, s.MissingFrom(nil))
+			must.Eq(t, []int{}, s.MissingFrom([]int{3, 1}))
+			must.Eq(t, []int{9, 4, 9}, s.MissingFrom([]int{9, 1, 4, 2, 9}))
+		})
+	}
+}
+
+func TestClassifySlice(t *testing.T) {
+	type classifySlice interface {
+		ClassifySlice([]int) ([]int, []int, []int)
+	}
+
+	items := []int{1, 2, 3}
+	cases := map[string]classifySlice{
+		"set":        From(items),
+		"hashset":    HashSetFromFunc(items, func(i int) int { return i }),
+		"treeset":    TreeSetFrom(items, cmp.Compare[int]),
+		"compact":    CompactTreeSetFrom(items, cmp.Compare[int]),
+		"skipset":    SkipSetFrom(items, cmp.Compare[int]),
+		"readmostly": ReadMostlySetFrom(items),
+	}
+
+	for name, s := range cases {
+		t.Run(name, func(t *testing.T) {
+			present, absent, duplicates := s.ClassifySlice(nil)
+			must.Eq(t, []int{}, present)
+			must.Eq(t, []int{}, absent)
+			must.Eq(t, []int{}, duplicates)
+
+			present, absent, duplicates = s.ClassifySlice([]int{9, 3, 1, 4, 3, 9, 9, 2})
+			must.Eq(t, []int{3, 1, 2}, present)
+			must.Eq(t, []int{9, 4}, absent)
+			must.Eq(t, []int{3, 9, 9}, duplicates)
+		})
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	type contentHash interface {
+		ContentHash(func(int) uint64) uint64
+	}
+	hash := func(i int) uint64 { return uint64(i) }
+
+	items := shuffle(ints(100))
+	cases := map[string]contentHash{
+		"set":        From(items),
+		"hashset":    HashSetFromFunc(items, func(i int) int { return i }),
+		"treeset":    TreeSetFrom(items, cmp.Compare[int]),
+		"compact":    CompactTreeSetFrom(items, cmp.Compare[int]),
+		"skipset":    SkipSetFrom(items, cmp.Compare[int]),
+		"readmostly": ReadMostlySetFrom(items),
+	}
+
+	expected := From(ints(100)).ContentHash(hash)
+	for name, s := range cases {
+		t.Run(name, func(t *testing.T) {
+			must.Eq(t, expected, s.ContentHash(hash))
+		})
+	}
+
+	// different elements, including with the same sum of hashes
+	must.NotEq(t, expected, From(ints(99)).ContentHash(hash))
+	must.NotEq(t, From([]int{1, 4}).ContentHash(hash), From([]int{2, 3}).ContentHash(hash))
+	must.NotEq(t, New[int](0).ContentHash(hash), From([]int{0}).ContentHash(hash))
+}
+
+func TestCapabilities(t *testing.T) {
+	isOrdered := func(col Collection[int]) bool {
+		_, ok := col.(OrderedCollection[int])
+		return ok
+	}
+	isConcurrent := func(col Collection[int]) bool {
+		_, ok := col.(ConcurrentCollection[int])
+		return ok
+	}
+
+	cases := []struct {
+		name       string
+		col        Collection[int]
+		ordered    bool
+		concurrent bool
+	}{
+		{name: "set", col: New[int](0)},
+		{name: "hashset", col: NewHashSetFunc(0, func(i int) int { return i })},
+		{name: "treeset", col: NewTreeSet(cmp.Compare[int]), ordered: true},
+		{name: "compact", col: NewCompactTreeSet(cmp.Compare[int]), ordered: true},
+		{name: "skipset", col: NewSkipSet(cmp.Compare[int]), ordered: true, concurrent: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.ordered, isOrdered(tc.col))
+			must.Eq(t, tc.concurrent, isConcurrent(tc.col))
+		})
+	}
+
+	reversed := func(a, b int) int { return cmp.Compare(b, a) }
+	ordered := Collection[int](NewTreeSet(reversed)).(OrderedCollection[int])
+	must.Positive(t, ordered.Compare(1, 2))
+}
+
+func TestSnapshot(t *testing.T) {
+	cases := []struct {
+		name string
+		col  Collection[int]
+	}{
+		{name: "set", col: From(ints(size))},
+		{name: "hashset", col: HashSetFromFunc(ints(size), func(i int) int { return i })},
+		{name: "treeset", col: TreeSetFrom(ints(size), cmp.Compare[int])},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seen := make([]int, 0, size)
+			for item := range Snapshot(tc.col) {
+				seen = append(seen, item)
+				tc.col.Remove(item)
+				tc.col.Remove(item + 1)
+				tc.col.Insert(-item)
+			}
+			sort.Ints(seen)
+			must.Eq(t, ints(size), seen)
+			must.Size(t, size, tc.col)
+			must.True(t, tc.col.Contains(-1))
+			must.True(t, tc.col.Contains(-size))
+		})
+	}
+
+	t.Run("stop", func(t *testing.T) {
+		count := 0
+		for range Snapshot[int](From(ints(10))) {
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		must.Eq(t, 3, count)
+	})
+}
+
+func TestIntersectBy(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	users := TreeSetFrom([]user{{1, "anna"}, {2, "bill"}, {3, "carl"}}, func(a, b user) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	ids := From([]string{"2", "3", "4"})
+
+	byID := func(u user) string { return strconv.Itoa(u.ID) }
+	identity := func(s string) string { return s }
+
+	result := IntersectBy[user, string](users, ids, byID, identity)
+	must.Eq(t, []user{{2, "bill"}, {3, "carl"}}, result)
+
+	result = IntersectBy[user, string](users, New[string](0), byID, identity)
+	must.SliceEmpty(t, result)
+}
+
+func TestDifferenceBy(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	users := TreeSetFrom([]user{{1, "anna"}, {2, "bill"}, {3, "carl"}}, func(a, b user) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	ids := From([]string{"2", "3", "4"})
+
+	byID := func(u user) string { return strconv.Itoa(u.ID) }
+	identity := func(s string) string { return s }
+
+	result := DifferenceBy[user, string](users, ids, byID, identity)
+	must.Eq(t, []user{{1, "anna"}}, result)
+
+	result = DifferenceBy[user, string](users, New[string](0), byID, identity)
+	must.Eq(t, users.Slice(), result)
+}
+
+func TestUnionInto(t *testing.T) {
+	a := From([]int{1, 2, 3})
+	b := TreeSetFrom([]int{3, 4}, cmp.Compare[int])
+
+	dst := From([]int{9})
+	must.Eq(t, 4, UnionInto[int](dst, a, b))
+	must.Eq(t, From([]int{1, 2, 3, 4}), dst)
+
+	// dst is also an input
+	must.Eq(t, 4, UnionInto[int](b, a, b))
+	must.Eq(t, []int{1, 2, 3, 4}, b.Slice())
+
+	must.Eq(t, 0, UnionInto[int](dst))
+	must.Empty(t, dst)
+}
+
+func TestUnionIter(t *testing.T) {
+	cols := []Collection[int]{
+		From([]int{1, 2, 3}),
+		TreeSetFrom([]int{3, 4}, cmp.Compare[int]),
+		HashSetFromFunc([]int{4, 5, 1}, func(i int) int { return i }),
+	}
+	result := UnionIter(slices.Values(cols))
+	must.Eq(t, From([]int{1, 2, 3, 4, 5}), result)
+
+	empty := UnionIter(slices.Values([]Collection[int]{}))
+	must.Empty(t, empty)
+}
+
+func TestIntersectInto(t *testing.T) {
+	a := From([]int{1, 2, 3, 4})
+	b := HashSetFromFunc([]int{2, 3, 4, 5}, func(i int) int { return i })
+	c := TreeSetFrom([]int{3, 4, 5, 6}, cmp.Compare[int])
+
+	dst := NewTreeSet[int](cmp.Compare[int])
+	must.Eq(t, 2, IntersectInto[int](dst, a, b, c))
+	must.Eq(t, []int{3, 4}, dst.Slice())
+
+	// dst is reused, replacing the previous result
+	must.Eq(t, 3, IntersectInto[int](dst, a, b))
+	must.Eq(t, []int{2, 3, 4}, dst.Slice())
+
+	// dst is also an input
+	must.Eq(t, 2, IntersectInto[int](a, a, c))
+	must.Eq(t, From([]int{3, 4}), a)
+
+	must.Eq(t, 0, IntersectInto[int](dst))
+	must.Empty(t, dst)
+}
+
+func TestDifferenceInto(t *testing.T) {
+	a := From([]int{1, 2, 3, 4, 5})
+	b := From([]int{2})
+	c := From([]int{4, 6})
+
+	dst := New[int](10)
+	must.Eq(t, 3, DifferenceInto[int](dst, a, b, c))
+	must.Eq(t, From([]int{1, 3, 5}), dst)
+
+	must.Eq(t, 5, DifferenceInto[int](dst, a))
+	must.Eq(t, a, dst)
+
+	// dst is also an input
+	must.Eq(t, 1, DifferenceInto[int](c, c, a))
+	must.Eq(t, From([]int{6}), c)
+}
+
+func TestCombineInto(t *testing.T) {
+	a := HashSetFromFunc([]int{1, 2, 3, 4}, func(i int) int { return i })
+	b := HashSetFromFunc([]int{5, 4, 3}, func(i int) int { return i })
+
+	dst := NewTreeSet[int](cmp.Compare[int])
+	must.Eq(t, 5, CombineInto[int](dst, OpUnion, a, b))
+	must.Eq(t, []int{1, 2, 3, 4, 5}, dst.Slice())
+
+	must.Eq(t, 2, CombineInto[int](dst, OpIntersect, a, b))
+	must.Eq(t, []int{3, 4}, dst.Slice())
+
+	must.Eq(t, 2, CombineInto[int](dst, OpDifference, a, b))
+	must.Eq(t, []int{1, 2}, dst.Slice())
+
+	must.Eq(t, 0, CombineInto[int](dst, OpDifference))
+	must.Empty(t, dst)
+
+	// dst is also an operand
+	must.Eq(t, 1, CombineInto[int](b, OpDifference, b, a))
+	must.Eq(t, []int{5}, b.Slice())
+
+	must.Eq(t, "intersect", OpIntersect.String())
+	must.Eq(t, "Op(9)", Op(9).String())
+
+	defer func() {
+		must.Eq(t, "set: CombineInto with unknown Op(0)", recover())
+	}()
+	CombineInto[int](dst, Op(0), a)
+}
+
+func TestClear(t *testing.T) {
+	cases := []struct {
+		name string
+		col  Collection[int]
+	}{
+		{name: "set", col: From(ints(size))},
+		{name: "hashset", col: HashSetFromFunc(ints(size), func(i int) int { return i })},
+		{name: "treeset", col: TreeSetFrom(ints(size), cmp.Compare[int])},
+		{name: "compacttreeset", col: CompactTreeSetFrom(ints(size), cmp.Compare[int])},
+		{name: "skipset", col: SkipSetFrom(ints(size), cmp.Compare[int])},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clearCollection(tc.col)
+			must.Empty(t, tc.col)
+			must.True(t, tc.col.Insert(1))
+			must.Eq(t, []int{1}, tc.col.Slice())
+		})
+	}
+}
+
+func TestCtx(t *testing.T) {
+	type ctxCollection interface {
+		Collection[int]
+		UnionCtx(context.Context, Collection[int]) (Collection[int], error)
+		DifferenceCtx(context.Context, Collection[int]) (Collection[int], error)
+		IntersectCtx(context.Context, Collection[int]) (Collection[int], error)
+	}
+
+	cases := []struct {
+		name  string
+		col   ctxCollection
+		other Collection[int]
+	}{
+		{name: "set", col: From(ints(size)), other: From(ints(size * 2)[size/2:])},
+		{name: "hashset", col: HashSetFromFunc(ints(size), func(i int) int { return i }), other: From(ints(size * 2)[size/2:])},
+		{name: "treeset", col: TreeSetFrom(ints(size), cmp.Compare[int]), other: TreeSetFrom(ints(size * 2)[size/2:], cmp.Compare[int])},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			union, err := tc.col.UnionCtx(context.Background(), tc.other)
+			must.NoError(t, err)
+			must.True(t, union.EqualSet(tc.col.Union(tc.other)))
+
+			difference, err := tc.col.DifferenceCtx(context.Background(), tc.other)
+			must.NoError(t, err)
+			must.True(t, difference.EqualSet(tc.col.Difference(tc.other)))
+
+			intersect, err := tc.col.IntersectCtx(context.Background(), tc.other)
+			must.NoError(t, err)
+			must.True(t, intersect.EqualSet(tc.col.Intersect(tc.other)))
+		})
+
+		t.Run(tc.name+" canceled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			union, err := tc.col.UnionCtx(ctx, tc.other)
+			must.ErrorIs(t, err, ErrPartialResult)
+			must.ErrorIs(t, err, context.Canceled)
+			must.NotNil(t, union)
+
+			_, err = tc.col.DifferenceCtx(ctx, tc.other)
+			must.ErrorIs(t, err, context.Canceled)
+
+			_, err = tc.col.IntersectCtx(ctx, tc.other)
+			must.ErrorIs(t, err, context.Canceled)
+		})
+	}
+}
+
+func TestFunc(t *testing.T) {
+	type funcCollection interface {
+		Collection[int]
+		UnionFunc(Collection[int], func(int) bool) Collection[int]
+		IntersectFunc(Collection[int], func(int) bool) Collection[int]
+	}
+
+	even := func(i int) bool { return i%2 == 0 }
+
+	cases := []struct {
+		name  string
+		col   funcCollection
+		other Collection[int]
+	}{
+		{name: "set", col: From(ints(10)), other: From(ints(20)[5:])},
+		{name: "hashset", col: HashSetFromFunc(ints(10), func(i int) int { return i }), other: From(ints(20)[5:])},
+		{name: "treeset", col: TreeSetFrom(ints(10), cmp.Compare[int]), other: TreeSetFrom(ints(20)[5:], cmp.Compare[int])},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			union := tc.col.UnionFunc(tc.other, even)
+			must.True(t, union.EqualSlice([]int{2, 4, 6, 8, 10, 12, 14, 16, 18, 20}))
+
+			intersect := tc.col.IntersectFunc(tc.other, even)
+			must.True(t, intersect.EqualSlice([]int{6, 8, 10}))
+
+			none := tc.col.IntersectFunc(tc.other, func(int) bool { return false })
+			must.Empty(t, none)
+		})
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := checkpoint{ctx: ctx}
+	must.NoError(t, c.check())
+	cancel()
+
+	// cancellation is only noticed at the next interval
+	for i := 1; i < ctxCheckInterval; i++ {
+		must.NoError(t, c.check())
+	}
+	must.ErrorIs(t, c.check(), context.Canceled)
+}
+
+// recovered returns the value with which f panics, or nil if f returns.
+func recovered(f func()) (r any) {
+	defer func() { r = recover() }()
+	f()
+	return nil
+}
@@ -0,0 +1,716 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"slices"
+
+	"github.com/hashicorp/go-set/v3/stack"
+)
+
+// CompactTreeSet provides a generic sortable set implementation for Go, which
+// trades some performance for reduced memory usage compared to TreeSet.
+//
+// The underlying data structure is a Left-Leaning Red-Black Binary Search Tree.
+// Nodes are stored contiguously in a slice, referring to their children by
+// int32 index rather than by pointer, and do not store parent pointers or
+// subtree sizes. This reduces the overhead per element (e.g. 48 down to 24
+// bytes per int element), avoids an allocation per element, and improves the
+// locality of searching the tree. The cost is that insertions and deletions
+// are implemented recursively and do slightly more work rebalancing the tree,
+// that order statistics such as CountRange are not available, and that a
+// CompactTreeSet is limited to math.MaxInt32-1 elements.
+//
+// The storage of removed elements is reused by later insertions, and is only
+// released by Compact.
+//
+// https://en.wikipedia.org/wiki/Left-leaning_red–black_tree
+//
+// Prefer TreeSet unless the set contains many millions of elements and memory
+// usage or the speed of Contains is a concern.
+//
+// Not thread safe, and not safe for concurrent modification. Multiple goroutines
+// may read from a CompactTreeSet concurrently as long as no goroutine is
+// modifying it. Modifying a CompactTreeSet while iterating its elements is not
+// supported, and is detected when built with -tags setdebug.
+type CompactTreeSet[T any] struct {
+	guard      guard
+	comparison CompareFunc[T]
+	nodes      []compactNode[T] // index 0 is reserved to mean no node
+	root       int32
+	free       int32 // first of the removed nodes, linked by their left index
+	size       int
+}
+
+// NewCompactTreeSet creates a CompactTreeSet of type T, comparing elements via
+// a given CompareFunc[T].
+//
+// T may be any type.
+func NewCompactTreeSet[T any](compare CompareFunc[T]) *CompactTreeSet[T] {
+	return &CompactTreeSet[T]{
+		comparison: compare,
+	}
+}
+
+// CompactTreeSetFrom creates a new CompactTreeSet containing each item in items.
+//
+// T may be any type.
+func CompactTreeSetFrom[T any](items []T, compare CompareFunc[T]) *CompactTreeSet[T] {
+	s := NewCompactTreeSet[T](compare)
+	s.nodes = make([]compactNode[T], 1, len(items)+1)
+	s.InsertSlice(items)
+	return s
+}
+
+// Insert item into s.
+//
+// Returns true if s was modified (item was not already in s), false otherwise.
+func (s *CompactTreeSet[T]) Insert(item T) bool {
+	s.guard.check("insert")
+
+	root, added := s.insert(s.root, item)
+	s.root = root
+	s.nodes[root].color = black
+	if added {
+		s.size++
+	}
+	return added
+}
+
+// InsertSlice will insert each item in items into s.
+//
+// Return true if s was modified (at least one item was not already in s), false otherwise.
+func (s *CompactTreeSet[T]) InsertSlice(items []T) bool {
+	modified := false
+	for _, item := range items {
+		if s.Insert(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// InsertSet will insert each element of col into s.
+//
+// Return true if s was modified (at least one item of col was not already in s), false otherwise.
+func (s *CompactTreeSet[T]) InsertSet(col Collection[T]) bool {
+	modified := false
+	for item := range col.Items() {
+		if s.Insert(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// Remove item from s.
+//
+// Returns true if s was modified (item was in s), false otherwise.
+func (s *CompactTreeSet[T]) Remove(item T) bool {
+	s.guard.check("remove")
+
+	if !s.Contains(item) {
+		return false
+	}
+
+	if s.black(s.nodes[s.root].left) && s.black(s.nodes[s.root].right) {
+		s.nodes[s.root].color = red
+	}
+	s.root = s.delete(s.root, item)
+	if s.root != 0 {
+		s.nodes[s.root].color = black
+	}
+	s.size--
+	return true
+}
+
+// RemoveSlice will remove each item in items from s.
+//
+// Return true if s was modified (any item was in s), false otherwise.
+func (s *CompactTreeSet[T]) RemoveSlice(items []T) bool {
+	modified := false
+	for _, item := range items {
+		if s.Remove(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// RemoveSet will remove each element in col from s.
+//
+// Returns true if s was modified (at least one item in col was in s), false otherwise.
+func (s *CompactTreeSet[T]) RemoveSet(col Collection[T]) bool {
+	return removeSet(s, col)
+}
+
+// RemoveFunc will remove each element from s that satisfies condition f.
+//
+// Return true if s was modified, false otherwise.
+func (s *CompactTreeSet[T]) RemoveFunc(f func(T) bool) bool {
+	return removeFunc(s, f)
+}
+
+// RemoveFuncN will remove up to n elements from s that satisfy condition f,
+// e.g. for bounding the work done by each pass of a periodic purge of s.
+//
+// Elements are considered in order, and so the smallest n elements which
+// satisfy f are removed.
+//
+// Returns the number of elements removed.
+func (s *CompactTreeSet[T]) RemoveFuncN(f func(T) bool, n int) int {
+	return removeFuncN(s, f, n)
+}
+
+// Min returns the smallest item in s.
+//
+// Must not be called on an empty set; panics with an error wrapping
+// ErrEmptySet if s is empty. Use TryMin if s may be empty.
+func (s *CompactTreeSet[T]) Min() T {
+	if s.root == 0 {
+		panic(emptyError("min", s))
+	}
+	return s.nodes[s.min(s.root)].element
+}
+
+// Max returns the largest item in s.
+//
+// Must not be called on an empty set; panics with an error wrapping
+// ErrEmptySet if s is empty. Use TryMax if s may be empty.
+func (s *CompactTreeSet[T]) Max() T {
+	if s.root == 0 {
+		panic(emptyError("max", s))
+	}
+	n := s.root
+	for s.nodes[n].right != 0 {
+		n = s.nodes[n].right
+	}
+	return s.nodes[n].element
+}
+
+// TryMin returns the smallest item in s.
+//
+// Returns an error wrapping ErrEmptySet if s is empty.
+func (s *CompactTreeSet[T]) TryMin() (T, error) {
+	if s.root == 0 {
+		var zero T
+		return zero, emptyError("min", s)
+	}
+	return s.Min(), nil
+}
+
+// TryMax returns the largest item in s.
+//
+// Returns an error wrapping ErrEmptySet if s is empty.
+func (s *CompactTreeSet[T]) TryMax() (T, error) {
+	if s.root == 0 {
+		var zero T
+		return zero, emptyError("max", s)
+	}
+	return s.Max(), nil
+}
+
+// Compare compares a and b according to the CompareFunc of s, implementing
+// OrderedCollection.
+func (s *CompactTreeSet[T]) Compare(a, b T) int {
+	return s.comparison(a, b)
+}
+
+// Contains returns whether item is present in s.
+func (s *CompactTreeSet[T]) Contains(item T) bool {
+	nodes, n := s.nodes, s.root
+	for n != 0 {
+		node := &nodes[n]
+		c := s.comparison(item, node.element)
+		switch {
+		case c < 0:
+			n = node.left
+		case c > 0:
+			n = node.right
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsSlice returns whether all elements in items are present in s.
+func (s *CompactTreeSet[T]) ContainsSlice(items []T) bool {
+	return containsSlice(s, items)
+}
+
+// MissingFrom returns the elements of items which are not present in s, e.g. for
+// reporting which of a list of requested identifiers do not exist.
+//
+// The result preserves the order of items, including any duplicates.
+func (s *CompactTreeSet[T]) MissingFrom(items []T) []T {
+	return missingFrom(s, items)
+}
+
+// ClassifySlice partitions items into those present in s, those absent from
+// s, and duplicates (i.e. each repeated occurrence of an item earlier in
+// items), in a single pass, e.g. for reporting which of a list of requested
+// identifiers are already known, which are new, and which were repeated.
+//
+// Each result preserves the order of items. The first occurrence of an item
+// is in either present or absent, and each later occurrence is in duplicates.
+func (s *CompactTreeSet[T]) ClassifySlice(items []T) (present, absent, duplicates []T) {
+	return classifySlice(s, NewTreeSet[T](s.comparison), items)
+}
+
+// Subset returns whether col is a subset of s.
+func (s *CompactTreeSet[T]) Subset(col Collection[T]) bool {
+	return subset(s, col)
+}
+
+// ProperSubset returns whether col is a proper subset of s.
+func (s *CompactTreeSet[T]) ProperSubset(col Collection[T]) bool {
+	if s.Size() <= col.Size() {
+		return false
+	}
+	return s.Subset(col)
+}
+
+// Clear removes every element from s.
+func (s *CompactTreeSet[T]) Clear() {
+	s.guard.check("clear")
+
+	clear(s.nodes)
+	s.nodes = s.nodes[:0]
+	s.root, s.free = 0, 0
+	s.size = 0
+}
+
+// Compact releases the storage of elements removed from s, and rearranges the
+// storage of the remaining elements to improve the locality of searching s,
+// e.g. after removing many elements.
+//
+// The storage of removed elements is otherwise reused by later insertions, and
+// so Compact is only useful if s is not expected to grow again soon.
+func (s *CompactTreeSet[T]) Compact() {
+	s.guard.check("compact")
+
+	if s.size == 0 {
+		s.nodes = nil
+		s.root, s.free = 0, 0
+		return
+	}
+
+	// copy the nodes in pre-order, so that the top levels of the tree, which
+	// every search visits, are stored together
+	nodes := make([]compactNode[T], 1, s.size+1)
+	var move func(n int32) int32
+	move = func(n int32) int32 {
+		if n == 0 {
+			return 0
+		}
+		i := int32(len(nodes))
+		nodes = append(nodes, s.nodes[n])
+		left := move(s.nodes[n].left)
+		right := move(s.nodes[n].right)
+		nodes[i].left, nodes[i].right = left, right
+		return i
+	}
+	s.root = move(s.root)
+	s.nodes = nodes
+	s.free = 0
+}
+
+// Size returns the number of elements in s.
+func (s *CompactTreeSet[T]) Size() int {
+	return s.size
+}
+
+// Empty returns true if there are no elements in s.
+func (s *CompactTreeSet[T]) Empty() bool {
+	return s.Size() == 0
+}
+
+// Union returns a set that contains all elements of s and col combined.
+func (s *CompactTreeSet[T]) Union(col Collection[T]) Collection[T] {
+	result := NewCompactTreeSet[T](s.comparison)
+	insert(result, s)
+	insert(result, col)
+	return result
+}
+
+// Difference returns a set that contains elements of s that are not in col.
+func (s *CompactTreeSet[T]) Difference(col Collection[T]) Collection[T] {
+	result := NewCompactTreeSet[T](s.comparison)
+	for item := range s.Items() {
+		if !col.Contains(item) {
+			result.Insert(item)
+		}
+	}
+	return result
+}
+
+// Intersect returns a set that contains elements that are present in both s and col.
+func (s *CompactTreeSet[T]) Intersect(col Collection[T]) Collection[T] {
+	result := NewCompactTreeSet[T](s.comparison)
+	intersect(result, s, col)
+	return result
+}
+
+// Copy creates a copy of s.
+//
+// Individual elements are reference copies.
+func (s *CompactTreeSet[T]) Copy() *CompactTreeSet[T] {
+	return &CompactTreeSet[T]{
+		comparison: s.comparison,
+		nodes:      slices.Clone(s.nodes),
+		root:       s.root,
+		free:       s.free,
+		size:       s.size,
+	}
+}
+
+// CopyFunc creates a deep copy of s, where each element of the copy is created
+// by passing an element of s through clone.
+//
+// The elements of the copy are ordered anew, and so if clone produces elements
+// which compare as equal for two elements of s, the copy contains fewer
+// elements than s.
+func (s *CompactTreeSet[T]) CopyFunc(clone func(T) T) *CompactTreeSet[T] {
+	result := NewCompactTreeSet[T](s.comparison)
+	for item := range s.Items() {
+		result.Insert(clone(item))
+	}
+	return result
+}
+
+// Slice returns the elements of s as a slice, in order.
+func (s *CompactTreeSet[T]) Slice() []T {
+	return s.AppendSlice(make([]T, 0, s.Size()))
+}
+
+// AppendSlice appends the elements of s to dst and returns the extended slice,
+// e.g. for reusing the same buffer across calls rather than allocating a new
+// slice via Slice each time.
+//
+// Elements are appended in order.
+func (s *CompactTreeSet[T]) AppendSlice(dst []T) []T {
+	dst = slices.Grow(dst, s.Size())
+	for item := range s.Items() {
+		dst = append(dst, item)
+	}
+	return dst
+}
+
+// String creates a string representation of s, using "%v" printf formatting
+// each element into a string. The result contains elements in order.
+func (s *CompactTreeSet[T]) String() string {
+	return s.StringFunc(func(element T) string {
+		return fmt.Sprintf("%v", element)
+	})
+}
+
+// StringFunc creates a string representation of s, using f to transform each
+// element into a string. The result contains elements in order.
+func (s *CompactTreeSet[T]) StringFunc(f func(T) string) string {
+	l := make([]string, 0, s.Size())
+	for item := range s.Items() {
+		l = append(l, f(item))
+	}
+	return fmt.Sprintf("%s", l)
+}
+
+// Equal return whether s and o contain the same elements.
+func (s *CompactTreeSet[T]) Equal(o *CompactTreeSet[T]) bool {
+	if s.Size() != o.Size() {
+		return false
+	}
+	next, stop := iter.Pull(o.Items())
+	defer stop()
+	for item := range s.Items() {
+		other, _ := next()
+		if s.comparison(item, other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ContentHash returns a hash of the elements of s which does not depend on
+// their order, combining the hash of each element as produced by hash, e.g.
+// for cheaply comparing sets of different types or in different processes.
+//
+// Sets containing the same elements have the same content hash regardless of
+// their type or internal layout. Sets containing different elements are
+// unlikely, but not guaranteed, to have different content hashes.
+func (s *CompactTreeSet[T]) ContentHash(hash func(T) uint64) uint64 {
+	return contentHash(s, hash)
+}
+
+// EqualSet returns s and col contain the same elements.
+func (s *CompactTreeSet[T]) EqualSet(col Collection[T]) bool {
+	return equalSet(s, col)
+}
+
+// EqualSlice returns whether s and items contain the same elements.
+//
+// The items slice may contain duplicates.
+//
+// If the items slice is known to contain no duplicates, EqualSliceSet may be
+// used instead as a faster implementation.
+//
+// To detect if a slice is a subset of s, use ContainsSlice.
+func (s *CompactTreeSet[T]) EqualSlice(items []T) bool {
+	other := CompactTreeSetFrom[T](items, s.comparison)
+	return s.Equal(other)
+}
+
+// EqualSliceSet returns whether s and items contain exactly the same elements,
+// where the elements of items are expected to be set-like. If items contains
+// duplicates EqualSliceSet returns false. For comparing s to a slice that may
+// contain duplicate elements, use EqualSlice instead.
+//
+// To detect if a slice is a subset of s, use ContainsSlice.
+func (s *CompactTreeSet[T]) EqualSliceSet(items []T) bool {
+	return equalSliceSet(s, NewCompactTreeSet[T](s.comparison), items)
+}
+
+// Items returns a generator function for iterating each element in s by using
+// the range keyword.
+//
+// The elements of s must not be inserted or removed during iteration. Use
+// Snapshot to iterate s while modifying it.
+//
+//	for element := range s.Items() { ... }
+func (s *CompactTreeSet[T]) Items() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.guard.enter()
+		defer s.guard.exit()
+
+		stck := stack.New[int32]()
+		for n := s.root; n != 0; n = s.nodes[n].left {
+			stck.Push(n)
+		}
+		for !stck.Empty() {
+			n := stck.Pop()
+			if !yield(s.nodes[n].element) {
+				return
+			}
+			for r := s.nodes[n].right; r != 0; r = s.nodes[r].left {
+				stck.Push(r)
+			}
+		}
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *CompactTreeSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[T](s)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// The set must have been created by NewCompactTreeSet, as the zero value has no
+// CompareFunc. Use UnmarshalJSONSets or UnmarshalJSONSetMap for decoding
+// nested sets.
+func (s *CompactTreeSet[T]) UnmarshalJSON(data []byte) error {
+	if s.comparison == nil {
+		return errNoConstructor("CompactTreeSet")
+	}
+	return unmarshalJSON[T](s, data)
+}
+
+// Left-Leaning Red-Black Tree Invariants
+//
+// In addition to the invariants of a Red-Black tree,
+//
+// 1. red nodes are always the left child of their parent
+//
+// which makes the tree isomorphic to a 2-3 tree, and allows insertion and
+// deletion to be implemented without references to parent nodes.
+
+type compactNode[T any] struct {
+	element T
+	left    int32
+	right   int32
+	color   color
+}
+
+func (s *CompactTreeSet[T]) black(n int32) bool {
+	return n == 0 || s.nodes[n].color == black
+}
+
+func (s *CompactTreeSet[T]) red(n int32) bool {
+	return n != 0 && s.nodes[n].color == red
+}
+
+// alloc stores a new red node containing item, reusing the storage of a
+// removed node if there is one, and returns its index.
+func (s *CompactTreeSet[T]) alloc(item T) int32 {
+	if s.free != 0 {
+		n := s.free
+		s.free = s.nodes[n].left
+		s.nodes[n] = compactNode[T]{element: item, color: red}
+		return n
+	}
+	if len(s.nodes) == 0 {
+		s.nodes = append(s.nodes, compactNode[T]{})
+	}
+	if len(s.nodes) > math.MaxInt32 {
+		panic(fmt.Errorf("%w: CompactTreeSet is limited to %d elements", ErrSizeLimit, math.MaxInt32-1))
+	}
+	s.nodes = append(s.nodes, compactNode[T]{element: item, color: red})
+	return int32(len(s.nodes) - 1)
+}
+
+// release adds the removed node n to the free list of s, clearing its element
+// so that it may be garbage collected.
+func (s *CompactTreeSet[T]) release(n int32) {
+	s.nodes[n] = compactNode[T]{left: s.free}
+	s.free = n
+}
+
+// flip inverts the color of n and its children.
+func (s *CompactTreeSet[T]) flip(n int32) {
+	node := &s.nodes[n]
+	node.color = !node.color
+	s.nodes[node.left].color = !s.nodes[node.left].color
+	s.nodes[node.right].color = !s.nodes[node.right].color
+}
+
+func (s *CompactTreeSet[T]) rotateLeft(n int32) int32 {
+	a := &s.nodes[n]
+	x := a.right
+	b := &s.nodes[x]
+	a.right = b.left
+	b.left = n
+	b.color = a.color
+	a.color = red
+	return x
+}
+
+func (s *CompactTreeSet[T]) rotateRight(n int32) int32 {
+	a := &s.nodes[n]
+	x := a.left
+	b := &s.nodes[x]
+	a.left = b.right
+	b.right = n
+	b.color = a.color
+	a.color = red
+	return x
+}
+
+// balance restores the invariants of the subtree rooted at n on the way back
+// up from an insertion or deletion.
+func (s *CompactTreeSet[T]) balance(n int32) int32 {
+	node := &s.nodes[n]
+	if s.red(node.right) && s.black(node.left) {
+		n = s.rotateLeft(n)
+		node = &s.nodes[n]
+	}
+	if s.red(node.left) && s.red(s.nodes[node.left].left) {
+		n = s.rotateRight(n)
+		node = &s.nodes[n]
+	}
+	if s.red(node.left) && s.red(node.right) {
+		s.flip(n)
+	}
+	return n
+}
+
+// insert adds item to the subtree rooted at n, returning the new root of the
+// subtree and whether item was added.
+//
+// Inserting may grow s.nodes, and so the result of a recursive call must be
+// stored into s.nodes only after the call returns.
+func (s *CompactTreeSet[T]) insert(n int32, item T) (int32, bool) {
+	if n == 0 {
+		return s.alloc(item), true
+	}
+
+	c := s.comparison(item, s.nodes[n].element)
+	switch {
+	case c < 0:
+		left, added := s.insert(s.nodes[n].left, item)
+		s.nodes[n].left = left
+		return s.balance(n), added
+	case c > 0:
+		right, added := s.insert(s.nodes[n].right, item)
+		s.nodes[n].right = right
+		return s.balance(n), added
+	default:
+		// already exists in tree
+		return n, false
+	}
+}
+
+// moveRedLeft makes n.left or one of its children red, assuming n is red and
+// both n.left and n.left.left are black.
+func (s *CompactTreeSet[T]) moveRedLeft(n int32) int32 {
+	s.flip(n)
+	if right := s.nodes[n].right; s.red(s.nodes[right].left) {
+		s.nodes[n].right = s.rotateRight(right)
+		n = s.rotateLeft(n)
+		s.flip(n)
+	}
+	return n
+}
+
+// moveRedRight makes n.right or one of its children red, assuming n is red and
+// both n.right and n.right.left are black.
+func (s *CompactTreeSet[T]) moveRedRight(n int32) int32 {
+	s.flip(n)
+	if left := s.nodes[n].left; s.red(s.nodes[left].left) {
+		n = s.rotateRight(n)
+		s.flip(n)
+	}
+	return n
+}
+
+// delete removes item from the subtree rooted at n, which must contain item.
+func (s *CompactTreeSet[T]) delete(n int32, item T) int32 {
+	if s.comparison(item, s.nodes[n].element) < 0 {
+		if left := s.nodes[n].left; s.black(left) && s.black(s.nodes[left].left) {
+			n = s.moveRedLeft(n)
+		}
+		s.nodes[n].left = s.delete(s.nodes[n].left, item)
+		return s.balance(n)
+	}
+
+	if s.red(s.nodes[n].left) {
+		n = s.rotateRight(n)
+	}
+	if s.comparison(item, s.nodes[n].element) == 0 && s.nodes[n].right == 0 {
+		s.release(n)
+		return 0
+	}
+	if right := s.nodes[n].right; s.black(right) && s.black(s.nodes[right].left) {
+		n = s.moveRedRight(n)
+	}
+	if s.comparison(item, s.nodes[n].element) == 0 {
+		// replace n with its successor
+		s.nodes[n].element = s.nodes[s.min(s.nodes[n].right)].element
+		s.nodes[n].right = s.deleteMin(s.nodes[n].right)
+	} else {
+		s.nodes[n].right = s.delete(s.nodes[n].right, item)
+	}
+	return s.balance(n)
+}
+
+func (s *CompactTreeSet[T]) deleteMin(n int32) int32 {
+	if s.nodes[n].left == 0 {
+		s.release(n)
+		return 0
+	}
+	if left := s.nodes[n].left; s.black(left) && s.black(s.nodes[left].left) {
+		n = s.moveRedLeft(n)
+	}
+	s.nodes[n].left = s.deleteMin(s.nodes[n].left)
+	return s.balance(n)
+}
+
+func (s *CompactTreeSet[T]) min(n int32) int32 {
+	for s.nodes[n].left != 0 {
+		n = s.nodes[n].left
+	}
+	return n
+}
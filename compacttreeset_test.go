@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+// compactInvariants makes assertions about the structure of a left-leaning
+// red-black tree
+func compactInvariants[T any](t *testing.T, tree *CompactTreeSet[T]) {
+	slice := tree.Slice()
+	must.AscendingCmp(t, slice, tree.comparison)
+	must.Eq(t, tree.Size(), len(slice), must.Sprint("tree is wrong size"))
+	must.True(t, tree.black(tree.root), must.Sprint("root must be black"))
+
+	var height func(n int32) int
+	height = func(n int32) int {
+		if n == 0 {
+			return 1
+		}
+		node := tree.nodes[n]
+		must.False(t, tree.red(node.right), must.Sprintf("right leaning red node at %v", node.element))
+		must.False(t, tree.red(n) && tree.red(node.left), must.Sprintf("consecutive red nodes at %v", node.element))
+		left, right := height(node.left), height(node.right)
+		must.Eq(t, left, right, must.Sprintf("unbalanced black height at %v", node.element))
+		if tree.black(n) {
+			return left + 1
+		}
+		return left
+	}
+	height(tree.root)
+
+	// every node is either in the tree or free
+	free := 0
+	for n := tree.free; n != 0; n = tree.nodes[n].left {
+		free++
+	}
+	if len(tree.nodes) > 0 {
+		must.Eq(t, len(tree.nodes)-1, tree.Size()+free, must.Sprint("nodes are lost"))
+	}
+}
+
+func TestCompactTreeSet_Insert(t *testing.T) {
+	ts := NewCompactTreeSet[int](cmp.Compare[int])
+	must.Empty(t, ts)
+	compactInvariants(t, ts)
+
+	for _, i := range shuffle(ints(size)) {
+		must.True(t, ts.Insert(i))
+		must.False(t, ts.Insert(i))
+	}
+	compactInvariants(t, ts)
+	must.Size(t, size, ts)
+	must.Eq(t, ints(size), ts.Slice())
+	must.Eq(t, 1, ts.Min())
+	must.Eq(t, size, ts.Max())
+
+	least, err := ts.TryMin()
+	must.NoError(t, err)
+	must.Eq(t, 1, least)
+	_, err = NewCompactTreeSet[int](cmp.Compare[int]).TryMax()
+	must.ErrorIs(t, err, ErrEmptySet)
+}
+
+func TestCompactTreeSet_Remove(t *testing.T) {
+	ts := CompactTreeSetFrom[int](shuffle(ints(size)), cmp.Compare[int])
+	must.False(t, ts.Remove(0))
+
+	for i, item := range shuffle(ints(size)) {
+		must.True(t, ts.Remove(item))
+		must.False(t, ts.Remove(item))
+		must.Size(t, size-i-1, ts)
+		if i%50 == 0 {
+			compactInvariants(t, ts)
+		}
+	}
+	must.Empty(t, ts)
+	compactInvariants(t, ts)
+}
+
+func TestCompactTreeSet_Compact(t *testing.T) {
+	ts := CompactTreeSetFrom[int](shuffle(ints(size)), cmp.Compare[int])
+	must.Eq(t, size+1, len(ts.nodes))
+
+	// removed nodes are reused
+	ts.RemoveSlice(ints(size / 2))
+	must.Eq(t, size+1, len(ts.nodes))
+	ts.InsertSlice(ints(size / 4))
+	must.Eq(t, size+1, len(ts.nodes))
+	compactInvariants(t, ts)
+
+	ts.Compact()
+	must.Eq(t, size/4+size/2+1, len(ts.nodes))
+	must.Eq(t, size/4+size/2+1, cap(ts.nodes))
+	must.Zero(t, ts.free)
+	must.Eq(t, append(ints(size/4), ints(size)[size/2:]...), ts.Slice())
+	compactInvariants(t, ts)
+
+	// the tree remains usable after compacting
+	must.True(t, ts.Insert(0))
+	must.True(t, ts.Remove(size))
+	compactInvariants(t, ts)
+
+	ts.Clear()
+	ts.Compact()
+	must.Nil(t, ts.nodes)
+	must.True(t, ts.Insert(1))
+	compactInvariants(t, ts)
+}
+
+func TestCompactTreeSet_CopyFunc(t *testing.T) {
+	s := CompactTreeSetFrom[int](ints(100), cmp.Compare[int])
+	c := s.CopyFunc(func(i int) int { return -i })
+	compactInvariants(t, c)
+	must.Eq(t, -100, c.Min())
+	must.Eq(t, -1, c.Max())
+	must.Size(t, 100, c)
+}
+
+func TestCompactTreeSet_random(t *testing.T) {
+	ts := NewCompactTreeSet[int](cmp.Compare[int])
+	ref := New[int](0)
+
+	for i := 0; i < 10*size; i++ {
+		item := rand.Intn(size / 4)
+		if rand.Intn(2) == 0 {
+			must.Eq(t, ref.Insert(item), ts.Insert(item))
+		} else {
+			must.Eq(t, ref.Remove(item), ts.Remove(item))
+		}
+	}
+	compactInvariants(t, ts)
+	must.True(t, ts.EqualSet(ref))
+}
+
+func TestCompactTreeSet_Collection(t *testing.T) {
+	a := CompactTreeSetFrom[int]([]int{1, 2, 3, 4, 5}, cmp.Compare[int])
+	b := CompactTreeSetFrom[int]([]int{4, 5, 6, 7}, cmp.Compare[int])
+
+	must.Eq(t, "[1 2 3 4 5 6 7]", a.Union(b).String())
+	must.Eq(t, "[1 2 3]", a.Difference(b).String())
+	must.Eq(t, "[4 5]", a.Intersect(b).String())
+	must.Eq(t, "[4 5]", a.Intersect(From([]int{4, 5, 9})).String())
+
+	must.True(t, a.Subset(From([]int{1, 5})))
+	must.False(t, a.Subset(b))
+	must.True(t, a.ProperSubset(From([]int{1, 2, 3, 4})))
+	must.False(t, a.ProperSubset(From([]int{1, 2, 3, 4, 5})))
+
+	must.True(t, a.ContainsSlice([]int{2, 3}))
+	must.False(t, a.ContainsSlice([]int{2, 9}))
+	must.True(t, a.EqualSlice([]int{5, 4, 3, 2, 1, 1}))
+	must.False(t, a.EqualSliceSet([]int{5, 4, 3, 2, 1, 1}))
+	must.True(t, a.EqualSliceSet([]int{5, 4, 3, 2, 1}))
+	must.True(t, a.EqualSet(From([]int{1, 2, 3, 4, 5})))
+
+	c := a.Copy()
+	must.True(t, c.Equal(a))
+	c.Remove(3)
+	must.False(t, c.Equal(a))
+	must.True(t, a.Contains(3))
+	compactInvariants(t, c)
+
+	must.True(t, a.RemoveFunc(func(i int) bool { return i%2 == 0 }))
+	must.Eq(t, []int{1, 3, 5}, a.Slice())
+	must.True(t, a.RemoveSet(From([]int{1, 9})))
+	must.Eq(t, []int{3, 5}, a.Slice())
+}
+
+func TestCompactTreeSet_JSON(t *testing.T) {
+	ts := CompactTreeSetFrom[string]([]string{"b", "c", "a"}, cmp.Compare[string])
+	bs, err := json.Marshal(ts)
+	must.NoError(t, err)
+	must.Eq(t, `["a","b","c"]`, string(bs))
+
+	result := NewCompactTreeSet[string](cmp.Compare[string])
+	must.NoError(t, json.Unmarshal(bs, result))
+	must.True(t, ts.Equal(result))
+}
@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"sync"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+// These tests exercise the documented concurrency contract of each set type,
+// and are most useful when run with the race detector enabled.
+//
+//	go test -race ./...
+
+const readers = 8
+
+// concurrentReads runs f from many goroutines at once.
+func concurrentReads(f func()) {
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrency_reads(t *testing.T) {
+	cases := []struct {
+		name string
+		col  Collection[int]
+	}{
+		{name: "set", col: From(ints(size))},
+		{name: "hashset", col: HashSetFromFunc(ints(size), func(i int) int { return i })},
+		{name: "treeset", col: TreeSetFrom(ints(size), cmp.Compare[int])},
+		{name: "skipset", col: SkipSetFrom(ints(size), cmp.Compare[int])},
+		{name: "readmostly", col: ReadMostlySetFrom(ints(size))},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			other := From(ints(size / 2))
+			concurrentReads(func() {
+				sum := 0
+				for item := range tc.col.Items() {
+					sum += item
+				}
+				must.Eq(t, size*(size+1)/2, sum)
+				must.True(t, tc.col.Contains(size/2))
+				must.False(t, tc.col.Contains(-1))
+				must.Size(t, size, tc.col)
+				must.SliceLen(t, size, tc.col.Slice())
+				must.Size(t, size/2, tc.col.Intersect(other))
+				must.NotEq(t, "", tc.col.String())
+			})
+		})
+	}
+
+	t.Run("treeset ordered", func(t *testing.T) {
+		ts := TreeSetFrom(shuffle(ints(size)), cmp.Compare[int])
+		concurrentReads(func() {
+			must.Eq(t, 1, ts.Min())
+			must.Eq(t, size, ts.Max())
+			must.Eq(t, []int{1, 2, 3}, ts.TopK(3))
+			must.Eq(t, 11, ts.CountRange(10, 20))
+			must.Size(t, 9, ts.Below(10))
+			next, ok := ts.FirstAbove(10)
+			must.True(t, ok)
+			must.Eq(t, 11, next)
+		})
+	})
+}
+
+func TestConcurrency_locked(t *testing.T) {
+	cases := []struct {
+		name string
+		col  Collection[int]
+	}{
+		{name: "set", col: New[int](0)},
+		{name: "hashset", col: NewHashSetFunc(0, func(i int) int { return i })},
+		{name: "treeset", col: NewTreeSet(cmp.Compare[int])},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				lock sync.RWMutex
+				wg   sync.WaitGroup
+			)
+
+			for w := 0; w < readers; w++ {
+				wg.Add(2)
+				go func(w int) {
+					defer wg.Done()
+					for i := 0; i < size; i++ {
+						lock.Lock()
+						tc.col.Insert(w*size + i)
+						if i%2 == 0 {
+							tc.col.Remove(w*size + i)
+						}
+						lock.Unlock()
+					}
+				}(w)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < size; i++ {
+						lock.RLock()
+						_ = tc.col.Contains(i)
+						for range tc.col.Items() {
+							break
+						}
+						lock.RUnlock()
+					}
+				}()
+			}
+			wg.Wait()
+
+			must.Size(t, readers*size/2, tc.col)
+		})
+	}
+}
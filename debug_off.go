@@ -0,0 +1,21 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !setdebug
+
+package set
+
+// debug enables additional runtime checks that are too expensive or too
+// strict for production use. Build with -tags setdebug to enable them.
+const debug = false
+
+// guard detects modification of a set during iteration when debug is enabled.
+//
+// Without the setdebug build tag a guard has no size and does nothing.
+type guard struct{}
+
+func (*guard) enter() {}
+
+func (*guard) exit() {}
+
+func (*guard) check(string) {}
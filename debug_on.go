@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build setdebug
+
+package set
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// debug enables additional runtime checks that are too expensive or too
+// strict for production use. Build with -tags setdebug to enable them.
+const debug = true
+
+// guard detects modification of a set during iteration when debug is enabled.
+//
+// The count of active iterators is kept atomically, so that concurrent
+// readers (which are permitted) do not themselves trigger the race detector.
+type guard struct {
+	iterators atomic.Int64
+}
+
+// enter records the start of an iteration.
+func (g *guard) enter() {
+	g.iterators.Add(1)
+}
+
+// exit records the end of an iteration.
+func (g *guard) exit() {
+	g.iterators.Add(-1)
+}
+
+// check panics if op is attempted while an iteration is in progress.
+func (g *guard) check(op string) {
+	if n := g.iterators.Load(); n > 0 {
+		panic(fmt.Sprintf("set: %s during iteration (%d active iterators); use Snapshot to modify a set while iterating", op, n))
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+)
+
+// Digest is a verifiable summary of the elements of a set, as created by
+// ExportDigest. A Digest may be published so that external systems can later
+// verify claims of membership or non-membership of an element in the set, e.g.
+// for an allow-list of artifacts.
+type Digest struct {
+	// Hashes contains the hash of each element of the set, in ascending order.
+	Hashes [][]byte
+
+	// Root is the SHA-256 Merkle Tree Hash of Hashes, as defined by RFC 6962.
+	// Root commits to the exact contents of Hashes, and may be published on its
+	// own where Hashes can be retrieved later.
+	Root []byte
+}
+
+// ExportDigest creates a Digest of the elements of col, where hash produces a
+// hash of each element (e.g. the SHA-256 sum of its canonical encoding).
+//
+// The Digest does not depend on the order in which the elements of col are
+// iterated, so identical sets always produce identical digests.
+func ExportDigest[T any](col Collection[T], hash func(T) []byte) Digest {
+	hashes := make([][]byte, 0, col.Size())
+	for item := range col.Items() {
+		hashes = append(hashes, hash(item))
+	}
+	slices.SortFunc(hashes, bytes.Compare)
+	return Digest{
+		Hashes: hashes,
+		Root:   merkleRoot(hashes),
+	}
+}
+
+// Contains returns whether the element with the given hash is a member of the
+// set summarized by d.
+//
+// Contains does not check that d is consistent; use Verify to check the Hashes
+// of d against a trusted Root first.
+func (d Digest) Contains(hash []byte) bool {
+	_, found := slices.BinarySearchFunc(d.Hashes, hash, bytes.Compare)
+	return found
+}
+
+// Verify returns an error wrapping ErrInvalidDigest if the Hashes of d are not
+// in ascending order, or do not produce the Root of d.
+func (d Digest) Verify() error {
+	if !slices.IsSortedFunc(d.Hashes, bytes.Compare) {
+		return fmt.Errorf("%w: hashes are not sorted", ErrInvalidDigest)
+	}
+	if !bytes.Equal(merkleRoot(d.Hashes), d.Root) {
+		return fmt.Errorf("%w: hashes do not match root", ErrInvalidDigest)
+	}
+	return nil
+}
+
+// merkleRoot computes the Merkle Tree Hash of leaves as defined by RFC 6962,
+// section 2.1.
+func merkleRoot(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	case 1:
+		h := sha256.New()
+		h.Write([]byte{0x00})
+		h.Write(leaves[0])
+		return h.Sum(nil)
+	}
+
+	// split at the largest power of two smaller than the number of leaves
+	k := 1
+	for k*2 < len(leaves) {
+		k *= 2
+	}
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(merkleRoot(leaves[:k]))
+	h.Write(merkleRoot(leaves[k:]))
+	return h.Sum(nil)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func sha(s string) []byte {
+	sum := sha256.Sum256([]byte(s))
+	return sum[:]
+}
+
+func TestExportDigest(t *testing.T) {
+	a := ExportDigest[string](From([]string{"alpha", "beta", "gamma"}), sha)
+	b := ExportDigest[string](TreeSetFrom([]string{"gamma", "alpha", "beta"}, cmp.Compare[string]), sha)
+	must.Eq(t, a, b)
+	must.NoError(t, a.Verify())
+	must.SliceLen(t, 3, a.Hashes)
+
+	must.True(t, a.Contains(sha("beta")))
+	must.False(t, a.Contains(sha("delta")))
+
+	c := ExportDigest[string](From([]string{"alpha", "beta"}), sha)
+	must.NotEq(t, a.Root, c.Root)
+}
+
+func TestExportDigest_empty(t *testing.T) {
+	d := ExportDigest[string](New[string](0), sha)
+	must.NoError(t, d.Verify())
+	must.SliceEmpty(t, d.Hashes)
+	// the hash of an empty tree is the hash of an empty string
+	must.Eq(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", hex.EncodeToString(d.Root))
+}
+
+func TestDigest_Verify(t *testing.T) {
+	d := ExportDigest[string](From([]string{"alpha", "beta", "gamma", "delta", "epsilon"}), sha)
+	must.NoError(t, d.Verify())
+
+	// claiming an additional member invalidates the digest
+	forged := Digest{Hashes: append([][]byte{make([]byte, sha256.Size)}, d.Hashes...), Root: d.Root}
+	must.ErrorIs(t, forged.Verify(), ErrInvalidDigest)
+	must.EqError(t, forged.Verify(), "set: invalid digest: hashes do not match root")
+
+	unsorted := Digest{Hashes: [][]byte{d.Hashes[1], d.Hashes[0]}, Root: d.Root}
+	must.EqError(t, unsorted.Verify(), "set: invalid digest: hashes are not sorted")
+}
+
+func TestMerkleRoot(t *testing.T) {
+	leaf := func(b []byte) []byte {
+		return sha(string(append([]byte{0x00}, b...)))
+	}
+	node := func(l, r []byte) []byte {
+		return sha(string(append(append([]byte{0x01}, l...), r...)))
+	}
+	a, b, c := []byte("a"), []byte("b"), []byte("c")
+
+	must.Eq(t, leaf(a), merkleRoot([][]byte{a}))
+	must.Eq(t, node(leaf(a), leaf(b)), merkleRoot([][]byte{a, b}))
+	must.Eq(t, node(node(leaf(a), leaf(b)), leaf(c)), merkleRoot([][]byte{a, b, c}))
+}
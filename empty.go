@@ -0,0 +1,132 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import "iter"
+
+// Empty returns an immutable empty set, e.g. as the default return value of a
+// function returning a Collection[T] with no results.
+//
+// Unlike creating a new set, Empty does not allocate. Any method which would
+// insert an element into the empty set panics; removing elements is a no-op.
+// Intersect and Difference return the empty set, and Union returns a copy of
+// the other set, so that the result of Union may be modified. The empty set
+// may be combined with any set of this package, e.g. as the argument of Union
+// or Subset.
+func Empty[T any]() Collection[T] {
+	return emptySet[T]{}
+}
+
+// emptySet is a zero size Collection, and so converting it into an interface
+// does not allocate.
+type emptySet[T any] struct{}
+
+func (emptySet[T]) modify() {
+	panic("set: cannot insert into the empty set")
+}
+
+func (e emptySet[T]) Insert(T) bool {
+	e.modify()
+	return false
+}
+
+func (e emptySet[T]) InsertSlice(items []T) bool {
+	if len(items) > 0 {
+		e.modify()
+	}
+	return false
+}
+
+func (e emptySet[T]) InsertSet(col Collection[T]) bool {
+	if !col.Empty() {
+		e.modify()
+	}
+	return false
+}
+
+func (emptySet[T]) Remove(T) bool {
+	return false
+}
+
+func (emptySet[T]) RemoveSlice([]T) bool {
+	return false
+}
+
+func (emptySet[T]) RemoveSet(Collection[T]) bool {
+	return false
+}
+
+func (emptySet[T]) RemoveFunc(func(T) bool) bool {
+	return false
+}
+
+func (emptySet[T]) Contains(T) bool {
+	return false
+}
+
+func (emptySet[T]) ContainsSlice(items []T) bool {
+	return len(items) == 0
+}
+
+func (emptySet[T]) Subset(col Collection[T]) bool {
+	return col.Empty()
+}
+
+func (emptySet[T]) ProperSubset(Collection[T]) bool {
+	return false
+}
+
+func (emptySet[T]) Size() int {
+	return 0
+}
+
+func (emptySet[T]) Empty() bool {
+	return true
+}
+
+func (e emptySet[T]) Union(col Collection[T]) Collection[T] {
+	// a copy of col, of the same implementation as col
+	return col.Difference(e)
+}
+
+func (e emptySet[T]) Difference(Collection[T]) Collection[T] {
+	return e
+}
+
+func (e emptySet[T]) Intersect(Collection[T]) Collection[T] {
+	return e
+}
+
+func (emptySet[T]) Slice() []T {
+	return []T{}
+}
+
+func (emptySet[T]) String() string {
+	return "[]"
+}
+
+func (emptySet[T]) StringFunc(func(T) string) string {
+	return "[]"
+}
+
+func (emptySet[T]) EqualSet(col Collection[T]) bool {
+	return col.Empty()
+}
+
+func (emptySet[T]) EqualSlice(items []T) bool {
+	return len(items) == 0
+}
+
+func (emptySet[T]) EqualSliceSet(items []T) bool {
+	return len(items) == 0
+}
+
+func (emptySet[T]) Items() iter.Seq[T] {
+	return func(func(T) bool) {}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (emptySet[T]) MarshalJSON() ([]byte, error) {
+	return []byte("[]"), nil
+}
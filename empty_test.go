@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"encoding/json"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestEmpty(t *testing.T) {
+	e := Empty[int]()
+
+	t.Run("queries", func(t *testing.T) {
+		must.Empty(t, e)
+		must.Size(t, 0, e)
+		must.False(t, e.Contains(1))
+		must.True(t, e.ContainsSlice(nil))
+		must.False(t, e.ContainsSlice([]int{1}))
+		must.True(t, e.Subset(New[int](0)))
+		must.False(t, e.Subset(Of(1)))
+		must.True(t, Of(1).Subset(e))
+		must.True(t, e.EqualSet(New[int](0)))
+		must.True(t, New[int](0).EqualSet(e))
+		must.False(t, e.EqualSet(Of(1)))
+		must.Eq(t, "[]", e.String())
+		must.SliceEmpty(t, e.Slice())
+		for range e.Items() {
+			t.Fatal("unexpected element")
+		}
+
+		bs, err := json.Marshal(e)
+		must.NoError(t, err)
+		must.Eq(t, "[]", string(bs))
+	})
+
+	t.Run("operations", func(t *testing.T) {
+		other := TreeSetOf(cmp.Compare[int], 2, 1)
+		union := e.Union(other)
+		must.Eq(t, []int{1, 2}, union.Slice())
+
+		// the union is a modifiable copy
+		must.True(t, union.Insert(3))
+		must.Size(t, 2, other)
+
+		must.Empty(t, e.Intersect(other))
+		must.Empty(t, e.Difference(other))
+		must.Eq(t, []int{1, 2}, other.Difference(e).Slice())
+	})
+
+	t.Run("modify", func(t *testing.T) {
+		must.False(t, e.Remove(1))
+		must.False(t, e.RemoveSlice([]int{1}))
+		must.False(t, e.RemoveFunc(func(int) bool { return true }))
+		must.False(t, e.InsertSlice(nil))
+		must.False(t, e.InsertSet(New[int](0)))
+
+		defer func() {
+			must.NotNil(t, recover())
+		}()
+		e.Insert(1)
+	})
+
+	t.Run("allocs", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(10, func() {
+			_ = Empty[string]()
+		})
+		must.Zero(t, allocs)
+	})
+}
+
+func TestEmpty_combine(t *testing.T) {
+	e := Empty[string]()
+	items := []string{"b", "a"}
+	identity := func(s string) string { return s }
+
+	cases := []struct {
+		name string
+		col  func() Collection[string]
+	}{
+		{name: "set", col: func() Collection[string] { return From(items) }},
+		{name: "hashset", col: func() Collection[string] { return HashSetFromFunc(items, identity) }},
+		{name: "treeset", col: func() Collection[string] { return TreeSetFrom(items, cmp.Compare[string]) }},
+		{name: "compact", col: func() Collection[string] { return CompactTreeSetFrom(items, cmp.Compare[string]) }},
+		{name: "skipset", col: func() Collection[string] { return SkipSetFrom(items, cmp.Compare[string]) }},
+		{name: "prefixset", col: func() Collection[string] { return PrefixSetFrom(items) }},
+		{name: "readmostly", col: func() Collection[string] { return ReadMostlySetFrom(items) }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			col := tc.col()
+			must.True(t, col.Union(e).EqualSlice(items))
+			must.True(t, e.Union(col).EqualSlice(items))
+			must.Empty(t, col.Intersect(e))
+			must.Empty(t, e.Intersect(col))
+			must.True(t, col.Difference(e).EqualSlice(items))
+			must.Empty(t, e.Difference(col))
+			must.True(t, col.Subset(e))
+			must.False(t, e.Subset(col))
+			must.False(t, col.EqualSet(e))
+			must.False(t, e.EqualSet(col))
+			must.False(t, col.InsertSet(e))
+			must.False(t, col.RemoveSet(e))
+			must.Size(t, 2, col)
+		})
+	}
+}
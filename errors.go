@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The errors returned by fallible functions of this package wrap one of the
+// following sentinel errors, so that callers may distinguish the failure mode
+// using errors.Is.
+var (
+	// ErrEmptySet indicates an operation requiring at least one element was
+	// applied to an empty set.
+	ErrEmptySet = errors.New("set: set is empty")
+
+	// ErrIncompatibleComparator indicates two ordered sets use different
+	// CompareFunc implementations, and so their elements are not comparable.
+	ErrIncompatibleComparator = errors.New("set: incompatible compare functions")
+
+	// ErrSizeLimit indicates an operation would exceed a limit on the number of
+	// elements of a set.
+	ErrSizeLimit = errors.New("set: size limit exceeded")
+
+	// ErrOutOfRange indicates an element cannot be represented in the requested
+	// form, e.g. a bitmask.
+	ErrOutOfRange = errors.New("set: element out of range")
+
+	// ErrHashCollision indicates two unequal elements of a HashSet have the
+	// same hash value.
+	ErrHashCollision = errors.New("set: hash collision")
+
+	// ErrCycle indicates a dependency graph contains a cycle.
+	ErrCycle = errors.New("set: dependency cycle")
+
+	// ErrPartialResult indicates an operation was abandoned before completion,
+	// e.g. because its context was canceled, leaving a partial result.
+	ErrPartialResult = errors.New("set: partial result")
+
+	// ErrConflict indicates a change cannot be applied to a set because the
+	// set is not in the expected state.
+	ErrConflict = errors.New("set: conflicting change")
+
+	// ErrDuplicate indicates an encoding of a set contains the same element
+	// more than once.
+	ErrDuplicate = errors.New("set: duplicate element")
+
+	// ErrNotAllowed indicates a value is not one of a set of allowed values,
+	// as checked by a Validator.
+	ErrNotAllowed = errors.New("set: value not allowed")
+
+	// ErrInvalidDigest indicates a Digest, or another record of the elements of
+	// a set such as an audit export, is not internally consistent.
+	ErrInvalidDigest = errors.New("set: invalid digest")
+)
+
+// emptyError returns an error wrapping ErrEmptySet for the operation op
+// applied to the empty set col, naming the type of col to aid debugging.
+func emptyError(op string, col any) error {
+	return fmt.Errorf("%s of empty %T: %w", op, col, ErrEmptySet)
+}
+
+// Must returns result if err is nil, and panics otherwise.
+//
+// Must is intended for the initialization of package-level variables from the
+// fallible functions of this package, where the arguments are static and an
+// error indicates a programming mistake, e.g.
+//
+//	var flags = set.Must(set.ToBits(set.Of(FlagA, FlagB)))
+func Must[R any](result R, err error) R {
+	if err != nil {
+		panic("set.Must: " + err.Error())
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"fmt"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestMust(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		bits := Must(ToBits(Of(0, 2)))
+		must.Eq(t, uint64(0b101), bits)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		defer func() {
+			r := recover()
+			must.Eq(t, "set.Must: set: element out of range for bitmask (64)", fmt.Sprint(r))
+		}()
+		Must(ToBits(Of(64)))
+		t.Fatal("expected panic")
+	})
+}
+
+func TestEnsureNonEmpty(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		least, err := EnsureNonEmpty[int](TreeSetFrom([]int{3, 1, 2}, cmp.Compare[int]))
+		must.NoError(t, err)
+		must.Eq(t, 1, least)
+
+		item, err := EnsureNonEmpty[string](From([]string{"a"}))
+		must.NoError(t, err)
+		must.Eq(t, "a", item)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := EnsureNonEmpty[int](New[int](0))
+		must.ErrorIs(t, err, ErrEmptySet)
+		must.EqError(t, err, "EnsureNonEmpty of empty *set.Set[int]: set: set is empty")
+	})
+}
+
+func TestEmptyMinMax(t *testing.T) {
+	recovered := func(f func()) (r any) {
+		defer func() { r = recover() }()
+		f()
+		return
+	}
+
+	ts := NewTreeSet[int](cmp.Compare[int])
+	err, ok := recovered(func() { ts.Min() }).(error)
+	must.True(t, ok)
+	must.ErrorIs(t, err, ErrEmptySet)
+	must.EqError(t, err, "min of empty *set.TreeSet[int]: set: set is empty")
+
+	_, err = ts.TryMax()
+	must.EqError(t, err, "max of empty *set.TreeSet[int]: set: set is empty")
+
+	cts := NewCompactTreeSet[string](cmp.Compare[string])
+	err, ok = recovered(func() { cts.Max() }).(error)
+	must.True(t, ok)
+	must.EqError(t, err, "max of empty *set.CompactTreeSet[string]: set: set is empty")
+}
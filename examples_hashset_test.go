@@ -23,7 +23,7 @@ func (p *person) String() string {
 }
 
 func ExampleHashSet_Insert() {
-	s := NewHashSet[*person, string](10)
+	s := NewHashSet[*person](10)
 	s.Insert(&person{Name: "dave", ID: 108})
 	s.Insert(&person{Name: "armon", ID: 101})
 	s.Insert(&person{Name: "mitchell", ID: 100})
@@ -36,7 +36,7 @@ func ExampleHashSet_Insert() {
 }
 
 func ExampleHashSet_InsertSlice() {
-	s := NewHashSet[*person, string](10)
+	s := NewHashSet[*person](10)
 	s.InsertSlice([]*person{
 		{Name: "dave", ID: 108},
 		{Name: "mitchell", ID: 100},
@@ -55,8 +55,8 @@ func ExampleHashSet_InsertSet() {
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
-	s1 := HashSetFrom[*person, string]([]*person{anna, carl})
-	s2 := HashSetFrom[*person, string]([]*person{carl, dave, bill})
+	s1 := HashSetFrom([]*person{anna, carl})
+	s2 := HashSetFrom([]*person{carl, dave, bill})
 	s2.InsertSet(s1)
 
 	fmt.Println(s1)
@@ -72,7 +72,7 @@ func ExampleHashSet_Remove() {
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
-	s := HashSetFrom[*person, string]([]*person{anna, carl, dave, bill})
+	s := HashSetFrom([]*person{anna, carl, dave, bill})
 
 	fmt.Println(s)
 
@@ -90,7 +90,7 @@ func ExampleHashSet_RemoveSlice() {
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
-	s := HashSetFrom[*person, string]([]*person{anna, carl, dave, bill})
+	s := HashSetFrom([]*person{anna, carl, dave, bill})
 
 	fmt.Println(s)
 
@@ -108,8 +108,8 @@ func ExampleHashSet_RemoveSet() {
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
-	s := HashSetFrom[*person, string]([]*person{carl, dave, bill})
-	r := HashSetFrom[*person, string]([]*person{anna, carl})
+	s := HashSetFrom([]*person{carl, dave, bill})
+	r := HashSetFrom([]*person{anna, carl})
 
 	fmt.Println(s)
 
@@ -127,7 +127,7 @@ func ExampleHashSet_RemoveFunc() {
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
-	s := HashSetFrom[*person, string]([]*person{anna, bill, carl, dave})
+	s := HashSetFrom([]*person{anna, bill, carl, dave})
 
 	idAbove50 := func(p *person) bool {
 		return p.ID >= 50
@@ -149,7 +149,7 @@ func ExampleHashSet_Contains() {
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
-	s := HashSetFrom[*person, string]([]*person{anna, bill, carl})
+	s := HashSetFrom([]*person{anna, bill, carl})
 
 	fmt.Println(s.Contains(anna))
 	fmt.Println(s.Contains(dave))
@@ -164,14 +164,14 @@ func ExampleHashSet_ContainsSlice() {
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
-	s := HashSetFrom[*person, string]([]*person{anna, bill, carl})
+	s := HashSetFrom([]*person{anna, bill, carl})
 
 	fmt.Println(s.ContainsSlice([]*person{anna, bill}))
 	fmt.Println(s.ContainsSlice([]*person{anna, bill, carl}))
 	fmt.Println(s.ContainsSlice([]*person{carl, dave}))
 
 	// Output:
-	// false
+	// true
 	// true
 	// false
 }
@@ -181,9 +181,9 @@ func ExampleHashSet_Subset() {
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
-	s1 := HashSetFrom[*person, string]([]*person{anna, bill, carl})
-	s2 := HashSetFrom[*person, string]([]*person{anna, bill})
-	s3 := HashSetFrom[*person, string]([]*person{bill, carl, dave})
+	s1 := HashSetFrom([]*person{anna, bill, carl})
+	s2 := HashSetFrom([]*person{anna, bill})
+	s3 := HashSetFrom([]*person{bill, carl, dave})
 
 	fmt.Println(s1.Subset(s2))
 	fmt.Println(s1.Subset(s3))
@@ -197,7 +197,7 @@ func ExampleHashSet_Size() {
 	anna := &person{Name: "anna", ID: 94}
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
-	s := HashSetFrom[*person, string]([]*person{anna, bill, carl})
+	s := HashSetFrom([]*person{anna, bill, carl})
 
 	fmt.Println(s.Size())
 
@@ -206,7 +206,7 @@ func ExampleHashSet_Size() {
 }
 
 func ExampleHashSet_Empty() {
-	s := NewHashSet[*person, string](0)
+	s := NewHashSet[*person](0)
 
 	fmt.Println(s.Empty())
 
@@ -219,8 +219,8 @@ func ExampleHashSet_Union() {
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
-	s1 := HashSetFrom[*person, string]([]*person{anna, bill, carl})
-	s2 := HashSetFrom[*person, string]([]*person{anna, bill, dave})
+	s1 := HashSetFrom([]*person{anna, bill, carl})
+	s2 := HashSetFrom([]*person{anna, bill, dave})
 	union := s1.Union(s2)
 
 	fmt.Println(s1)
@@ -238,8 +238,8 @@ func ExampleHashSet_Difference() {
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
-	s1 := HashSetFrom[*person, string]([]*person{anna, bill, carl})
-	s2 := HashSetFrom[*person, string]([]*person{anna, bill, dave})
+	s1 := HashSetFrom([]*person{anna, bill, carl})
+	s2 := HashSetFrom([]*person{anna, bill, dave})
 	difference := s1.Difference(s2)
 
 	fmt.Println(s1)
@@ -257,8 +257,8 @@ func ExampleHashSet_Intersect() {
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
-	s1 := HashSetFrom[*person, string]([]*person{anna, bill, carl})
-	s2 := HashSetFrom[*person, string]([]*person{anna, bill, dave})
+	s1 := HashSetFrom([]*person{anna, bill, carl})
+	s2 := HashSetFrom([]*person{anna, bill, dave})
 	intersect := s1.Intersect(s2)
 
 	fmt.Println(s1)
@@ -277,9 +277,9 @@ func ExampleHashSet_Equal() {
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
 
-	s1 := HashSetFrom[*person, string]([]*person{anna, bill, carl})
-	s2 := HashSetFrom[*person, string]([]*person{anna, bill, carl})
-	s3 := HashSetFrom[*person, string]([]*person{anna, bill, dave})
+	s1 := HashSetFrom([]*person{anna, bill, carl})
+	s2 := HashSetFrom([]*person{anna, bill, carl})
+	s3 := HashSetFrom([]*person{anna, bill, dave})
 
 	fmt.Println(s1.Equal(s2))
 	fmt.Println(s1.Equal(s3))
@@ -295,7 +295,7 @@ func ExampleHashSet_EqualSlice() {
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
 
-	s := HashSetFrom[*person, string]([]*person{anna, bill, carl})
+	s := HashSetFrom([]*person{anna, bill, carl})
 
 	fmt.Println(s.EqualSlice([]*person{bill, anna, carl}))
 	fmt.Println(s.EqualSlice([]*person{anna, anna, bill, carl}))
@@ -313,7 +313,7 @@ func ExampleHashSet_EqualSliceSet() {
 	carl := &person{Name: "carl", ID: 10}
 	dave := &person{Name: "dave", ID: 32}
 
-	s := HashSetFrom[*person, string]([]*person{anna, bill, carl})
+	s := HashSetFrom([]*person{anna, bill, carl})
 
 	fmt.Println(s.EqualSliceSet([]*person{bill, anna, carl}))
 	fmt.Println(s.EqualSliceSet([]*person{anna, anna, bill, carl}))
@@ -329,7 +329,7 @@ func ExampleHashSet_Copy() {
 	anna := &person{Name: "anna", ID: 94}
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
-	s := HashSetFrom[*person, string]([]*person{anna, bill, carl})
+	s := HashSetFrom([]*person{anna, bill, carl})
 	c := s.Copy()
 
 	fmt.Println(c)
@@ -342,7 +342,7 @@ func ExampleHashSet_Slice() {
 	anna := &person{Name: "anna", ID: 94}
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
-	s := HashSetFrom[*person, string]([]*person{anna, bill, carl})
+	s := HashSetFrom([]*person{anna, bill, carl})
 
 	slice := s.Slice()
 	sort.Slice(slice, func(a, b int) bool {
@@ -359,7 +359,7 @@ func ExampleHashSet_String() {
 	anna := &person{Name: "anna", ID: 94}
 	bill := &person{Name: "bill", ID: 50}
 	carl := &person{Name: "carl", ID: 10}
-	s := HashSetFrom[*person, string]([]*person{anna, bill, carl})
+	s := HashSetFrom([]*person{anna, bill, carl})
 
 	fmt.Println(s.String())
 
@@ -290,3 +290,14 @@ func ExampleSet_MarshalJSON() {
 	// Output:
 	// {"colors":["red","green","blue"]}
 }
+
+func ExampleCanonicalJSON() {
+	s := From([]string{"red", "green", "blue"})
+
+	b, _ := CanonicalJSON[string](s)
+
+	fmt.Println(string(b))
+
+	// Output:
+	// ["blue","green","red"]
+}
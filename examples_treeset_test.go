@@ -389,6 +389,28 @@ func ExampleTreeSet_TopK() {
 	// [1 2 3 4 5]
 }
 
+func ExampleTreeSet_TruncateToSmallestK() {
+	s := TreeSetFrom[int]([]int{1, 2, 3, 4, 5}, cmp.Compare[int])
+
+	fmt.Println(s.TruncateToSmallestK(2))
+	fmt.Println(s)
+
+	// Output:
+	// 3
+	// [1 2]
+}
+
+func ExampleTreeSet_TruncateToLargestK() {
+	s := TreeSetFrom[int]([]int{1, 2, 3, 4, 5}, cmp.Compare[int])
+
+	fmt.Println(s.TruncateToLargestK(2))
+	fmt.Println(s)
+
+	// Output:
+	// 3
+	// [4 5]
+}
+
 func ExampleTreeSet_BottomK() {
 	s := TreeSetFrom[int]([]int{1, 2, 3, 4, 5}, cmp.Compare[int])
 
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"encoding/json"
+)
+
+// SetField is a Set suitable for use as a field of a struct which is encoded
+// to or decoded from JSON, e.g. an API request payload or a configuration file.
+//
+// Unlike a *Set, the zero value of a SetField is an empty set which is ready to
+// use, is encoded as an empty JSON array rather than null, and is replaced by
+// (rather than merged with) the elements of a decoded JSON array.
+//
+//	type Request struct {
+//	  Tags set.SetField[string] `json:"tags"`
+//	}
+type SetField[T comparable] struct {
+	set *Set[T]
+}
+
+// Set returns the underlying Set of f, creating it if necessary.
+//
+// Modifications to the returned Set are reflected in f.
+func (f *SetField[T]) Set() *Set[T] {
+	if f.set == nil {
+		f.set = New[T](0)
+	}
+	return f.set
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (f SetField[T]) MarshalJSON() ([]byte, error) {
+	if f.set == nil {
+		return []byte("[]"), nil
+	}
+	return f.set.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// The existing elements of f are replaced. A JSON null decodes as an empty set.
+func (f *SetField[T]) UnmarshalJSON(data []byte) error {
+	s := New[T](0)
+	if err := unmarshalJSON[T](s, data); err != nil {
+		return err
+	}
+	f.set = s
+	return nil
+}
+
+// Comparator represents a type which compares two elements, as with a
+// CompareFunc. The zero value of a Comparator must be ready to use, so that a
+// TreeSetField may create its TreeSet without being given a CompareFunc.
+type Comparator[T any] interface {
+	Compare(a, b T) int
+}
+
+// Ordered is a Comparator for types which satisfy cmp.Ordered, comparing
+// elements via cmp.Compare.
+type Ordered[T cmp.Ordered] struct{}
+
+// Compare returns the result of cmp.Compare(a, b).
+func (Ordered[T]) Compare(a, b T) int {
+	return cmp.Compare(a, b)
+}
+
+// TreeSetField is a TreeSet suitable for use as a field of a struct which is
+// encoded to or decoded from JSON, ordering elements via the Comparator C.
+//
+// Unlike a *TreeSet, the zero value of a TreeSetField is an empty set which is
+// ready to use, is encoded as an empty JSON array rather than null, and is
+// replaced by (rather than merged with) the elements of a decoded JSON array.
+// Elements are encoded in order.
+//
+//	type Request struct {
+//	  Priorities set.TreeSetField[int, set.Ordered[int]] `json:"priorities"`
+//	}
+type TreeSetField[T any, C Comparator[T]] struct {
+	set *TreeSet[T]
+}
+
+// TreeSet returns the underlying TreeSet of f, creating it if necessary.
+//
+// Modifications to the returned TreeSet are reflected in f.
+func (f *TreeSetField[T, C]) TreeSet() *TreeSet[T] {
+	if f.set == nil {
+		f.set = f.empty()
+	}
+	return f.set
+}
+
+// empty creates an empty TreeSet ordered by C.
+func (f *TreeSetField[T, C]) empty() *TreeSet[T] {
+	var c C
+	return NewTreeSet[T](c.Compare)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (f TreeSetField[T, C]) MarshalJSON() ([]byte, error) {
+	if f.set == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(f.set.Slice())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// The existing elements of f are replaced. A JSON null decodes as an empty set.
+func (f *TreeSetField[T, C]) UnmarshalJSON(data []byte) error {
+	s := f.empty()
+	if err := unmarshalJSON[T](s, data); err != nil {
+		return err
+	}
+	f.set = s
+	return nil
+}
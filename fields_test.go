@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestSetField(t *testing.T) {
+	type request struct {
+		Tags SetField[string] `json:"tags"`
+	}
+
+	t.Run("zero", func(t *testing.T) {
+		var r request
+		must.Empty(t, r.Tags.Set())
+
+		b, err := json.Marshal(request{})
+		must.NoError(t, err)
+		must.Eq(t, `{"tags":[]}`, string(b))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		var r request
+		r.Tags.Set().InsertSlice([]string{"a", "b"})
+
+		b, err := json.Marshal(r)
+		must.NoError(t, err)
+
+		var result request
+		must.NoError(t, json.Unmarshal(b, &result))
+		must.Eq(t, r.Tags.Set(), result.Tags.Set())
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		var r request
+		r.Tags.Set().Insert("old")
+		must.NoError(t, json.Unmarshal([]byte(`{"tags":["a","b","a"]}`), &r))
+		must.Eq(t, Of("a", "b"), r.Tags.Set())
+
+		must.NoError(t, json.Unmarshal([]byte(`{"tags":null}`), &r))
+		must.Empty(t, r.Tags.Set())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var r request
+		must.Error(t, json.Unmarshal([]byte(`{"tags":[1]}`), &r))
+	})
+}
+
+func TestTreeSetField(t *testing.T) {
+	type config struct {
+		Ports TreeSetField[int, Ordered[int]] `json:"ports"`
+	}
+
+	t.Run("zero", func(t *testing.T) {
+		var c config
+		must.Empty(t, c.Ports.TreeSet())
+
+		b, err := json.Marshal(config{})
+		must.NoError(t, err)
+		must.Eq(t, `{"ports":[]}`, string(b))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		var c config
+		must.NoError(t, json.Unmarshal([]byte(`{"ports":[443,80,8080,80]}`), &c))
+		must.Eq(t, []int{80, 443, 8080}, c.Ports.TreeSet().Slice())
+
+		b, err := json.Marshal(c)
+		must.NoError(t, err)
+		must.Eq(t, `{"ports":[80,443,8080]}`, string(b))
+	})
+
+	t.Run("compatible", func(t *testing.T) {
+		var a, b config
+		a.Ports.TreeSet().Insert(1)
+		b.Ports.TreeSet().Insert(2)
+		must.NoError(t, a.Ports.TreeSet().CheckCompatible(b.Ports.TreeSet()))
+		must.Eq(t, []int{1, 2}, a.Ports.TreeSet().Union(b.Ports.TreeSet()).Slice())
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+// FuzzCollections applies the same sequence of operations to a Set, HashSet,
+// and TreeSet, asserting their results and membership never diverge.
+//
+// Each pair of bytes of ops is decoded into an operation and an element.
+//
+//	go test -fuzz FuzzCollections
+func FuzzCollections(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0, 1, 0, 2, 0, 3, 1, 2, 2, 2, 2, 3})
+	f.Add([]byte{0, 5, 0, 5, 1, 5, 1, 5, 3, 0})
+	f.Add([]byte{0, 9, 0, 8, 0, 7, 0, 6, 0, 5, 0, 4, 4, 6, 1, 8, 1, 4})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		s := New[int](0)
+		hs := NewHashSetFunc[int, int](0, func(i int) int { return i })
+		ts := NewTreeSet[int](cmp.Compare[int])
+		cols := []Collection[int]{s, hs, ts}
+
+		// each operation returns whether it modified (or found) the element
+		type op func(col Collection[int], item int) bool
+		operations := []op{
+			func(col Collection[int], item int) bool { return col.Insert(item) },
+			func(col Collection[int], item int) bool { return col.Remove(item) },
+			func(col Collection[int], item int) bool { return col.Contains(item) },
+			func(col Collection[int], item int) bool {
+				return col.RemoveFunc(func(i int) bool { return i < item })
+			},
+			func(col Collection[int], item int) bool {
+				return col.InsertSlice([]int{item, item + 1, item + 2})
+			},
+		}
+
+		for i := 0; i+1 < len(ops); i += 2 {
+			operation := operations[int(ops[i])%len(operations)]
+			item := int(ops[i+1] % 64)
+
+			expect := operation(s, item)
+			must.Eq(t, expect, operation(hs, item), must.Sprintf("hashset diverged at op %d", i/2))
+			must.Eq(t, expect, operation(ts, item), must.Sprintf("treeset diverged at op %d", i/2))
+
+			for _, col := range cols[1:] {
+				must.Eq(t, s.Size(), col.Size())
+				must.True(t, s.EqualSet(col))
+			}
+		}
+
+		must.Eq(t, SortedSlice[int](s), ts.Slice())
+		invariants(t, ts, cmp.Compare[int])
+		blackHeight(t, ts.root)
+	})
+}
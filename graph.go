@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+// Graph is a minimal directed or undirected graph of vertices of type T, in
+// which the edges of each vertex are stored as a set.
+//
+// Not thread safe, and not safe for concurrent modification.
+type Graph[T comparable] struct {
+	directed bool
+	vertices *Set[T]
+	out      *SetMap[T, T]
+	in       *SetMap[T, T] // nil for undirected graphs
+}
+
+// NewGraph creates an empty directed Graph, in which an edge from a to b does
+// not imply an edge from b to a.
+func NewGraph[T comparable]() *Graph[T] {
+	return &Graph[T]{
+		directed: true,
+		vertices: New[T](0),
+		out:      NewSetMap[T, T](),
+		in:       NewSetMap[T, T](),
+	}
+}
+
+// NewUndirectedGraph creates an empty undirected Graph, in which each edge
+// connects two vertices in both directions.
+func NewUndirectedGraph[T comparable]() *Graph[T] {
+	return &Graph[T]{
+		vertices: New[T](0),
+		out:      NewSetMap[T, T](),
+	}
+}
+
+// Directed returns whether g is a directed graph.
+func (g *Graph[T]) Directed() bool {
+	return g.directed
+}
+
+// AddVertex inserts vertex into g.
+//
+// Returns true if g was modified (vertex was not already in g), false otherwise.
+func (g *Graph[T]) AddVertex(vertex T) bool {
+	return g.vertices.Insert(vertex)
+}
+
+// AddEdge inserts an edge from a to b into g, inserting a and b as vertices if
+// they are not already in g.
+//
+// Returns true if g was modified (the edge was not already in g), false otherwise.
+func (g *Graph[T]) AddEdge(a, b T) bool {
+	g.vertices.Insert(a)
+	g.vertices.Insert(b)
+	if !g.out.Insert(a, b) {
+		return false
+	}
+	if g.directed {
+		g.in.Insert(b, a)
+	} else {
+		g.out.Insert(b, a)
+	}
+	return true
+}
+
+// RemoveEdge removes the edge from a to b from g. The vertices a and b remain
+// in g.
+//
+// Returns true if g was modified (the edge was in g), false otherwise.
+func (g *Graph[T]) RemoveEdge(a, b T) bool {
+	if !g.out.Remove(a, b) {
+		return false
+	}
+	if g.directed {
+		g.in.Remove(b, a)
+	} else {
+		g.out.Remove(b, a)
+	}
+	return true
+}
+
+// RemoveVertex removes vertex from g, along with every edge to or from vertex.
+//
+// Returns true if g was modified (vertex was in g), false otherwise.
+func (g *Graph[T]) RemoveVertex(vertex T) bool {
+	if !g.vertices.Remove(vertex) {
+		return false
+	}
+
+	reverse := g.out
+	if g.directed {
+		reverse = g.in
+		if sources := g.in.Get(vertex); sources != nil {
+			for source := range sources.Items() {
+				g.out.Remove(source, vertex)
+			}
+		}
+		g.in.Delete(vertex)
+	}
+	if targets := g.out.Get(vertex); targets != nil {
+		for target := range targets.Items() {
+			if target != vertex {
+				reverse.Remove(target, vertex)
+			}
+		}
+	}
+	g.out.Delete(vertex)
+	return true
+}
+
+// HasVertex returns whether vertex is in g.
+func (g *Graph[T]) HasVertex(vertex T) bool {
+	return g.vertices.Contains(vertex)
+}
+
+// HasEdge returns whether the edge from a to b is in g.
+func (g *Graph[T]) HasEdge(a, b T) bool {
+	return g.out.Contains(a, b)
+}
+
+// Vertices returns a set containing each vertex of g.
+func (g *Graph[T]) Vertices() *Set[T] {
+	return g.vertices.Copy()
+}
+
+// Neighbors returns a set containing each vertex with an edge from vertex. For
+// an undirected graph, these are the vertices connected to vertex.
+func (g *Graph[T]) Neighbors(vertex T) *Set[T] {
+	return copyOrEmpty(g.out.Get(vertex))
+}
+
+// Predecessors returns a set containing each vertex with an edge to vertex.
+// For an undirected graph, these are the vertices connected to vertex.
+func (g *Graph[T]) Predecessors(vertex T) *Set[T] {
+	if !g.directed {
+		return g.Neighbors(vertex)
+	}
+	return copyOrEmpty(g.in.Get(vertex))
+}
+
+func copyOrEmpty[T comparable](s *Set[T]) *Set[T] {
+	if s == nil {
+		return New[T](0)
+	}
+	return s.Copy()
+}
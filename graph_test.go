@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestGraph_directed(t *testing.T) {
+	g := NewGraph[string]()
+	must.True(t, g.Directed())
+
+	must.True(t, g.AddEdge("a", "b"))
+	must.False(t, g.AddEdge("a", "b"))
+	must.True(t, g.AddEdge("a", "c"))
+	must.True(t, g.AddEdge("c", "b"))
+	must.True(t, g.AddEdge("c", "c"))
+	must.True(t, g.AddVertex("d"))
+	must.False(t, g.AddVertex("a"))
+
+	must.Eq(t, From([]string{"a", "b", "c", "d"}), g.Vertices())
+	must.True(t, g.HasEdge("a", "b"))
+	must.False(t, g.HasEdge("b", "a"))
+	must.Eq(t, From([]string{"b", "c"}), g.Neighbors("a"))
+	must.Empty(t, g.Neighbors("b"))
+	must.Empty(t, g.Neighbors("z"))
+	must.Eq(t, From([]string{"a", "c"}), g.Predecessors("b"))
+
+	// the returned sets are copies
+	g.Neighbors("a").Insert("z")
+	must.False(t, g.HasEdge("a", "z"))
+
+	must.True(t, g.RemoveEdge("a", "b"))
+	must.False(t, g.RemoveEdge("a", "b"))
+	must.True(t, g.HasVertex("b"))
+	must.Eq(t, From([]string{"c"}), g.Predecessors("b"))
+
+	must.True(t, g.RemoveVertex("c"))
+	must.False(t, g.RemoveVertex("c"))
+	must.False(t, g.HasVertex("c"))
+	must.Empty(t, g.Neighbors("a"))
+	must.Empty(t, g.Predecessors("b"))
+	must.Eq(t, From([]string{"a", "b", "d"}), g.Vertices())
+}
+
+func TestGraph_undirected(t *testing.T) {
+	g := NewUndirectedGraph[int]()
+	must.False(t, g.Directed())
+
+	must.True(t, g.AddEdge(1, 2))
+	must.False(t, g.AddEdge(2, 1))
+	must.True(t, g.AddEdge(1, 3))
+	must.True(t, g.AddEdge(3, 3))
+
+	must.True(t, g.HasEdge(2, 1))
+	must.Eq(t, From([]int{2, 3}), g.Neighbors(1))
+	must.Eq(t, From([]int{1}), g.Predecessors(2))
+	must.Eq(t, From([]int{1, 3}), g.Neighbors(3))
+
+	must.True(t, g.RemoveEdge(2, 1))
+	must.False(t, g.HasEdge(1, 2))
+
+	must.True(t, g.RemoveVertex(3))
+	must.Empty(t, g.Neighbors(1))
+	must.Eq(t, From([]int{1, 2}), g.Vertices())
+}
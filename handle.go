@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+// Handle is a cursor referring to an element of a TreeSet, as created by
+// TreeSet.Find, for visiting and editing neighboring elements without
+// searching from the root of the tree for each, e.g. maintaining a sliding
+// window over an ordered set.
+//
+//	for h := s.Find(start); h.Valid(); {
+//	  if expired(h.Element()) {
+//	    h = h.Remove()
+//	  } else {
+//	    h = h.Next()
+//	  }
+//	}
+//
+// Moving a Handle via Next or Prev takes amortized O(1) time, and removing its
+// element via Remove takes O(log n) time. A Handle remains valid while
+// elements are inserted into its set, but removing any element of the set
+// other than via the Handle (or a Handle returned by it) invalidates it.
+//
+// The zero value is an invalid Handle.
+type Handle[T any] struct {
+	set  *TreeSet[T]
+	node *node[T]
+}
+
+// Find returns a Handle referring to the element of s equal to item, which is
+// invalid if no such element is present in s.
+func (s *TreeSet[T]) Find(item T) Handle[T] {
+	return Handle[T]{set: s, node: s.locate(s.root, item)}
+}
+
+// Valid returns whether h refers to an element.
+func (h Handle[T]) Valid() bool {
+	return h.node != nil
+}
+
+// Element returns the element referred to by h.
+//
+// Must not be called on an invalid Handle.
+func (h Handle[T]) Element() T {
+	return h.node.element
+}
+
+// Next returns a Handle referring to the next larger element of the set,
+// which is invalid if h refers to the largest element.
+//
+// Must not be called on an invalid Handle.
+func (h Handle[T]) Next() Handle[T] {
+	return Handle[T]{set: h.set, node: successor(h.node)}
+}
+
+// Prev returns a Handle referring to the next smaller element of the set,
+// which is invalid if h refers to the smallest element.
+//
+// Must not be called on an invalid Handle.
+func (h Handle[T]) Prev() Handle[T] {
+	return Handle[T]{set: h.set, node: predecessor(h.node)}
+}
+
+// Remove removes the element referred to by h from the set, and returns a
+// Handle referring to the next larger element, as with Next. Once removed, h
+// is invalid, and only the returned Handle may be used.
+//
+// Must not be called on an invalid Handle.
+func (h Handle[T]) Remove() Handle[T] {
+	h.set.guard.check("remove")
+	return Handle[T]{set: h.set, node: h.set.deleteNode(h.node)}
+}
@@ -4,8 +4,10 @@
 package set
 
 import (
+	"context"
 	"fmt"
 	"iter"
+	"slices"
 	"sort"
 )
 
@@ -39,13 +41,28 @@ type HashFunc[T any, H Hash] func(T) H
 
 // HashSet is a generic implementation of the mathematical data structure, oriented
 // around the use of a HashFunc to make hash values from other types.
+//
+// Elements with the same hash value are considered to be the same element. Use
+// WithEquals to detect elements which differ despite having the same hash value,
+// e.g. due to a faulty HashFunc.
+//
+// Not thread safe, and not safe for concurrent modification. Multiple goroutines
+// may read from a HashSet concurrently as long as no goroutine is modifying it.
 type HashSet[T any, H Hash] struct {
-	fn    HashFunc[T, H]
-	items map[H]T
+	fn        HashFunc[T, H]
+	equal     func(a, b T) bool
+	normalize func(H) H // applied to keys given directly, see WithKeyNormalizer
+	items     map[H]T
+	version   uint64 // incremented on each modification, see SortedView
 }
 
 // NewHashSet creates a HashSet with underlying capacity of size and will compute
 // hash values from the T.Hash method.
+//
+// The type of hash values H is inferred from the T.Hash method, and so only T
+// needs to be specified, e.g.
+//
+//	s := set.NewHashSet[*employee](10)
 func NewHashSet[T Hasher[H], H Hash](size int) *HashSet[T, H] {
 	return NewHashSetFunc[T, H](size, HasherFunc[T, H]())
 }
@@ -66,12 +83,26 @@ func NewHashSetFunc[T any, H Hash](size int, fn HashFunc[T, H]) *HashSet[T, H] {
 //
 // T must implement HashFunc[H], where H is of type Hash. This allows custom types
 // that include non-comparable fields to provide their own hash algorithm.
+//
+// Both T and H are inferred from items, e.g.
+//
+//	s := set.HashSetFrom(employees)
 func HashSetFrom[T Hasher[H], H Hash](items []T) *HashSet[T, H] {
 	s := NewHashSet[T, H](len(items))
 	s.InsertSlice(items)
 	return s
 }
 
+// HashSetOf creates a new HashSet containing each of items.
+//
+// HashSetOf is a more concise alternative to HashSetFrom for a fixed list of
+// elements. Both T and H are inferred from items, e.g.
+//
+//	s := set.HashSetOf(e1, e2, e3)
+func HashSetOf[T Hasher[H], H Hash](items ...T) *HashSet[T, H] {
+	return HashSetFrom[T, H](items)
+}
+
 // NewHashSetFromFunc creates a new HashSet containing each element in items.
 func HashSetFromFunc[T any, H Hash](items []T, hash HashFunc[T, H]) *HashSet[T, H] {
 	s := NewHashSetFunc[T, H](len(items), hash)
@@ -79,15 +110,126 @@ func HashSetFromFunc[T any, H Hash](items []T, hash HashFunc[T, H]) *HashSet[T,
 	return s
 }
 
+// WithEquals configures s to compare elements with the same hash value using
+// equal, so that hash collisions between unequal elements are detected rather
+// than silently treating the elements as the same.
+//
+// When a collision is detected, the element already in s is retained; Insert
+// does not insert the colliding element, Contains reports it is not present,
+// and Remove does not remove it. Use TryInsert to be notified of collisions.
+//
+// Sets derived from s (e.g. by Copy or Union) use the same equal function.
+//
+// Returns s.
+func (s *HashSet[T, H]) WithEquals(equal func(a, b T) bool) *HashSet[T, H] {
+	s.equal = equal
+	return s
+}
+
+// WithKeyNormalizer configures s to pass each hash value through normalize
+// before using it as a key, so that keys are canonicalized consistently at
+// the boundary of s rather than in every HashFunc, e.g. for case-insensitive
+// hostnames:
+//
+//	s := set.NewHashSet[*host, string](10).WithKeyNormalizer(strings.ToLower)
+//
+// The keys given to InsertWithKey, RemoveKey and ContainsKey are normalized
+// likewise, and the keys returned by Keys and KeySet are normalized keys.
+//
+// Sets derived from s (e.g. by Copy or Union) use the same normalize function.
+//
+// Panics if s is not empty, as its existing keys would not be normalized.
+//
+// Returns s.
+func (s *HashSet[T, H]) WithKeyNormalizer(normalize func(H) H) *HashSet[T, H] {
+	if len(s.items) > 0 {
+		panic("set: WithKeyNormalizer of non-empty HashSet")
+	}
+	fn := s.fn
+	s.fn = func(item T) H { return normalize(fn(item)) }
+	s.normalize = normalize
+	return s
+}
+
+// key returns the key of s for the hash value h, as normalized by the
+// normalize function of s, if any.
+func (s *HashSet[T, H]) key(h H) H {
+	if s.normalize != nil {
+		return s.normalize(h)
+	}
+	return h
+}
+
+// lookup returns the hash key of item, and whether an element with that key is
+// present in s, and whether that element collides with item (i.e. is not equal
+// to item according to the equal function of s).
+func (s *HashSet[T, H]) lookup(item T) (key H, exists, collides bool) {
+	key = s.fn(item)
+	existing, exists := s.items[key]
+	collides = exists && s.equal != nil && !s.equal(existing, item)
+	return key, exists, collides
+}
+
+// empty creates an empty HashSet with the same HashFunc and equal function as
+// s, with an underlying capacity of size.
+func (s *HashSet[T, H]) empty(size int) *HashSet[T, H] {
+	result := NewHashSetFunc[T, H](size, s.fn).WithEquals(s.equal)
+	result.normalize = s.normalize
+	return result
+}
+
 // Insert item into s.
 //
 // Return true if s was modified (item was not already in s), false otherwise.
 func (s *HashSet[T, H]) Insert(item T) bool {
-	key := s.fn(item)
-	if _, exists := s.items[key]; exists {
+	key, exists, _ := s.lookup(item)
+	if exists {
 		return false
 	}
 	s.items[key] = item
+	s.version++
+	return true
+}
+
+// TryInsert inserts item into s, as with Insert.
+//
+// Returns an error wrapping ErrHashCollision if s was configured via WithEquals
+// and an element of s has the same hash value as item but is not equal to item.
+func (s *HashSet[T, H]) TryInsert(item T) (bool, error) {
+	key, exists, collides := s.lookup(item)
+	switch {
+	case collides:
+		return false, fmt.Errorf("%w: %v and %v have hash value %v", ErrHashCollision, s.items[key], item, key)
+	case exists:
+		return false, nil
+	}
+	s.items[key] = item
+	s.version++
+	return true, nil
+}
+
+// InsertWithKey inserts item into s with the hash key h, which must be the
+// hash value of item, e.g. as computed upstream and received along with item.
+// This avoids calling the HashFunc of s when it is expensive. Use ContainsKey
+// to check for the presence of an element by its hash key.
+//
+// If h is not the hash value of item, s is left inconsistent. When built with
+// the setdebug build tag, InsertWithKey verifies h and panics if it is wrong.
+//
+// Return true if s was modified (no element with key h was already in s),
+// false otherwise.
+func (s *HashSet[T, H]) InsertWithKey(h H, item T) bool {
+	h = s.key(h)
+	if debug {
+		if key := s.fn(item); key != h {
+			panic(fmt.Sprintf("set: InsertWithKey of %v with key %v, but its hash value is %v", item, h, key))
+		}
+	}
+	if _, exists := s.items[h]; exists {
+		return false
+	}
+	s.items[h] = item
+	s.version++
 	return true
 }
 
@@ -121,11 +263,25 @@ func (s *HashSet[T, H]) InsertSet(col Collection[T]) bool {
 //
 // Return true if s was modified (item was present), false otherwise.
 func (s *HashSet[T, H]) Remove(item T) bool {
-	key := s.fn(item)
-	if _, exists := s.items[key]; !exists {
+	key, exists, collides := s.lookup(item)
+	if !exists || collides {
 		return false
 	}
 	delete(s.items, key)
+	s.version++
+	return true
+}
+
+// RemoveKey will remove the element of s with hash key h.
+//
+// Return true if s was modified (an element with key h was present), false otherwise.
+func (s *HashSet[T, H]) RemoveKey(h H) bool {
+	h = s.key(h)
+	if _, exists := s.items[h]; !exists {
+		return false
+	}
+	delete(s.items, h)
+	s.version++
 	return true
 }
 
@@ -156,20 +312,54 @@ func (s *HashSet[T, H]) RemoveFunc(f func(item T) bool) bool {
 	return removeFunc(s, f)
 }
 
+// RemoveFuncN will remove up to n elements from s that satisfy condition f,
+// e.g. for bounding the work done by each pass of a periodic purge of s.
+//
+// Returns the number of elements removed.
+func (s *HashSet[T, H]) RemoveFuncN(f func(item T) bool, n int) int {
+	return removeFuncN(s, f, n)
+}
+
 // Contains returns whether item is present in s.
 func (s *HashSet[T, H]) Contains(item T) bool {
-	hash := s.fn(item)
-	_, exists := s.items[hash]
+	_, exists, collides := s.lookup(item)
+	return exists && !collides
+}
+
+// ContainsKey returns whether an element with hash key h is present in s.
+//
+// Useful when only the hash of an element is known, e.g. as received from
+// another process.
+func (s *HashSet[T, H]) ContainsKey(h H) bool {
+	_, exists := s.items[s.key(h)]
 	return exists
 }
 
-// ContainsSlice returns whether s contains the same set of of elements
-// that are in items. The elements of items may contain duplicates.
+// ContainsSlice returns whether all elements in items are present in s.
 //
-// If the slice is known to be set-like (no duplicates), EqualSlice provides
-// a more efficient implementation.
+// The items slice may contain duplicates. To detect whether s and items contain
+// the same elements, use EqualSlice or EqualSliceSet.
 func (s *HashSet[T, H]) ContainsSlice(items []T) bool {
-	return s.Equal(HashSetFromFunc[T, H](items, s.fn))
+	return containsSlice(s, items)
+}
+
+// MissingFrom returns the elements of items which are not present in s, e.g. for
+// reporting which of a list of requested identifiers do not exist.
+//
+// The result preserves the order of items, including any duplicates.
+func (s *HashSet[T, H]) MissingFrom(items []T) []T {
+	return missingFrom(s, items)
+}
+
+// ClassifySlice partitions items into those present in s, those absent from
+// s, and duplicates (i.e. each repeated occurrence of an item earlier in
+// items), in a single pass, e.g. for reporting which of a list of requested
+// identifiers are already known, which are new, and which were repeated.
+//
+// Each result preserves the order of items. The first occurrence of an item
+// is in either present or absent, and each later occurrence is in duplicates.
+func (s *HashSet[T, H]) ClassifySlice(items []T) (present, absent, duplicates []T) {
+	return classifySlice(s, s.empty(len(items)), items)
 }
 
 // Subset returns whether col is a subset of s.
@@ -185,6 +375,14 @@ func (s *HashSet[T, H]) ProperSubset(col Collection[T]) bool {
 	return s.Subset(col)
 }
 
+// Clear removes every element from s.
+//
+// The underlying capacity of s is retained for reuse.
+func (s *HashSet[T, H]) Clear() {
+	clear(s.items)
+	s.version++
+}
+
 // Size returns the cardinality of s.
 func (s *HashSet[T, H]) Size() int {
 	return len(s.items)
@@ -199,7 +397,7 @@ func (s *HashSet[T, H]) Empty() bool {
 //
 // Elements in s take priority in the event of colliding hash values.
 func (s *HashSet[T, H]) Union(col Collection[T]) Collection[T] {
-	result := NewHashSetFunc[T, H](s.Size(), s.fn)
+	result := s.empty(s.Size())
 	insert(result, s)
 	insert(result, col)
 	return result
@@ -207,7 +405,7 @@ func (s *HashSet[T, H]) Union(col Collection[T]) Collection[T] {
 
 // Difference returns a set that contains elements of s that are not in col.
 func (s *HashSet[T, H]) Difference(col Collection[T]) Collection[T] {
-	result := NewHashSetFunc[T, H](max(0, s.Size()-col.Size()), s.fn)
+	result := s.empty(max(0, s.Size()-col.Size()))
 	for item := range s.Items() {
 		if !col.Contains(item) {
 			result.Insert(item)
@@ -218,27 +416,201 @@ func (s *HashSet[T, H]) Difference(col Collection[T]) Collection[T] {
 
 // Intersect returns a set that contains elements that are present in both s and col.
 func (s *HashSet[T, H]) Intersect(col Collection[T]) Collection[T] {
-	result := NewHashSetFunc[T, H](0, s.fn)
+	result := s.empty(0)
 	intersect(result, s, col)
 	return result
 }
 
+// UnionCtx returns a set that contains all elements of s and col combined, as
+// with Union, while periodically checking whether ctx has been canceled.
+//
+// If ctx is canceled before the union is complete, the partial result is
+// returned along with an error wrapping ErrPartialResult and the error of ctx.
+func (s *HashSet[T, H]) UnionCtx(ctx context.Context, col Collection[T]) (Collection[T], error) {
+	result := s.empty(s.Size())
+	err := insertCtx(ctx, result, s, col)
+	return result, err
+}
+
+// DifferenceCtx returns a set that contains elements of s that are not in col,
+// as with Difference, while periodically checking whether ctx has been
+// canceled.
+//
+// If ctx is canceled before the difference is complete, the partial result is
+// returned along with an error wrapping ErrPartialResult and the error of ctx.
+func (s *HashSet[T, H]) DifferenceCtx(ctx context.Context, col Collection[T]) (Collection[T], error) {
+	result := s.empty(max(0, s.Size()-col.Size()))
+	err := differenceCtx(ctx, result, s, col)
+	return result, err
+}
+
+// IntersectCtx returns a set that contains elements that are present in both s
+// and col, as with Intersect, while periodically checking whether ctx has been
+// canceled.
+//
+// If ctx is canceled before the intersection is complete, the partial result
+// is returned along with an error wrapping ErrPartialResult and the error of ctx.
+func (s *HashSet[T, H]) IntersectCtx(ctx context.Context, col Collection[T]) (Collection[T], error) {
+	result := s.empty(0)
+	err := intersectCtx(ctx, result, s, col)
+	return result, err
+}
+
+// UnionFunc returns a set that contains the elements of s and col combined
+// which satisfy predicate, as with Union followed by filtering the result,
+// but in a single pass without creating the unfiltered union.
+func (s *HashSet[T, H]) UnionFunc(col Collection[T], predicate func(T) bool) Collection[T] {
+	result := s.empty(0)
+	insertFunc(result, predicate, s, col)
+	return result
+}
+
+// IntersectFunc returns a set that contains the elements present in both s
+// and col which satisfy predicate, as with Intersect followed by filtering the
+// result, but in a single pass without creating the unfiltered intersection.
+func (s *HashSet[T, H]) IntersectFunc(col Collection[T], predicate func(T) bool) Collection[T] {
+	result := s.empty(0)
+	intersectFunc(result, s, col, predicate)
+	return result
+}
+
 // Copy creates a shallow copy of s.
 func (s *HashSet[T, H]) Copy() *HashSet[T, H] {
-	result := NewHashSetFunc[T, H](s.Size(), s.fn)
+	result := s.empty(s.Size())
 	for key, item := range s.items {
 		result.items[key] = item
 	}
 	return result
 }
 
+// CopyFunc creates a deep copy of s, where each element of the copy is created
+// by passing an element of s through clone.
+//
+// The elements of the copy are hashed anew, and so if clone produces elements
+// with the same hash value for two elements of s, the copy contains fewer
+// elements than s.
+func (s *HashSet[T, H]) CopyFunc(clone func(T) T) *HashSet[T, H] {
+	result := s.empty(s.Size())
+	for _, item := range s.items {
+		result.Insert(clone(item))
+	}
+	return result
+}
+
+// SplitN partitions the elements of s into k sets of roughly equal size, e.g.
+// for distributing work items across k workers. The sizes of the resulting
+// sets differ by at most one.
+//
+// The partition is deterministic, such that sets with elements of the same
+// hash values are always split in the same way.
+//
+// Panics if k is less than 1.
+func (s *HashSet[T, H]) SplitN(k int) []*HashSet[T, H] {
+	checkSplitN(k)
+	parts := make([]*HashSet[T, H], k)
+	for i := range parts {
+		parts[i] = s.empty(s.Size()/k + 1)
+	}
+	items := seededOrder(s.Slice(), func(item T) string {
+		return fmt.Sprintf("%v", s.fn(item))
+	}, 0)
+	for i, item := range items {
+		parts[i%k].Insert(item)
+	}
+	return parts
+}
+
 // Slice creates a copy of s as a slice.
 //
 // The result is not ordered.
 func (s *HashSet[T, H]) Slice() []T {
-	result := make([]T, 0, s.Size())
+	return s.AppendSlice(make([]T, 0, s.Size()))
+}
+
+// AppendSlice appends the elements of s to dst and returns the extended slice,
+// e.g. for reusing the same buffer across calls rather than allocating a new
+// slice via Slice each time.
+//
+// Elements are in no particular order.
+func (s *HashSet[T, H]) AppendSlice(dst []T) []T {
+	dst = slices.Grow(dst, s.Size())
 	for _, item := range s.items {
-		result = append(result, item)
+		dst = append(dst, item)
+	}
+	return dst
+}
+
+// Keys creates a slice of the hash keys of the elements of s.
+//
+// The result is not ordered.
+func (s *HashSet[T, H]) Keys() []H {
+	result := make([]H, 0, s.Size())
+	for key := range s.items {
+		result = append(result, key)
+	}
+	return result
+}
+
+// ForEachKV calls visit for each element of s along with its hash key, e.g. to
+// synchronize s with an external key-value store. Iteration stops early if
+// visit returns false. Elements are visited in no particular order.
+//
+// The hash keys are those stored in s, and so the HashFunc of s is not called.
+// As with Items, elements removed from s by visit are not visited if not yet
+// reached, and elements inserted by visit may or may not be visited.
+func (s *HashSet[T, H]) ForEachKV(visit func(key H, element T) bool) {
+	for key, item := range s.items {
+		if !visit(key, item) {
+			return
+		}
+	}
+}
+
+// ForEachSortedByKey calls visit for each element of s, in ascending order of
+// the hash keys of the elements. Iteration stops early if visit returns false.
+//
+// This provides a deterministic order without converting s into a TreeSet,
+// which is useful when the hash key of each element already encodes the
+// desired order. The keys of s are collected and sorted before the first
+// call to visit, in O(n log n) time. Elements removed from s by visit are not
+// visited, and elements inserted by visit are not visited.
+func (s *HashSet[T, H]) ForEachSortedByKey(visit func(element T) bool) {
+	keys := s.Keys()
+	slices.Sort(keys)
+	for _, key := range keys {
+		item, exists := s.items[key]
+		if !exists {
+			// removed by visit
+			continue
+		}
+		if !visit(item) {
+			return
+		}
+	}
+}
+
+// SliceSortedByKey creates a copy of s as a slice, in ascending order of the
+// hash keys of the elements.
+func (s *HashSet[T, H]) SliceSortedByKey() []T {
+	result := make([]T, 0, s.Size())
+	s.ForEachSortedByKey(func(element T) bool {
+		result = append(result, element)
+		return true
+	})
+	return result
+}
+
+// KeySet creates a Set of the hash keys of the elements of s.
+//
+// The result is a snapshot; later modifications to s are not reflected in it.
+// KeySet enables set algebra over the keys of HashSets of different element
+// types which share a hash key type, e.g.
+//
+//	common := a.KeySet().Intersect(b.KeySet())
+func (s *HashSet[T, H]) KeySet() *Set[H] {
+	result := New[H](s.Size())
+	for key := range s.items {
+		result.items[key] = sentinel
 	}
 	return result
 }
@@ -276,6 +648,17 @@ func (s *HashSet[T, H]) Equal(o *HashSet[T, H]) bool {
 	return true
 }
 
+// ContentHash returns a hash of the elements of s which does not depend on
+// their order, combining the hash of each element as produced by hash, e.g.
+// for cheaply comparing sets of different types or in different processes.
+//
+// Sets containing the same elements have the same content hash regardless of
+// their type or internal layout. Sets containing different elements are
+// unlikely, but not guaranteed, to have different content hashes.
+func (s *HashSet[T, H]) ContentHash(hash func(T) uint64) uint64 {
+	return contentHash(s, hash)
+}
+
 // EqualSet returns whether s and col contain the same elements.
 func (s *HashSet[T, H]) EqualSet(col Collection[T]) bool {
 	return equalSet(s, col)
@@ -294,38 +677,43 @@ func (s *HashSet[T, H]) EqualSlice(items []T) bool {
 	return s.Equal(other)
 }
 
-// EqualSliceSet returns whether s and items contain exactly the same elements.
-//
-// If items contains duplicates EqualSliceSet will return false. The elements of
-// items are assumed to be set-like. For comparing s to a slice that may contain
-// duplicate elements, use EqualSlice instead.
+// EqualSliceSet returns whether s and items contain exactly the same elements,
+// where the elements of items are expected to be set-like. If items contains
+// duplicates EqualSliceSet returns false. For comparing s to a slice that may
+// contain duplicate elements, use EqualSlice instead.
 //
 // To detect if a slice is a subset of s, use ContainsSlice.
 func (s *HashSet[T, H]) EqualSliceSet(items []T) bool {
-	if len(items) != s.Size() {
-		return false
-	}
-	for _, item := range items {
-		if !s.Contains(item) {
-			return false
-		}
-	}
-	return true
+	return equalSliceSet(s, s.empty(len(items)), items)
 }
 
 // MarshalJSON implements the json.Marshaler interface.
+//
+// Elements are encoded in no particular order. Use CanonicalJSON for an
+// encoding that is identical for identical sets.
 func (s *HashSet[T, H]) MarshalJSON() ([]byte, error) {
 	return marshalJSON[T](s)
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// The set must have been created by NewHashSet or NewHashSetFunc, as the zero
+// value has no HashFunc. Use UnmarshalJSONSets or UnmarshalJSONSetMap for
+// decoding nested sets.
 func (s *HashSet[T, H]) UnmarshalJSON(data []byte) error {
+	if s.fn == nil {
+		return errNoConstructor("HashSet")
+	}
 	return unmarshalJSON[T](s, data)
 }
 
 // Items returns a generator function for iterating each element in s by using
 // the range keyword.
 //
+// Elements of s may be removed during iteration, and will not be produced if
+// not yet reached. Elements inserted during iteration may or may not be
+// produced. Use Snapshot to iterate a fixed view of s instead.
+//
 //	for element := range s.Items() { ... }
 func (s *HashSet[T, H]) Items() iter.Seq[T] {
 	return func(yield func(T) bool) {
@@ -336,3 +724,27 @@ func (s *HashSet[T, H]) Items() iter.Seq[T] {
 		}
 	}
 }
+
+// IterOrderSeed returns a generator function for iterating each element in s
+// by using the range keyword, in a pseudo-random order determined by seed.
+//
+// Unlike Items, the order is the same each time s is iterated with the same
+// seed, for as long as s contains elements with the same hash values. This is
+// useful for reproducing test failures, or for round-robin selection that
+// should not always favor the same elements.
+//
+// The elements of s are copied when iteration begins, and s may be modified
+// during iteration without affecting the elements produced.
+//
+//	for element := range s.IterOrderSeed(42) { ... }
+func (s *HashSet[T, H]) IterOrderSeed(seed int64) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range seededOrder(s.Slice(), func(item T) string {
+			return fmt.Sprintf("%v", s.fn(item))
+		}, seed) {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build setdebug
+
+package set
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestHashSet_debug_InsertWithKey(t *testing.T) {
+	s := NewHashSet[*company, string](0)
+	must.True(t, s.InsertWithKey("street:1", c1))
+
+	message := func() (message any) {
+		defer func() { message = recover() }()
+		s.InsertWithKey("street:9", c2)
+		return
+	}()
+	must.Eq(t, "set: InsertWithKey of <street 2> with key street:9, but its hash value is street:2", message)
+	must.Size(t, 1, s)
+}
@@ -93,6 +93,29 @@ func TestHashSet_New(t *testing.T) {
 	})
 }
 
+func TestHashSet_Of(t *testing.T) {
+	t.Run("of none", func(t *testing.T) {
+		s := HashSetOf[*company, string]()
+		must.MapEmpty(t, s.items)
+	})
+
+	t.Run("of some", func(t *testing.T) {
+		s := HashSetOf[*company, string](c1, c2, c1)
+		must.MapContainsKeys(t, s.items, []string{"street:1", "street:2"})
+		must.Size(t, 2, s)
+	})
+}
+
+func TestHashSet_infer(t *testing.T) {
+	// the hash type is inferred from the Hash method of *company
+	a := NewHashSet[*company](0)
+	b := HashSetFrom([]*company{c1, c2})
+	c := HashSetOf(c1, c2)
+
+	var _ *HashSet[*company, string] = a
+	must.True(t, b.Equal(c))
+}
+
 func TestHashSet_Insert(t *testing.T) {
 	t.Run("one", func(t *testing.T) {
 		s := NewHashSet[*company, string](1)
@@ -118,6 +141,88 @@ func TestHashSet_Insert(t *testing.T) {
 	})
 }
 
+func TestHashSet_WithKeyNormalizer(t *testing.T) {
+	identity := func(s string) string { return s }
+
+	s := NewHashSetFunc[string, string](0, identity).WithKeyNormalizer(strings.ToLower)
+	must.True(t, s.Insert("Example.COM"))
+	must.False(t, s.Insert("example.com"))
+	must.Contains[string](t, "EXAMPLE.com", s)
+	must.Eq(t, []string{"example.com"}, s.Keys())
+	must.Eq(t, []string{"Example.COM"}, s.Slice())
+
+	// keys given directly are normalized
+	must.True(t, s.ContainsKey("EXAMPLE.COM"))
+	must.False(t, s.InsertWithKey("Example.com", "Example.com"))
+	must.True(t, s.InsertWithKey("HashiCorp.com", "HashiCorp.com"))
+	must.True(t, s.RemoveKey("HASHICORP.COM"))
+
+	// derived sets normalize keys likewise
+	union := s.Union(From([]string{"EXAMPLE.COM", "Other.com"}))
+	must.Eq(t, []string{"Example.COM", "Other.com"}, SortedSlice(union))
+	must.True(t, s.Copy().ContainsKey("example.COM"))
+
+	defer func() {
+		must.Eq(t, "set: WithKeyNormalizer of non-empty HashSet", recover())
+	}()
+	s.WithKeyNormalizer(strings.ToUpper)
+}
+
+func TestHashSet_WithEquals(t *testing.T) {
+	// a faulty hash function, ignoring the floor
+	byAddress := func(c *company) string { return c.address }
+	equal := (*company).Equal
+
+	t.Run("without equals", func(t *testing.T) {
+		s := NewHashSetFunc[*company, string](0, byAddress)
+		must.True(t, s.Insert(c1))
+		must.False(t, s.Insert(c2))
+		must.Contains[*company](t, c2, s)
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		s := NewHashSetFunc[*company, string](0, byAddress).WithEquals(equal)
+		must.True(t, s.Insert(c1))
+		must.False(t, s.Insert(c2))
+		must.False(t, s.Insert(&company{address: "street", floor: 1}))
+		must.Size(t, 1, s)
+	})
+
+	t.Run("try insert", func(t *testing.T) {
+		s := NewHashSetFunc[*company, string](0, byAddress).WithEquals(equal)
+		modified, err := s.TryInsert(c1)
+		must.NoError(t, err)
+		must.True(t, modified)
+
+		modified, err = s.TryInsert(c1)
+		must.NoError(t, err)
+		must.False(t, modified)
+
+		modified, err = s.TryInsert(c2)
+		must.ErrorIs(t, err, ErrHashCollision)
+		must.EqError(t, err, "set: hash collision: <street 1> and <street 2> have hash value street")
+		must.False(t, modified)
+	})
+
+	t.Run("contains and remove", func(t *testing.T) {
+		s := NewHashSetFunc[*company, string](0, byAddress).WithEquals(equal)
+		s.Insert(c1)
+		must.Contains[*company](t, c1, s)
+		must.NotContains[*company](t, c2, s)
+		must.False(t, s.Remove(c2))
+		must.Size(t, 1, s)
+		must.True(t, s.Remove(c1))
+		must.Empty(t, s)
+	})
+
+	t.Run("derived", func(t *testing.T) {
+		s := NewHashSetFunc[*company, string](0, byAddress).WithEquals(equal)
+		s.Insert(c1)
+		must.NotContains[*company](t, c2, s.Copy())
+		must.NotContains[*company](t, c2, s.Union(s))
+	})
+}
+
 func TestHashSet_InsertSlice(t *testing.T) {
 	t.Run("insert none", func(t *testing.T) {
 		empty := NewHashSet[*company, string](0)
@@ -178,6 +283,17 @@ func TestHashSet_Remove(t *testing.T) {
 	})
 }
 
+func TestHashSet_RemoveKey(t *testing.T) {
+	s := HashSetFrom[*company, string]([]*company{c1, c2, c3})
+	must.True(t, s.RemoveKey("street:2"))
+	must.False(t, s.RemoveKey("street:2"))
+	must.False(t, s.RemoveKey("street:4"))
+	must.MapContainsKeys(t, s.items, []string{
+		"street:1", "street:3",
+	})
+	must.Size(t, 2, s)
+}
+
 func TestHashSet_RemoveSlice(t *testing.T) {
 	t.Run("empty remove all", func(t *testing.T) {
 		s := NewHashSet[*company, string](0)
@@ -268,6 +384,15 @@ func TestHashSet_RemoveFunc(t *testing.T) {
 	})
 }
 
+func TestHashSet_RemoveFuncN(t *testing.T) {
+	s := HashSetFrom[*company, string]([]*company{c1, c2, c3, c4, c5})
+	odd := func(c *company) bool { return c.floor%2 == 1 }
+	must.Eq(t, 2, s.RemoveFuncN(odd, 2))
+	must.Size(t, 3, s)
+	must.Eq(t, 1, s.RemoveFuncN(odd, 2))
+	must.True(t, s.EqualSlice([]*company{c2, c4}))
+}
+
 func TestHashSet_Contains(t *testing.T) {
 	t.Run("empty contains", func(t *testing.T) {
 		a := NewHashSet[*company, string](0)
@@ -285,6 +410,35 @@ func TestHashSet_Contains(t *testing.T) {
 	})
 }
 
+func TestHashSet_ContainsKey(t *testing.T) {
+	s := HashSetFrom[*company, string]([]*company{c1, c2})
+	must.True(t, s.ContainsKey("street:1"))
+	must.True(t, s.ContainsKey("street:2"))
+	must.False(t, s.ContainsKey("street:3"))
+	must.False(t, NewHashSet[*company, string](0).ContainsKey("street:1"))
+}
+
+func TestHashSet_InsertWithKey(t *testing.T) {
+	calls := 0
+	s := NewHashSetFunc[*company, string](0, func(c *company) string {
+		calls++
+		return c.Hash()
+	})
+
+	must.True(t, s.InsertWithKey("street:1", c1))
+	must.True(t, s.InsertWithKey("street:2", c2))
+	must.False(t, s.InsertWithKey("street:1", c1))
+	must.Size(t, 2, s)
+	must.True(t, s.ContainsKey("street:2"))
+	if !debug {
+		must.Zero(t, calls)
+	}
+
+	// elements are found by their hash value as usual
+	must.True(t, s.Contains(c1))
+	must.False(t, s.Insert(c2))
+}
+
 func TestHashSet_ContainsSlice(t *testing.T) {
 	t.Run("empty empty", func(t *testing.T) {
 		a := NewHashSet[*company, string](0)
@@ -301,7 +455,7 @@ func TestHashSet_ContainsSlice(t *testing.T) {
 	t.Run("some empty", func(t *testing.T) {
 		a := HashSetFrom[*company, string]([]*company{c1, c2, c3})
 		b := make([]*company, 0)
-		must.False(t, a.ContainsSlice(b))
+		must.True(t, a.ContainsSlice(b))
 	})
 
 	t.Run("equal", func(t *testing.T) {
@@ -319,7 +473,7 @@ func TestHashSet_ContainsSlice(t *testing.T) {
 	t.Run("subset", func(t *testing.T) {
 		a := HashSetFrom[*company, string]([]*company{c1, c2, c3, c4, c5})
 		b := []*company{c2, c3, c4}
-		must.False(t, a.ContainsSlice(b))
+		must.True(t, a.ContainsSlice(b))
 	})
 
 	t.Run("superset", func(t *testing.T) {
@@ -612,6 +766,21 @@ func TestHashSet_Copy(t *testing.T) {
 	})
 }
 
+func TestHashSet_CopyFunc(t *testing.T) {
+	a := HashSetFrom[*company, string]([]*company{c1, c2})
+	b := a.CopyFunc(func(c *company) *company {
+		return &company{address: c.address, floor: c.floor}
+	})
+	must.True(t, a.Equal(b))
+
+	// mutating an element of b does not affect a
+	for c := range b.Items() {
+		c.address = "avenue"
+	}
+	must.Eq(t, "street", c1.address)
+	must.Eq(t, "street", c2.address)
+}
+
 func TestHashSet_Slice(t *testing.T) {
 	t.Run("slice empty", func(t *testing.T) {
 		a := NewHashSet[*company, string](10)
@@ -628,6 +797,117 @@ func TestHashSet_Slice(t *testing.T) {
 	})
 }
 
+func TestHashSet_AppendSlice(t *testing.T) {
+	s := HashSetFrom[*company, string]([]*company{c1, c2})
+	buf := s.AppendSlice([]*company{c3})
+	must.SliceContainsAll(t, []*company{c1, c2, c3}, buf)
+	must.Eq(t, c3, buf[0])
+}
+
+func TestHashSet_Keys(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		s := NewHashSet[*company, string](0)
+		must.SliceEmpty(t, s.Keys())
+	})
+
+	t.Run("set", func(t *testing.T) {
+		s := HashSetFrom[*company, string]([]*company{c1, c2, c3})
+		must.SliceContainsAll(t, []string{"street:1", "street:2", "street:3"}, s.Keys())
+	})
+}
+
+func TestHashSet_SortedByKey(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		s := NewHashSet[*company, string](0)
+		must.SliceEmpty(t, s.SliceSortedByKey())
+	})
+
+	t.Run("sorted", func(t *testing.T) {
+		s := HashSetFrom[*company, string]([]*company{c3, c1, c5, c2, c4})
+		must.Eq(t, []*company{c1, c2, c3, c4, c5}, s.SliceSortedByKey())
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		s := HashSetFrom[*company, string]([]*company{c3, c1, c2})
+		var visited []*company
+		s.ForEachSortedByKey(func(c *company) bool {
+			visited = append(visited, c)
+			return len(visited) < 2
+		})
+		must.Eq(t, []*company{c1, c2}, visited)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		s := HashSetFrom[*company, string]([]*company{c3, c1, c2})
+		var visited []*company
+		s.ForEachSortedByKey(func(c *company) bool {
+			visited = append(visited, c)
+			s.Remove(c2)
+			return true
+		})
+		must.Eq(t, []*company{c1, c3}, visited)
+	})
+}
+
+func TestHashSet_ForEachKV(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		s := NewHashSet[*company, string](0)
+		s.ForEachKV(func(string, *company) bool {
+			t.Fatal("visit called on empty set")
+			return true
+		})
+	})
+
+	t.Run("pairs", func(t *testing.T) {
+		calls := 0
+		s := NewHashSetFunc[*company, string](3, func(c *company) string {
+			calls++
+			return c.Hash()
+		})
+		s.InsertSlice([]*company{c1, c2, c3})
+		calls = 0
+
+		visited := make(map[string]*company)
+		s.ForEachKV(func(key string, c *company) bool {
+			visited[key] = c
+			return true
+		})
+		must.MapEq(t, map[string]*company{
+			"street:1": c1,
+			"street:2": c2,
+			"street:3": c3,
+		}, visited)
+		must.Zero(t, calls)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		s := HashSetFrom[*company, string]([]*company{c1, c2, c3})
+		visited := 0
+		s.ForEachKV(func(string, *company) bool {
+			visited++
+			return visited < 2
+		})
+		must.Eq(t, 2, visited)
+	})
+}
+
+func TestHashSet_KeySet(t *testing.T) {
+	s := HashSetFrom[*company, string]([]*company{c1, c2, c3})
+	keys := s.KeySet()
+	must.True(t, keys.EqualSlice([]string{"street:1", "street:2", "street:3"}))
+
+	// keys is a snapshot of s
+	s.Remove(c1)
+	must.Size(t, 3, keys)
+
+	// intersect the keys of sets with different element types
+	other := HashSetFromFunc([]int{2, 3, 4}, func(i int) string {
+		return fmt.Sprintf("street:%d", i)
+	})
+	common := keys.Intersect(other.KeySet())
+	must.True(t, common.EqualSlice([]string{"street:2", "street:3"}))
+}
+
 func TestHashSet_String(t *testing.T) {
 	a := HashSetFrom[*company, string]([]*company{c2, c1})
 	result := a.String()
@@ -738,3 +1018,23 @@ func TestHashSet_Items(t *testing.T) {
 
 	must.Eq(t, 6, sum)
 }
+
+func TestHashSet_SplitN(t *testing.T) {
+	s := HashSetFrom[*company, string]([]*company{c1, c2, c3, c4, c5})
+
+	parts := s.SplitN(2)
+	must.SliceLen(t, 2, parts)
+	must.Eq(t, 5, parts[0].Size()+parts[1].Size())
+	must.Between(t, 2, parts[0].Size(), 3)
+	must.True(t, s.Equal(parts[0].Union(parts[1]).(*HashSet[*company, string])))
+
+	again := s.Copy().SplitN(2)
+	must.True(t, parts[0].Equal(again[0]))
+	must.True(t, parts[1].Equal(again[1]))
+
+	for _, k := range []int{0, -1} {
+		msg := fmt.Sprintf("set: SplitN into %d sets, must be at least 1", k)
+		must.Eq[any](t, msg, recovered(func() { s.SplitN(k) }))
+		must.Eq[any](t, msg, recovered(func() { NewHashSet[*company, string](0).SplitN(k) }))
+	}
+}
@@ -0,0 +1,24 @@
+module github.com/hashicorp/go-set/v3/hclset
+
+go 1.23
+
+require (
+	github.com/hashicorp/go-set/v3 v3.0.0
+	github.com/hashicorp/hcl/v2 v2.23.0
+	github.com/shoenig/test v1.12.0
+	github.com/zclconf/go-cty v1.13.0
+)
+
+require (
+	github.com/agext/levenshtein v1.2.1 // indirect
+	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
+	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
+	github.com/google/go-cmp v0.6.0 // indirect
+	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
+	golang.org/x/mod v0.17.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	golang.org/x/tools v0.21.1-0.20240508182429-e35e4ccd0d2d // indirect
+)
+
+replace github.com/hashicorp/go-set/v3 => ../
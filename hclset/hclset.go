@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package hclset provides decoding of HCL list and set attributes into the
+// sets of package set.
+//
+// The gohcl package decodes attributes into Go types via gocty, which has no
+// support for custom types such as set.Set. Instead, an attribute which should
+// be decoded into a set is declared as an hcl.Expression, and decoded after
+// the enclosing block via Decode.
+//
+//	type Config struct {
+//	  Datacenters hcl.Expression `hcl:"datacenters"`
+//	}
+//
+//	var config Config
+//	diags := gohcl.DecodeBody(body, ctx, &config)
+//	datacenters := set.New[string](0)
+//	diags = diags.Extend(hclset.Decode(config.Datacenters, ctx, datacenters))
+package hclset
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-set/v3"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+)
+
+// Decode evaluates expr in ctx as a list, set, or tuple, and inserts each of
+// its elements into col after converting it to a T via gocty.
+//
+// Duplicate elements are inserted only once, and each duplicate is reported
+// as a warning diagnostic. When expr is a static list (e.g. a literal tuple
+// such as ["a", "b"]), diagnostics refer to the source range of the element
+// at fault rather than the expression as a whole.
+//
+// Elements are inserted into col in order, up until the first element which
+// cannot be converted to a T.
+func Decode[T any](expr hcl.Expression, ctx *hcl.EvalContext, col set.Collection[T]) hcl.Diagnostics {
+	exprs, diags := hcl.ExprList(expr)
+	if diags.HasErrors() {
+		// not a static list, e.g. a variable reference or a function call
+		return decodeValue(expr, ctx, col)
+	}
+
+	for i, e := range exprs {
+		value, valueDiags := e.Value(ctx)
+		diags = diags.Extend(valueDiags)
+		if valueDiags.HasErrors() {
+			return diags
+		}
+		diags = diags.Extend(insert(col, value, i, e.Range(), expr.Range()))
+		if diags.HasErrors() {
+			return diags
+		}
+	}
+	return diags
+}
+
+// decodeValue decodes the elements of an expression that is not a static list.
+func decodeValue[T any](expr hcl.Expression, ctx *hcl.EvalContext, col set.Collection[T]) hcl.Diagnostics {
+	value, diags := expr.Value(ctx)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	switch {
+	case value.IsNull():
+		return diags
+	case !value.IsKnown():
+		return diags.Append(&hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     "Unknown value",
+			Detail:      "The elements of a set must be known.",
+			Subject:     expr.Range().Ptr(),
+			Expression:  expr,
+			EvalContext: ctx,
+		})
+	case !value.CanIterateElements() || value.Type().IsMapType() || value.Type().IsObjectType():
+		return diags.Append(&hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     "Unsuitable value type",
+			Detail:      fmt.Sprintf("Unsuitable value: a list, set, or tuple is required, not %s.", value.Type().FriendlyName()),
+			Subject:     expr.Range().Ptr(),
+			Expression:  expr,
+			EvalContext: ctx,
+		})
+	}
+
+	for it, i := value.ElementIterator(), 0; it.Next(); i++ {
+		_, element := it.Element()
+		diags = diags.Extend(insert(col, element, i, expr.Range(), expr.Range()))
+		if diags.HasErrors() {
+			return diags
+		}
+	}
+	return diags
+}
+
+// insert converts value to a T and inserts it into col, where value is the
+// element at index i of a list at context, and subject is the source of value.
+func insert[T any](col set.Collection[T], value cty.Value, i int, subject, context hcl.Range) hcl.Diagnostics {
+	var item T
+	if err := gocty.FromCtyValue(value, &item); err != nil {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Unsuitable set element",
+			Detail:   fmt.Sprintf("Unsuitable value for element %d: %s.", i, err),
+			Subject:  subject.Ptr(),
+			Context:  context.Ptr(),
+		}}
+	}
+
+	if !col.Insert(item) {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagWarning,
+			Summary:  "Duplicate set element",
+			Detail:   fmt.Sprintf("Element %d (%v) is already present in the set and is ignored.", i, item),
+			Subject:  subject.Ptr(),
+			Context:  context.Ptr(),
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hclset
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/hashicorp/go-set/v3"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/shoenig/test/must"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type config struct {
+	Datacenters hcl.Expression `hcl:"datacenters"`
+}
+
+// parse decodes src into a config with gohcl.
+func parse(t *testing.T, src string) config {
+	file, diags := hclsyntax.ParseConfig([]byte(src), "test.hcl", hcl.InitialPos)
+	must.False(t, diags.HasErrors(), must.Sprint(diags))
+
+	var c config
+	diags = gohcl.DecodeBody(file.Body, nil, &c)
+	must.False(t, diags.HasErrors(), must.Sprint(diags))
+	return c
+}
+
+func TestDecode_static(t *testing.T) {
+	c := parse(t, `datacenters = ["dc2", "dc1", "dc3"]`)
+
+	s := set.New[string](0)
+	diags := Decode(c.Datacenters, nil, s)
+	must.Len(t, 0, diags)
+	must.Eq(t, set.From([]string{"dc1", "dc2", "dc3"}), s)
+
+	ts := set.NewTreeSet[string](cmp.Compare[string])
+	diags = Decode(c.Datacenters, nil, ts)
+	must.Len(t, 0, diags)
+	must.Eq(t, []string{"dc1", "dc2", "dc3"}, ts.Slice())
+}
+
+func TestDecode_duplicates(t *testing.T) {
+	c := parse(t, `datacenters = ["dc1", "dc2", "dc1"]`)
+
+	s := set.New[string](0)
+	diags := Decode(c.Datacenters, nil, s)
+	must.False(t, diags.HasErrors())
+	must.Len(t, 1, diags)
+	must.Eq(t, hcl.DiagWarning, diags[0].Severity)
+	must.Eq(t, "Duplicate set element", diags[0].Summary)
+	must.StrContains(t, diags[0].Detail, "Element 2 (dc1)")
+	// the subject is the duplicate element itself
+	must.Eq(t, 30, diags[0].Subject.Start.Column)
+	must.Eq(t, 35, diags[0].Subject.End.Column)
+	must.Eq(t, set.From([]string{"dc1", "dc2"}), s)
+}
+
+func TestDecode_variable(t *testing.T) {
+	c := parse(t, `datacenters = var.dcs`)
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(map[string]cty.Value{
+				"dcs": cty.ListVal([]cty.Value{cty.NumberIntVal(2), cty.NumberIntVal(1), cty.NumberIntVal(2)}),
+			}),
+		},
+	}
+
+	s := set.New[int](0)
+	diags := Decode(c.Datacenters, ctx, s)
+	must.False(t, diags.HasErrors())
+	must.Len(t, 1, diags)
+	must.Eq(t, "Duplicate set element", diags[0].Summary)
+	must.Eq(t, set.From([]int{1, 2}), s)
+}
+
+func TestDecode_null(t *testing.T) {
+	c := parse(t, `datacenters = null`)
+
+	s := set.New[string](0)
+	diags := Decode(c.Datacenters, nil, s)
+	must.Len(t, 0, diags)
+	must.Empty(t, s)
+}
+
+func TestDecode_errors(t *testing.T) {
+	t.Run("element type", func(t *testing.T) {
+		c := parse(t, `datacenters = [1, "two", 3]`)
+
+		s := set.New[int](0)
+		diags := Decode(c.Datacenters, nil, s)
+		must.True(t, diags.HasErrors())
+		must.Eq(t, "Unsuitable set element", diags[0].Summary)
+		must.StrContains(t, diags[0].Detail, "element 1")
+		must.Eq(t, set.From([]int{1}), s)
+	})
+
+	t.Run("not a list", func(t *testing.T) {
+		c := parse(t, `datacenters = { dc1 = true }`)
+
+		s := set.New[string](0)
+		diags := Decode(c.Datacenters, nil, s)
+		must.True(t, diags.HasErrors())
+		must.Eq(t, "Unsuitable value type", diags[0].Summary)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		c := parse(t, `datacenters = var.dcs`)
+		ctx := &hcl.EvalContext{
+			Variables: map[string]cty.Value{
+				"var": cty.ObjectVal(map[string]cty.Value{
+					"dcs": cty.UnknownVal(cty.List(cty.String)),
+				}),
+			},
+		}
+
+		s := set.New[string](0)
+		diags := Decode(c.Datacenters, ctx, s)
+		must.True(t, diags.HasErrors())
+		must.Eq(t, "Unknown value", diags[0].Summary)
+	})
+}
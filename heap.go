@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import "container/heap"
+
+// Heap is a binary heap of elements ordered by a less function, implementing
+// heap.Interface for use with the functions of container/heap, e.g.
+//
+//	h := set.InitHeapFrom(s, func(a, b *job) bool { return a.priority > b.priority })
+//	for h.Len() > 0 {
+//	  next := heap.Pop(h).(*job)
+//	  ...
+//	}
+//
+// A Heap is not a set; an element pushed more than once is contained more
+// than once.
+type Heap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+// InitHeapFrom creates a Heap containing each element of col, ordered such
+// that the least element according to less is at the top of the heap. Use a
+// less function which reports whether a is greater than b for a max-heap.
+//
+// The heap is established in O(n) time, which is faster than sorting the
+// elements of col.
+func InitHeapFrom[T any](col Collection[T], less func(a, b T) bool) *Heap[T] {
+	h := &Heap[T]{
+		items: col.Slice(),
+		less:  less,
+	}
+	heap.Init(h)
+	return h
+}
+
+// Peek returns the element at the top of h without removing it.
+//
+// Must not be called on an empty heap.
+func (h *Heap[T]) Peek() T {
+	return h.items[0]
+}
+
+// Len implements heap.Interface.
+func (h *Heap[T]) Len() int {
+	return len(h.items)
+}
+
+// Less implements heap.Interface.
+func (h *Heap[T]) Less(i, j int) bool {
+	return h.less(h.items[i], h.items[j])
+}
+
+// Swap implements heap.Interface.
+func (h *Heap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+// Push implements heap.Interface. Use heap.Push to push an element onto h.
+func (h *Heap[T]) Push(x any) {
+	h.items = append(h.items, x.(T))
+}
+
+// Pop implements heap.Interface. Use heap.Pop to pop an element from h.
+func (h *Heap[T]) Pop() any {
+	last := len(h.items) - 1
+	item := h.items[last]
+	var zero T
+	h.items[last] = zero
+	h.items = h.items[:last]
+	return item
+}
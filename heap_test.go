@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"container/heap"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestInitHeapFrom(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	t.Run("empty", func(t *testing.T) {
+		h := InitHeapFrom[int](New[int](0), less)
+		must.Zero(t, h.Len())
+	})
+
+	t.Run("min", func(t *testing.T) {
+		h := InitHeapFrom[int](Of(5, 3, 8, 1, 9), less)
+		must.Eq(t, 1, h.Peek())
+
+		var result []int
+		for h.Len() > 0 {
+			result = append(result, heap.Pop(h).(int))
+		}
+		must.Eq(t, []int{1, 3, 5, 8, 9}, result)
+	})
+
+	t.Run("max", func(t *testing.T) {
+		h := InitHeapFrom[int](Of(5, 3, 8), func(a, b int) bool { return a > b })
+		must.Eq(t, 8, heap.Pop(h).(int))
+		heap.Push(h, 4)
+		heap.Push(h, 7)
+		must.Eq(t, 7, heap.Pop(h).(int))
+		must.Eq(t, 5, heap.Pop(h).(int))
+		must.Eq(t, 4, heap.Pop(h).(int))
+		must.Eq(t, 3, heap.Pop(h).(int))
+	})
+}
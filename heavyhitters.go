@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"hash/fnv"
+	"slices"
+)
+
+// HeavyHitters identifies the most frequent elements of a stream of elements,
+// using a bounded amount of memory regardless of the number of distinct
+// elements in the stream.
+//
+// The frequency of each element is estimated by a Count-Min sketch, and the
+// elements with the greatest estimated frequencies are retained as candidates.
+// Estimates may exceed the true frequency of an element (due to collisions in
+// the sketch) but never fall short of it. A wider sketch reduces the magnitude
+// of overestimates, and a deeper sketch reduces their likelihood.
+//
+// Elements are hashed by their "%#v" printf formatting, so elements which are
+// equal always share columns of the sketch. Formatting each element dominates
+// the cost of Offer for primitive types such as int or string, which a
+// dedicated sketch hashing their bytes directly would avoid.
+//
+// Not thread safe.
+//
+// https://en.wikipedia.org/wiki/Count–min_sketch
+type HeavyHitters[T comparable] struct {
+	width      uint64
+	sketch     [][]uint64
+	candidates candidates[T]
+	size       int
+}
+
+// NewHeavyHitters creates a HeavyHitters retaining up to size candidates, with
+// a Count-Min sketch of depth rows of width counters each.
+//
+// Panics if size, width, or depth is less than 1.
+func NewHeavyHitters[T comparable](size, width, depth int) *HeavyHitters[T] {
+	if size < 1 || width < 1 || depth < 1 {
+		panic(fmt.Sprintf("heavy hitters: invalid dimensions (size %d, width %d, depth %d)", size, width, depth))
+	}
+	sketch := make([][]uint64, depth)
+	for i := range sketch {
+		sketch[i] = make([]uint64, width)
+	}
+	return &HeavyHitters[T]{
+		width:  uint64(width),
+		sketch: sketch,
+		candidates: candidates[T]{
+			index: make(map[T]int, size),
+		},
+		size: size,
+	}
+}
+
+// columns returns the column of the sketch for item in each row, derived from
+// two hashes of item via double hashing.
+func (h *HeavyHitters[T]) columns(item T) func(row int) uint64 {
+	f := fnv.New64a()
+	_, _ = fmt.Fprintf(f, "%#v", item)
+	h1 := mix(f.Sum64())
+	h2 := mix(h1) | 1
+	return func(row int) uint64 {
+		return (h1 + uint64(row)*h2) % h.width
+	}
+}
+
+// Offer records an occurrence of item in the stream, returning the estimated
+// frequency of item including this occurrence.
+func (h *HeavyHitters[T]) Offer(item T) uint64 {
+	column := h.columns(item)
+	estimate := ^uint64(0)
+	for row := range h.sketch {
+		c := &h.sketch[row][column(row)]
+		*c++
+		estimate = min(estimate, *c)
+	}
+
+	switch i, exists := h.candidates.index[item]; {
+	case exists:
+		h.candidates.entries[i].count = estimate
+		heap.Fix(&h.candidates, i)
+	case h.candidates.Len() < h.size:
+		heap.Push(&h.candidates, hitter[T]{item: item, count: estimate})
+	case estimate > h.candidates.entries[0].count:
+		// replace the least frequent candidate
+		delete(h.candidates.index, h.candidates.entries[0].item)
+		h.candidates.entries[0] = hitter[T]{item: item, count: estimate}
+		h.candidates.index[item] = 0
+		heap.Fix(&h.candidates, 0)
+	}
+	return estimate
+}
+
+// Estimate returns the estimated frequency of item in the stream.
+func (h *HeavyHitters[T]) Estimate(item T) uint64 {
+	column := h.columns(item)
+	estimate := ^uint64(0)
+	for row := range h.sketch {
+		estimate = min(estimate, h.sketch[row][column(row)])
+	}
+	return estimate
+}
+
+// Top returns up to n of the most frequent elements of the stream, in
+// descending order of estimated frequency. Use Estimate for the estimated
+// frequency of each element.
+//
+// Elements with the same estimated frequency are in no particular order.
+func (h *HeavyHitters[T]) Top(n int) []T {
+	entries := slices.Clone(h.candidates.entries)
+	slices.SortStableFunc(entries, func(a, b hitter[T]) int {
+		return cmp.Compare(b.count, a.count)
+	})
+	result := make([]T, min(max(n, 0), len(entries)))
+	for i := range result {
+		result[i] = entries[i].item
+	}
+	return result
+}
+
+// Candidates returns a Set of the elements currently retained as the most
+// frequent elements of the stream.
+func (h *HeavyHitters[T]) Candidates() *Set[T] {
+	result := New[T](h.candidates.Len())
+	for item := range h.candidates.index {
+		result.items[item] = sentinel
+	}
+	return result
+}
+
+type hitter[T comparable] struct {
+	item  T
+	count uint64
+}
+
+// candidates is a min-heap of hitters ordered by count, which tracks the
+// position of each item in the heap.
+type candidates[T comparable] struct {
+	entries []hitter[T]
+	index   map[T]int
+}
+
+func (c *candidates[T]) Len() int           { return len(c.entries) }
+func (c *candidates[T]) Less(i, j int) bool { return c.entries[i].count < c.entries[j].count }
+
+func (c *candidates[T]) Swap(i, j int) {
+	c.entries[i], c.entries[j] = c.entries[j], c.entries[i]
+	c.index[c.entries[i].item] = i
+	c.index[c.entries[j].item] = j
+}
+
+func (c *candidates[T]) Push(x any) {
+	entry := x.(hitter[T])
+	c.index[entry.item] = len(c.entries)
+	c.entries = append(c.entries, entry)
+}
+
+func (c *candidates[T]) Pop() any {
+	n := len(c.entries)
+	entry := c.entries[n-1]
+	c.entries = c.entries[:n-1]
+	delete(c.index, entry.item)
+	return entry
+}
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestHeavyHitters(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		h := NewHeavyHitters[string](3, 64, 4)
+		must.SliceEmpty(t, h.Top(3))
+		must.Zero(t, h.Estimate("a"))
+		must.Empty(t, h.Candidates())
+	})
+
+	t.Run("exact", func(t *testing.T) {
+		h := NewHeavyHitters[string](3, 1024, 4)
+		for _, item := range []string{"a", "b", "a", "c", "a", "b", "d"} {
+			h.Offer(item)
+		}
+		must.Eq(t, 4, h.Offer("a"))
+		must.Eq(t, 4, h.Estimate("a"))
+		must.Eq(t, 2, h.Estimate("b"))
+		must.Eq(t, []string{"a", "b"}, h.Top(2))
+		must.Len(t, 3, h.Top(10))
+		must.SliceEmpty(t, h.Top(0))
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		h := NewHeavyHitters[int](5, 256, 4)
+		rng := rand.New(rand.NewSource(1))
+
+		// elements 0 through 4 are much more frequent than the noise
+		for i := range 20_000 {
+			if i%2 == 0 {
+				h.Offer(i % 10 / 2)
+			} else {
+				h.Offer(100 + rng.Intn(10_000))
+			}
+		}
+		must.Eq(t, Of(0, 1, 2, 3, 4), h.Candidates())
+		for i := range 5 {
+			must.GreaterEq(t, 2000, h.Estimate(i))
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		defer func() {
+			must.NotNil(t, recover())
+		}()
+		NewHeavyHitters[int](0, 1, 1)
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// Layers orders the vertices of a directed acyclic graph into layers, where
+// deps maps each vertex to the set of vertices it depends on. Every vertex in
+// a layer depends only on vertices in earlier layers, such that the vertices
+// of each layer may be processed concurrently once the layers before it are
+// complete.
+//
+// Vertices which appear only as dependencies, and vertices with a nil or empty
+// set of dependencies, are placed in the first layer. The vertices of each
+// layer are in ascending order, making the result deterministic.
+//
+// Returns an error wrapping ErrCycle if deps contains a cycle.
+//
+// https://en.wikipedia.org/wiki/Topological_sorting#Kahn's_algorithm
+func Layers[T cmp.Ordered](deps map[T]*Set[T]) ([][]T, error) {
+	return LayersFunc(deps, cmp.Compare[T])
+}
+
+// LayersFunc orders the vertices of a directed acyclic graph into layers, as
+// with Layers, where the vertices of each layer are ordered by compare.
+//
+// Returns an error wrapping ErrCycle if deps contains a cycle.
+func LayersFunc[T comparable](deps map[T]*Set[T], compare CompareFunc[T]) ([][]T, error) {
+	// the number of unresolved dependencies of each vertex
+	pending := make(map[T]int, len(deps))
+	// the vertices which depend on each vertex
+	dependents := make(map[T][]T, len(deps))
+
+	for vertex, vertexDeps := range deps {
+		pending[vertex] += 0
+		if vertexDeps == nil {
+			continue
+		}
+		for dep := range vertexDeps.Items() {
+			pending[vertex]++
+			pending[dep] += 0
+			dependents[dep] = append(dependents[dep], vertex)
+		}
+	}
+
+	layer := make([]T, 0)
+	for vertex, count := range pending {
+		if count == 0 {
+			layer = append(layer, vertex)
+		}
+	}
+
+	layers := make([][]T, 0)
+	resolved := 0
+	for len(layer) > 0 {
+		slices.SortFunc(layer, compare)
+		layers = append(layers, layer)
+		resolved += len(layer)
+
+		next := make([]T, 0)
+		for _, vertex := range layer {
+			for _, dependent := range dependents[vertex] {
+				pending[dependent]--
+				if pending[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		layer = next
+	}
+
+	if resolved < len(pending) {
+		cycle := make([]T, 0, len(pending)-resolved)
+		for vertex, count := range pending {
+			if count > 0 {
+				cycle = append(cycle, vertex)
+			}
+		}
+		slices.SortFunc(cycle, compare)
+		return nil, fmt.Errorf("%w among %v", ErrCycle, cycle)
+	}
+
+	return layers, nil
+}
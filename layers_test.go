@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestLayers(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		layers, err := Layers[string](nil)
+		must.NoError(t, err)
+		must.SliceEmpty(t, layers)
+	})
+
+	t.Run("diamond", func(t *testing.T) {
+		layers, err := Layers(map[string]*Set[string]{
+			"app":      From([]string{"db", "cache"}),
+			"db":       From([]string{"network"}),
+			"cache":    From([]string{"network"}),
+			"network":  nil,
+			"metrics":  New[string](0),
+			"frontend": From([]string{"app", "cdn"}),
+		})
+		must.NoError(t, err)
+		must.Eq(t, [][]string{
+			{"cdn", "metrics", "network"},
+			{"cache", "db"},
+			{"app"},
+			{"frontend"},
+		}, layers)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := Layers(map[int]*Set[int]{
+			1: From([]int{2}),
+			2: From([]int{3}),
+			3: From([]int{1}),
+			4: From([]int{3}),
+			5: nil,
+		})
+		must.ErrorIs(t, err, ErrCycle)
+		must.EqError(t, err, "set: dependency cycle among [1 2 3 4]")
+	})
+
+	t.Run("self", func(t *testing.T) {
+		_, err := Layers(map[int]*Set[int]{
+			1: From([]int{1}),
+		})
+		must.EqError(t, err, "set: dependency cycle among [1]")
+	})
+}
+
+func TestLayersFunc(t *testing.T) {
+	layers, err := LayersFunc(map[int]*Set[int]{
+		3: From([]int{1, 2}),
+		4: From([]int{1, 2}),
+	}, func(a, b int) int { return b - a })
+	must.NoError(t, err)
+	must.Eq(t, [][]int{{2, 1}, {4, 3}}, layers)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GenerateLiteral creates the Go source of a file in package pkg, declaring a
+// function named name which returns a new Set containing the elements of col,
+// e.g. for embedding a large, static allow-list into a binary instead of
+// maintaining a hand-written literal.
+//
+//	source := set.GenerateLiteral("policy", "AllowedRegions", regions)
+//	err := os.WriteFile("allowed_regions.go", source, 0o644)
+//
+// The generated function verifies the elements against a digest recorded
+// alongside them via MustLoadLiteral, so that a generated file which has been
+// edited by hand is detected rather than silently used.
+//
+// Elements are written in ascending order using "%#v" formatting, and so T
+// must be a type whose values are written as Go literals that way (i.e. a
+// string, integer, or float type other than NaN or infinite values). A named
+// element type must be declared in package pkg, as the generated file imports
+// only the set package.
+func GenerateLiteral[T cmp.Ordered](pkg, name string, col Collection[T]) []byte {
+	items := make([]T, 0, col.Size())
+	for item := range col.Items() {
+		items = append(items, item)
+	}
+	slices.Sort(items)
+
+	var zero T
+	typ := strings.TrimPrefix(fmt.Sprintf("%T", zero), pkg+".")
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by set.GenerateLiteral; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("import \"github.com/hashicorp/go-set/v3\"\n\n")
+	fmt.Fprintf(&buf, "// %s creates a new Set containing %d generated elements.\n", name, len(items))
+	fmt.Fprintf(&buf, "func %s() *set.Set[%s] {\n", name, typ)
+	fmt.Fprintf(&buf, "\treturn set.MustLoadLiteral(%q, []%s{", literalDigest(items), typ)
+	if len(items) > 0 {
+		buf.WriteString("\n")
+		for _, item := range items {
+			fmt.Fprintf(&buf, "\t\t%#v,\n", item)
+		}
+		buf.WriteString("\t")
+	}
+	buf.WriteString("})\n}\n")
+	return buf.Bytes()
+}
+
+// LoadLiteral creates a new Set containing each item in items, after verifying
+// that items match digest, as recorded by GenerateLiteral.
+//
+// Returns an error wrapping ErrInvalidDigest if items do not match digest.
+func LoadLiteral[T comparable](digest string, items []T) (*Set[T], error) {
+	if actual := literalDigest(items); actual != digest {
+		return nil, fmt.Errorf("%w: elements have digest %s, want %s", ErrInvalidDigest, actual, digest)
+	}
+	return From(items), nil
+}
+
+// MustLoadLiteral creates a new Set containing each item in items, as with
+// LoadLiteral, but panics if items do not match digest.
+func MustLoadLiteral[T comparable](digest string, items []T) *Set[T] {
+	s, err := LoadLiteral(digest, items)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// literalDigest returns the SHA-256 hash of the "%#v" formatting of items,
+// which does not depend on the order of items.
+func literalDigest[T any](items []T) string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("%#v", item))
+	}
+	slices.Sort(lines)
+
+	h := sha256.New()
+	for _, line := range lines {
+		h.Write([]byte(line))
+		h.Write([]byte{'\n'})
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"go/format"
+	"regexp"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+type region string
+
+func TestGenerateLiteral(t *testing.T) {
+	digestOf := func(source []byte) string {
+		match := regexp.MustCompile(`MustLoadLiteral\("([^"]+)"`).FindSubmatch(source)
+		must.Len(t, 2, match)
+		return string(match[1])
+	}
+
+	t.Run("strings", func(t *testing.T) {
+		source := GenerateLiteral("policy", "Allowed", From([]string{"b", "a", `"c"`}))
+		formatted, err := format.Source(source)
+		must.NoError(t, err)
+		must.Eq(t, string(formatted), string(source))
+
+		must.StrContains(t, string(source), "package policy\n")
+		must.StrContains(t, string(source), "func Allowed() *set.Set[string] {\n")
+		must.StrContains(t, string(source), "[]string{\n\t\t\"\\\"c\\\"\",\n\t\t\"a\",\n\t\t\"b\",\n\t})")
+
+		s, err := LoadLiteral(digestOf(source), []string{"a", "b", `"c"`})
+		must.NoError(t, err)
+		must.True(t, s.EqualSlice([]string{"a", "b", `"c"`}))
+	})
+
+	t.Run("ints", func(t *testing.T) {
+		source := GenerateLiteral("policy", "Ports", From([]int{443, 80}))
+		must.StrContains(t, string(source), "func Ports() *set.Set[int] {\n")
+		must.StrContains(t, string(source), "[]int{\n\t\t80,\n\t\t443,\n\t})")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		source := GenerateLiteral("policy", "None", New[int](0))
+		formatted, err := format.Source(source)
+		must.NoError(t, err)
+		must.Eq(t, string(formatted), string(source))
+
+		s, err := LoadLiteral[int](digestOf(source), nil)
+		must.NoError(t, err)
+		must.Empty(t, s)
+	})
+
+	t.Run("named type", func(t *testing.T) {
+		source := GenerateLiteral("set", "Regions", From([]region{"us-east-1"}))
+		must.StrContains(t, string(source), "func Regions() *set.Set[region] {\n")
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		source := GenerateLiteral("policy", "Allowed", From([]string{"a", "b"}))
+		_, err := LoadLiteral(digestOf(source), []string{"a", "b", "c"})
+		must.ErrorIs(t, err, ErrInvalidDigest)
+
+		panicked := func() (result bool) {
+			defer func() { result = recover() != nil }()
+			MustLoadLiteral(digestOf(source), []string{"a"})
+			return
+		}()
+		must.True(t, panicked)
+	})
+}
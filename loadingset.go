@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"fmt"
+	"time"
+)
+
+// Loader determines whether item is a member of a set, e.g. by consulting a
+// remote service, for use by a LoadingSet.
+type Loader[T any] func(item T) (bool, error)
+
+// LoadingSet provides a read-through cache of membership in a set whose
+// members are determined by a Loader.
+//
+// Elements found to be members are cached until removed. Elements found not to
+// be members are cached for a limited time, after which the Loader is
+// consulted again. Errors returned by the Loader are not cached.
+//
+// Expired non-members are swept from the cache once the number of cached
+// non-members has doubled since the last sweep, so that expired entries never
+// outnumber unexpired ones by much, at an amortized constant cost per cached
+// non-member.
+//
+// Not thread safe, and not safe for concurrent modification.
+type LoadingSet[T comparable] struct {
+	loader  Loader[T]
+	ttl     time.Duration
+	members *Set[T]
+	absent  map[T]time.Time // expiry of each cached non-member
+	sweepAt int             // size of absent at which to sweep expired entries
+	now     func() time.Time
+}
+
+// minSweep is the smallest size of the cache of non-members of a LoadingSet
+// at which expired entries are swept.
+const minSweep = 64
+
+// NewLoadingSet creates a LoadingSet which consults loader for elements that
+// are not cached, caching non-members for ttl. A ttl of zero or less disables
+// the caching of non-members.
+//
+// Non-members expire according to time.Now; use WithClock to measure their
+// expiry by another clock.
+func NewLoadingSet[T comparable](loader Loader[T], ttl time.Duration) *LoadingSet[T] {
+	return &LoadingSet[T]{
+		loader:  loader,
+		ttl:     ttl,
+		members: New[T](0),
+		absent:  make(map[T]time.Time),
+		sweepAt: minSweep,
+		now:     time.Now,
+	}
+}
+
+// WithClock configures s to measure the expiry of cached non-members using now
+// rather than time.Now, e.g. for testing with a clock that is advanced
+// manually. Returns s.
+func (s *LoadingSet[T]) WithClock(now func() time.Time) *LoadingSet[T] {
+	s.now = now
+	return s
+}
+
+// Contains returns whether item is a member of s, consulting the Loader of s
+// if the membership of item is not cached.
+//
+// Returns an error if the Loader fails, in which case nothing is cached.
+func (s *LoadingSet[T]) Contains(item T) (bool, error) {
+	if s.members.Contains(item) {
+		return true, nil
+	}
+	if expiry, exists := s.absent[item]; exists {
+		if s.now().Before(expiry) {
+			return false, nil
+		}
+		delete(s.absent, item)
+	}
+
+	member, err := s.loader(item)
+	switch {
+	case err != nil:
+		return false, fmt.Errorf("failed to load membership of %v: %w", item, err)
+	case member:
+		s.members.Insert(item)
+	default:
+		s.cacheAbsent(item)
+	}
+	return member, nil
+}
+
+// cacheAbsent caches item as a non-member of s for the ttl of s, first
+// sweeping expired non-members if the cache has reached sweepAt.
+func (s *LoadingSet[T]) cacheAbsent(item T) {
+	if s.ttl <= 0 {
+		return
+	}
+	now := s.now()
+	if len(s.absent) >= s.sweepAt {
+		for cached, expiry := range s.absent {
+			if !now.Before(expiry) {
+				delete(s.absent, cached)
+			}
+		}
+		s.sweepAt = max(minSweep, 2*len(s.absent))
+	}
+	s.absent[item] = now.Add(s.ttl)
+}
+
+// Insert caches item as a member of s, without consulting the Loader.
+//
+// Return true if s was modified (item was not already cached as a member),
+// false otherwise.
+func (s *LoadingSet[T]) Insert(item T) bool {
+	delete(s.absent, item)
+	return s.members.Insert(item)
+}
+
+// Remove caches item as a non-member of s, without consulting the Loader.
+//
+// If s does not cache non-members (its ttl is zero or less), Remove only
+// discards the cached membership of item, as with Forget, and so the Loader
+// is consulted the next time item is checked.
+//
+// Return true if s was modified (item was cached as a member), false otherwise.
+func (s *LoadingSet[T]) Remove(item T) bool {
+	s.cacheAbsent(item)
+	return s.members.Remove(item)
+}
+
+// Forget discards the cached membership of item, so that the Loader is
+// consulted the next time item is checked.
+func (s *LoadingSet[T]) Forget(item T) {
+	delete(s.absent, item)
+	s.members.Remove(item)
+}
+
+// Members returns a copy of the elements cached as members of s.
+func (s *LoadingSet[T]) Members() *Set[T] {
+	return s.members.Copy()
+}
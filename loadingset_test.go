@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestLoadingSet(t *testing.T) {
+	// remote is the source of truth, counting the lookups of each element
+	remote := Of("a", "b")
+	lookups := make(map[string]int)
+	failure := errors.New("unavailable")
+	loader := func(item string) (bool, error) {
+		lookups[item]++
+		if item == "error" {
+			return false, failure
+		}
+		return remote.Contains(item), nil
+	}
+
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	s := NewLoadingSet[string](loader, time.Minute).WithClock(clock.now)
+
+	contains := func(item string) bool {
+		member, err := s.Contains(item)
+		must.NoError(t, err)
+		return member
+	}
+
+	t.Run("members", func(t *testing.T) {
+		must.True(t, contains("a"))
+		must.True(t, contains("a"))
+		must.Eq(t, 1, lookups["a"])
+		must.Eq(t, Of("a"), s.Members())
+	})
+
+	t.Run("non-members", func(t *testing.T) {
+		must.False(t, contains("c"))
+		must.False(t, contains("c"))
+		must.Eq(t, 1, lookups["c"])
+
+		// the negative verdict expires
+		remote.Insert("c")
+		clock.advance(time.Minute)
+		must.True(t, contains("c"))
+		must.Eq(t, 2, lookups["c"])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := s.Contains("error")
+		must.ErrorIs(t, err, failure)
+		_, err = s.Contains("error")
+		must.ErrorIs(t, err, failure)
+		must.Eq(t, 2, lookups["error"])
+	})
+
+	t.Run("modify", func(t *testing.T) {
+		must.True(t, s.Insert("x"))
+		must.True(t, contains("x"))
+		must.Zero(t, lookups["x"])
+
+		must.True(t, s.Remove("x"))
+		must.False(t, contains("x"))
+		must.Zero(t, lookups["x"])
+
+		s.Forget("b")
+		must.True(t, contains("b"))
+		s.Forget("b")
+		must.True(t, contains("b"))
+		must.Eq(t, 2, lookups["b"])
+	})
+
+	t.Run("sweep", func(t *testing.T) {
+		s := NewLoadingSet[int](func(int) (bool, error) {
+			return false, nil
+		}, time.Minute).WithClock(clock.now)
+		for i := range 10 * minSweep {
+			_, err := s.Contains(i)
+			must.NoError(t, err)
+			clock.advance(time.Second)
+		}
+		// at most a minute of non-members, plus those not yet swept
+		must.LessEq(t, 2*max(minSweep, 60), len(s.absent))
+	})
+
+	t.Run("no negative caching", func(t *testing.T) {
+		s := NewLoadingSet[string](loader, 0)
+		lookups["z"] = 0
+		for range 3 {
+			member, err := s.Contains("z")
+			must.NoError(t, err)
+			must.False(t, member)
+		}
+		must.Eq(t, 3, lookups["z"])
+
+		// removal is not cached either, and so the loader decides
+		must.True(t, s.Insert("a"))
+		must.True(t, s.Remove("a"))
+		member, err := s.Contains("a")
+		must.NoError(t, err)
+		must.True(t, member)
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package mapstructureset provides mapstructure decode hooks for decoding
+// lists into the sets of package set.
+//
+// Configuration loaded via mapstructure represents lists as []interface{},
+// which mapstructure cannot decode into a set on its own. Adding a hook for
+// each set type used by the target struct enables decoding those fields
+// directly, e.g.
+//
+//	type Config struct {
+//	  Datacenters *set.Set[string]     `mapstructure:"datacenters"`
+//	  Ports       *set.TreeSet[int]    `mapstructure:"ports"`
+//	}
+//
+//	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+//	  Result: &config,
+//	  DecodeHook: mapstructure.ComposeDecodeHookFunc(
+//	    mapstructureset.SetHook[string](),
+//	    mapstructureset.TreeSetHook[int](cmp.Compare[int]),
+//	  ),
+//	})
+//
+// Fields may be of the set type itself or a pointer to it.
+//
+// This package is a separate module, so that importing package set does not
+// require mapstructure.
+package mapstructureset
+
+import (
+	"reflect"
+
+	"github.com/hashicorp/go-set/v3"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Hook creates a decode hook which decodes a list into the type of collection
+// returned by create, which must be a pointer type (e.g. *set.Set[T]).
+//
+// Each element of the list is decoded into a T by mapstructure using its
+// default configuration, and inserted into a collection returned by create.
+// Duplicate elements are inserted only once. A different Collection[T] may
+// also be decoded, by inserting each of its elements.
+func Hook[T any](create func() set.Collection[T]) mapstructure.DecodeHookFuncType {
+	target := reflect.TypeOf(create())
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		if to != target && to != target.Elem() {
+			return data, nil
+		}
+
+		// mapstructure decodes into a pointer field by first decoding into the
+		// pointer type and then into its element type
+		col, ok := data.(set.Collection[T])
+		switch {
+		case ok && from == target:
+		case ok:
+			col = create()
+			col.InsertSet(data.(set.Collection[T]))
+		default:
+			var items []T
+			if err := mapstructure.Decode(data, &items); err != nil {
+				return nil, err
+			}
+			col = create()
+			col.InsertSlice(items)
+		}
+
+		if to == target {
+			return col, nil
+		}
+		return reflect.ValueOf(col).Elem().Interface(), nil
+	}
+}
+
+// SetHook creates a decode hook which decodes a list into a set.Set[T].
+func SetHook[T comparable]() mapstructure.DecodeHookFuncType {
+	return Hook[T](func() set.Collection[T] {
+		return set.New[T](0)
+	})
+}
+
+// HashSetHook creates a decode hook which decodes a list into a
+// set.HashSet[T, H], hashing elements with fn.
+func HashSetHook[T any, H set.Hash](fn set.HashFunc[T, H]) mapstructure.DecodeHookFuncType {
+	return Hook[T](func() set.Collection[T] {
+		return set.NewHashSetFunc[T, H](0, fn)
+	})
+}
+
+// TreeSetHook creates a decode hook which decodes a list into a
+// set.TreeSet[T], ordering elements with compare.
+func TreeSetHook[T any](compare set.CompareFunc[T]) mapstructure.DecodeHookFuncType {
+	return Hook[T](func() set.Collection[T] {
+		return set.NewTreeSet[T](compare)
+	})
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mapstructureset
+
+import (
+	"cmp"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-set/v3"
+	"github.com/mitchellh/mapstructure"
+	"github.com/shoenig/test/must"
+)
+
+type server struct {
+	Name string
+	Port int
+}
+
+type config struct {
+	Datacenters *set.Set[string]             `mapstructure:"datacenters"`
+	Regions     set.Set[string]              `mapstructure:"regions"`
+	Ports       *set.TreeSet[int]            `mapstructure:"ports"`
+	Servers     *set.HashSet[server, string] `mapstructure:"servers"`
+	Unchanged   []string                     `mapstructure:"unchanged"`
+}
+
+func decode(t *testing.T, input map[string]interface{}) (config, error) {
+	var c config
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result: &c,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			SetHook[string](),
+			TreeSetHook[int](cmp.Compare[int]),
+			HashSetHook[server, string](func(s server) string { return s.Name }),
+		),
+	})
+	must.NoError(t, err)
+	return c, decoder.Decode(input)
+}
+
+func TestHook(t *testing.T) {
+	c, err := decode(t, map[string]interface{}{
+		"datacenters": []interface{}{"dc1", "dc2", "dc1"},
+		"regions":     []interface{}{"global"},
+		"ports":       []interface{}{8080, 443, 80, 443},
+		"servers": []interface{}{
+			map[string]interface{}{"name": "a", "port": 1},
+			map[string]interface{}{"name": "b", "port": 2},
+		},
+		"unchanged": []interface{}{"x", "x"},
+	})
+	must.NoError(t, err)
+
+	must.Eq(t, set.From([]string{"dc1", "dc2"}), c.Datacenters)
+	must.Eq(t, *set.From([]string{"global"}), c.Regions)
+	must.Eq(t, []int{80, 443, 8080}, c.Ports.Slice())
+	must.Size(t, 2, c.Servers)
+	must.True(t, c.Servers.Contains(server{Name: "b", Port: 2}))
+	must.Eq(t, []string{"x", "x"}, c.Unchanged)
+}
+
+func TestHook_error(t *testing.T) {
+	_, err := decode(t, map[string]interface{}{
+		"ports": []interface{}{80, "https"},
+	})
+	must.ErrorContains(t, err, "ports")
+}
+
+func TestHook_set(t *testing.T) {
+	// decoding from a value that is already the target type passes through
+	hook := SetHook[string]()
+	s := set.From([]string{"a"})
+	result, err := hook(reflectType(s), reflectType(s), s)
+	must.NoError(t, err)
+	must.Eq(t, s, result.(*set.Set[string]))
+}
+
+func reflectType(v interface{}) reflect.Type {
+	return reflect.TypeOf(v)
+}
+
+func TestHook_collection(t *testing.T) {
+	c, err := decode(t, map[string]interface{}{
+		"ports": set.From([]int{3, 1, 2}),
+	})
+	must.NoError(t, err)
+	must.Eq(t, []int{1, 2, 3}, c.Ports.Slice())
+}
@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Matcher provides fast matching of text against a fixed set of strings, e.g.
+// a list of allowed or denied keywords, as created by CompileMatcher.
+//
+// A Matcher is immutable, and so is safe for concurrent use by multiple
+// goroutines.
+type Matcher struct {
+	nodes []matchNode
+	fold  map[string]nothing
+}
+
+// matchNode is a state of the Aho-Corasick automaton of a Matcher.
+type matchNode struct {
+	next     map[byte]int32
+	fail     int32
+	terminal bool // a string of the set ends at this state, or at a suffix of it
+}
+
+// CompileMatcher creates a Matcher for the strings of col.
+//
+// Matching substrings uses the Aho-Corasick algorithm, which finds every
+// string of col in a single pass over the text, in time proportional to the
+// length of the text regardless of the number of strings in col. The Matcher
+// is a snapshot; later modifications to col are not reflected in it.
+//
+// https://en.wikipedia.org/wiki/Aho–Corasick_algorithm
+func CompileMatcher(col Collection[string]) *Matcher {
+	m := &Matcher{
+		nodes: []matchNode{{}},
+		fold:  make(map[string]nothing, col.Size()),
+	}
+	for item := range col.Items() {
+		m.add(item)
+		m.fold[foldKey(item)] = sentinel
+	}
+	m.link()
+	return m
+}
+
+// add inserts item into the trie of m.
+func (m *Matcher) add(item string) {
+	state := int32(0)
+	for i := 0; i < len(item); i++ {
+		next, exists := m.nodes[state].next[item[i]]
+		if !exists {
+			next = int32(len(m.nodes))
+			m.nodes = append(m.nodes, matchNode{})
+			if m.nodes[state].next == nil {
+				m.nodes[state].next = make(map[byte]int32)
+			}
+			m.nodes[state].next[item[i]] = next
+		}
+		state = next
+	}
+	m.nodes[state].terminal = true
+}
+
+// link computes the failure link of each state of m, in breadth first order,
+// where the failure link of a state is the state of its longest proper suffix
+// in the trie.
+func (m *Matcher) link() {
+	queue := make([]int32, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for b, child := range m.nodes[state].next {
+			m.nodes[child].fail = m.step(m.nodes[state].fail, b)
+			m.nodes[child].terminal = m.nodes[child].terminal || m.nodes[m.nodes[child].fail].terminal
+			queue = append(queue, child)
+		}
+	}
+}
+
+// step returns the state following state upon reading b.
+func (m *Matcher) step(state int32, b byte) int32 {
+	for {
+		if next, exists := m.nodes[state].next[b]; exists {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = m.nodes[state].fail
+	}
+}
+
+// ContainsAnySubstring returns whether any string of m is a substring of text.
+//
+// If m contains the empty string, the result is always true.
+func (m *Matcher) ContainsAnySubstring(text string) bool {
+	state := int32(0)
+	if m.nodes[state].terminal {
+		return true
+	}
+	for i := 0; i < len(text); i++ {
+		state = m.step(state, text[i])
+		if m.nodes[state].terminal {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchFold returns whether s is equal to any string of m under simple Unicode
+// case folding, as with strings.EqualFold.
+func (m *Matcher) MatchFold(s string) bool {
+	_, exists := m.fold[foldKey(s)]
+	return exists
+}
+
+// foldKey maps s to a canonical form, such that foldKey(a) == foldKey(b) if
+// and only if strings.EqualFold(a, b).
+func foldKey(s string) string {
+	buf := make([]byte, 0, len(s))
+	for _, r := range s {
+		// the smallest rune of the orbit of r under simple folding
+		least := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			least = min(least, f)
+		}
+		buf = utf8.AppendRune(buf, least)
+	}
+	return string(buf)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestMatcher_ContainsAnySubstring(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		m := CompileMatcher(New[string](0))
+		must.False(t, m.ContainsAnySubstring(""))
+		must.False(t, m.ContainsAnySubstring("text"))
+	})
+
+	t.Run("empty string", func(t *testing.T) {
+		m := CompileMatcher(Of(""))
+		must.True(t, m.ContainsAnySubstring(""))
+		must.True(t, m.ContainsAnySubstring("text"))
+	})
+
+	t.Run("keywords", func(t *testing.T) {
+		m := CompileMatcher(Of("he", "she", "his", "hers"))
+		must.True(t, m.ContainsAnySubstring("ushers"))
+		must.True(t, m.ContainsAnySubstring("this"))
+		must.True(t, m.ContainsAnySubstring("ahishers"))
+		must.False(t, m.ContainsAnySubstring("hi s"))
+		must.False(t, m.ContainsAnySubstring("HE"))
+	})
+
+	t.Run("suffix", func(t *testing.T) {
+		// "bc" is only found via the failure link of "abc"
+		m := CompileMatcher(Of("abcd", "bc"))
+		must.True(t, m.ContainsAnySubstring("xabcx"))
+	})
+
+	t.Run("random", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		word := func(n int) string {
+			var sb strings.Builder
+			for range 1 + r.Intn(n) {
+				sb.WriteByte("abc"[r.Intn(3)])
+			}
+			return sb.String()
+		}
+
+		for range 100 {
+			keywords := New[string](0)
+			for range 1 + r.Intn(5) {
+				keywords.Insert(word(4))
+			}
+			m := CompileMatcher(keywords)
+			for range 20 {
+				text := word(12)
+				expect := false
+				for keyword := range keywords.Items() {
+					expect = expect || strings.Contains(text, keyword)
+				}
+				must.Eq(t, expect, m.ContainsAnySubstring(text), must.Sprintf("%v in %q", keywords, text))
+			}
+		}
+	})
+}
+
+func TestMatcher_MatchFold(t *testing.T) {
+	m := CompileMatcher(Of("Allow", "straße", "K"))
+	must.True(t, m.MatchFold("allow"))
+	must.True(t, m.MatchFold("ALLOW"))
+	must.True(t, m.MatchFold("STRAßE"))
+	must.True(t, m.MatchFold("K")) // Kelvin sign
+	must.False(t, m.MatchFold("allowed"))
+	must.False(t, m.MatchFold("strasse"))
+}
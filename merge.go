@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// MergeSortedSlices creates a TreeSet containing each element of each of
+// shards, ordered by compare.
+//
+// Each shard is expected to be in ascending order according to compare, e.g.
+// one part of a larger data set. The shards are merged via a k-way merge in
+// O(n log k) time, and the TreeSet is then built directly from the merged
+// elements in O(n) time, without the rebalancing incurred by inserting each
+// element individually. Shards which are not in order are sorted first (into a
+// copy), so that the result is correct either way.
+func MergeSortedSlices[T any](compare CompareFunc[T], shards ...[]T) *TreeSet[T] {
+	s := NewTreeSet[T](compare)
+	s.rebuild(mergeSorted(compare, shards))
+	return s
+}
+
+// MergeTreeSets creates a TreeSet containing each element of each of sets,
+// ordered by compare, as with MergeSortedSlices.
+//
+// The elements of each set are already in order if the set is also ordered by
+// compare, though sets ordered otherwise are merged correctly.
+func MergeTreeSets[T any](compare CompareFunc[T], sets ...*TreeSet[T]) *TreeSet[T] {
+	shards := make([][]T, len(sets))
+	for i, set := range sets {
+		shards[i] = set.Slice()
+	}
+	return MergeSortedSlices(compare, shards...)
+}
+
+// mergeSorted merges shards into a single slice in ascending order according
+// to compare, omitting duplicate elements.
+func mergeSorted[T any](compare CompareFunc[T], shards [][]T) []T {
+	h := &mergeHeap[T]{compare: compare}
+	total := 0
+	for _, shard := range shards {
+		if len(shard) == 0 {
+			continue
+		}
+		if !slices.IsSortedFunc(shard, compare) {
+			shard = slices.SortedFunc(slices.Values(shard), compare)
+		}
+		h.shards = append(h.shards, shard)
+		total += len(shard)
+	}
+	heap.Init(h)
+
+	result := make([]T, 0, total)
+	for h.Len() > 0 {
+		shard := h.shards[0]
+		item := shard[0]
+		if len(result) == 0 || compare(result[len(result)-1], item) != 0 {
+			result = append(result, item)
+		}
+		if len(shard) == 1 {
+			heap.Pop(h)
+		} else {
+			h.shards[0] = shard[1:]
+			heap.Fix(h, 0)
+		}
+	}
+	return result
+}
+
+// mergeHeap is a min-heap of non-empty shards, ordered by their first element.
+type mergeHeap[T any] struct {
+	compare CompareFunc[T]
+	shards  [][]T
+}
+
+func (h *mergeHeap[T]) Len() int           { return len(h.shards) }
+func (h *mergeHeap[T]) Less(i, j int) bool { return h.compare(h.shards[i][0], h.shards[j][0]) < 0 }
+func (h *mergeHeap[T]) Swap(i, j int)      { h.shards[i], h.shards[j] = h.shards[j], h.shards[i] }
+
+func (h *mergeHeap[T]) Push(x any) {
+	h.shards = append(h.shards, x.([]T))
+}
+
+func (h *mergeHeap[T]) Pop() any {
+	n := len(h.shards)
+	shard := h.shards[n-1]
+	h.shards = h.shards[:n-1]
+	return shard
+}
@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestMergeSortedSlices(t *testing.T) {
+	t.Run("none", func(t *testing.T) {
+		s := MergeSortedSlices[int](cmp.Compare[int])
+		must.Empty(t, s)
+		s.Insert(1)
+		invariants(t, s, cmp.Compare[int])
+	})
+
+	t.Run("overlapping", func(t *testing.T) {
+		s := MergeSortedSlices(cmp.Compare[int],
+			[]int{1, 4, 7, 10},
+			[]int{2, 4, 6},
+			nil,
+			[]int{3, 6, 9, 10, 11},
+		)
+		must.Eq(t, []int{1, 2, 3, 4, 6, 7, 9, 10, 11}, s.Slice())
+		invariants(t, s, cmp.Compare[int])
+		blackHeight(t, s.root)
+	})
+
+	t.Run("unsorted", func(t *testing.T) {
+		shard := []int{5, 1, 3}
+		s := MergeSortedSlices(cmp.Compare[int], shard, []int{2, 4})
+		must.Eq(t, ints(5), s.Slice())
+		must.Eq(t, []int{5, 1, 3}, shard)
+	})
+
+	t.Run("large", func(t *testing.T) {
+		shards := make([][]int, 7)
+		for i := range 1000 {
+			shards[i%7] = append(shards[i%7], i)
+		}
+		s := MergeSortedSlices(cmp.Compare[int], shards...)
+		must.Size(t, 1000, s)
+		invariants(t, s, cmp.Compare[int])
+		blackHeight(t, s.root)
+
+		// the merged tree remains usable
+		for i := range 500 {
+			s.Remove(i * 2)
+		}
+		invariants(t, s, cmp.Compare[int])
+		blackHeight(t, s.root)
+	})
+}
+
+func TestMergeTreeSets(t *testing.T) {
+	a := TreeSetOf(cmp.Compare[int], 1, 3, 5)
+	b := TreeSetOf(cmp.Compare[int], 2, 3, 4)
+	desc := TreeSetOf(func(a, b int) int { return cmp.Compare(b, a) }, 6, 0)
+
+	s := MergeTreeSets(cmp.Compare[int], a, b, desc)
+	must.Eq(t, []int{0, 1, 2, 3, 4, 5, 6}, s.Slice())
+	invariants(t, s, cmp.Compare[int])
+}
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"encoding/binary"
+	"hash/fnv"
+	"slices"
+)
+
+// seededOrder sorts items into a pseudo-random order determined by seed and
+// the key of each item, and returns items.
+//
+// Each item is ordered by the FNV-1a hash of seed and its key, so that the
+// order depends only on the keys of items and not on their initial order. The
+// hash function is fixed, making the order stable across processes and Go
+// releases.
+func seededOrder[T any](items []T, key func(T) string, seed int64) []T {
+	type scored struct {
+		item  T
+		key   string
+		score uint64
+	}
+
+	var prefix [8]byte
+	binary.LittleEndian.PutUint64(prefix[:], uint64(seed))
+
+	scores := make([]scored, len(items))
+	for i, item := range items {
+		h := fnv.New64a()
+		_, _ = h.Write(prefix[:])
+		k := key(item)
+		_, _ = h.Write([]byte(k))
+		scores[i] = scored{item: item, key: k, score: mix(h.Sum64())}
+	}
+
+	slices.SortFunc(scores, func(a, b scored) int {
+		if c := cmp.Compare(a.score, b.score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.key, b.key)
+	})
+
+	for i := range scores {
+		items[i] = scores[i].item
+	}
+	return items
+}
+
+// mix is the finalizer of the SplitMix64 generator, which spreads the small
+// differences in the FNV-1a hashes of similar keys across every bit.
+func mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestSet_IterOrderSeed(t *testing.T) {
+	s := From(ints(size))
+
+	first := slices.Collect(s.IterOrderSeed(1))
+	must.SliceContainsAll(t, ints(size), first)
+
+	// the same order for the same seed and contents, regardless of history
+	other := From(shuffle(ints(size)))
+	must.Eq(t, first, slices.Collect(other.IterOrderSeed(1)))
+
+	// a different order for a different seed, and not simply ascending
+	must.NotEq(t, first, slices.Collect(s.IterOrderSeed(2)))
+	must.NotEq(t, ints(size), first)
+
+	// stable across releases
+	must.Eq(t, []int{5, 3, 2, 1, 4}, slices.Collect(From(ints(5)).IterOrderSeed(42)))
+
+	// modifying s during iteration does not affect the elements produced
+	count := 0
+	for item := range s.IterOrderSeed(1) {
+		s.Remove(item + 1)
+		count++
+	}
+	must.Eq(t, size, count)
+}
+
+func TestHashSet_IterOrderSeed(t *testing.T) {
+	s := HashSetFrom[*company, string]([]*company{c1, c2, c3, c4, c5})
+
+	first := slices.Collect(s.IterOrderSeed(7))
+	must.SliceLen(t, 5, first)
+	for i := 0; i < 10; i++ {
+		must.Eq(t, first, slices.Collect(s.Copy().IterOrderSeed(7)))
+	}
+
+	for item := range s.IterOrderSeed(7) {
+		must.Eq(t, first[0], item)
+		break
+	}
+}
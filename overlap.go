@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// IntersectionSizeAtLeast returns whether a and b have at least k elements in
+// common, e.g. whether two sets of nodes overlap by at least a quorum.
+//
+// Unlike computing the size of the intersection, no set is created, and the
+// elements of the smaller set are probed in the larger set only until the
+// answer is known, i.e. until k common elements are found, or too few
+// elements remain for k to be reached.
+func IntersectionSizeAtLeast[T any](a, b Collection[T], k int) bool {
+	if k <= 0 {
+		return true
+	}
+	big, small := a, b
+	if a.Size() < b.Size() {
+		big, small = b, a
+	}
+
+	remaining := small.Size()
+	found := 0
+	for item := range small.Items() {
+		if found+remaining < k {
+			return false
+		}
+		remaining--
+		if big.Contains(item) {
+			found++
+			if found == k {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EstimateIntersectionSize returns an estimate of the number of elements a and
+// b have in common, by probing a uniform random sample of up to n elements of
+// the smaller set in the larger set, e.g. where probing every element is too
+// expensive and an approximate answer suffices.
+//
+// The estimate is exact if the smaller set has no more than n elements. The
+// sample is selected as with SampleWeighted, and so each element of the
+// smaller set is visited once, but only the sampled elements are probed. If
+// rng is nil, the top-level functions of math/rand/v2 are used.
+func EstimateIntersectionSize[T any](a, b Collection[T], n int, rng *rand.Rand) int {
+	big, small := a, b
+	if a.Size() < b.Size() {
+		big, small = b, a
+	}
+	if small.Size() <= n {
+		count := 0
+		for item := range small.Items() {
+			if big.Contains(item) {
+				count++
+			}
+		}
+		return count
+	}
+
+	samples := SampleWeighted(small, n, func(T) float64 { return 1 }, rng)
+	if len(samples) == 0 {
+		return 0
+	}
+	hits := 0
+	for _, item := range samples {
+		if big.Contains(item) {
+			hits++
+		}
+	}
+	return int(math.Round(float64(hits) / float64(len(samples)) * float64(small.Size())))
+}
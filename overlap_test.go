@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestIntersectionSizeAtLeast(t *testing.T) {
+	a := From(ints(10))                                           // 1..10
+	b := TreeSetFrom(ints(20)[7:], cmp.Compare[int])              // 8..20
+	c := HashSetFromFunc([]int{30}, func(i int) int { return i }) // disjoint
+
+	must.True(t, IntersectionSizeAtLeast[int](a, b, 0))
+	must.True(t, IntersectionSizeAtLeast[int](a, b, 1))
+	must.True(t, IntersectionSizeAtLeast[int](a, b, 3))
+	must.True(t, IntersectionSizeAtLeast[int](b, a, 3))
+	must.False(t, IntersectionSizeAtLeast[int](a, b, 4))
+	must.False(t, IntersectionSizeAtLeast[int](a, c, 1))
+	must.False(t, IntersectionSizeAtLeast[int](a, New[int](0), 1))
+	must.True(t, IntersectionSizeAtLeast[int](a, New[int](0), 0))
+
+	t.Run("early", func(t *testing.T) {
+		probes := 0
+		big := NewTreeSet(func(x, y int) int {
+			probes++
+			return cmp.Compare(x, y)
+		})
+		big.InsertSlice(ints(1000))
+		probes = 0
+
+		must.True(t, IntersectionSizeAtLeast[int](From(ints(100)), big, 2))
+		must.Less(t, 100, probes) // stopped after 2 elements, at ~10 comparisons each
+	})
+}
+
+func TestEstimateIntersectionSize(t *testing.T) {
+	a := From(ints(10_000))
+	b := From(ints(20_000)[5_000:]) // 5001..20000, overlapping a by 5000
+
+	t.Run("exact", func(t *testing.T) {
+		must.Eq(t, 5000, EstimateIntersectionSize[int](a, b, 10_000, nil))
+		must.Eq(t, 0, EstimateIntersectionSize[int](a, New[int](0), 10, nil))
+	})
+
+	t.Run("estimate", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(1, 2))
+		estimate := EstimateIntersectionSize[int](a, b, 1000, rng)
+		must.Between(t, 4500, estimate, 5500)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		must.Zero(t, EstimateIntersectionSize[int](a, b, 0, nil))
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Pair is a comparable 2-tuple, for use as the element of a set of pairs, e.g.
+// the edges of a graph or (namespace, name) identifiers.
+//
+// A Pair may be used as the element of a Set directly, and of a TreeSet via
+// ComparePairs or ComparePairsFunc. Being comparable, a Pair has no need of a
+// HashSet; use a Set instead.
+type Pair[A, B comparable] struct {
+	First  A
+	Second B
+}
+
+// PairOf creates a Pair of first and second.
+func PairOf[A, B comparable](first A, second B) Pair[A, B] {
+	return Pair[A, B]{First: first, Second: second}
+}
+
+// String returns a string representation of p, e.g. "(a, 1)".
+func (p Pair[A, B]) String() string {
+	return fmt.Sprintf("(%v, %v)", p.First, p.Second)
+}
+
+// ComparePairs compares pairs of cmp.Ordered types lexicographically, by their
+// first elements and then by their second elements. ComparePairs implements
+// CompareFunc for use with TreeSet.
+func ComparePairs[A, B cmp.Ordered](x, y Pair[A, B]) int {
+	if c := cmp.Compare(x.First, y.First); c != 0 {
+		return c
+	}
+	return cmp.Compare(x.Second, y.Second)
+}
+
+// ComparePairsFunc creates a CompareFunc which compares pairs lexicographically,
+// by their first elements via compareFirst and then by their second elements
+// via compareSecond.
+func ComparePairsFunc[A, B comparable](compareFirst CompareFunc[A], compareSecond CompareFunc[B]) CompareFunc[Pair[A, B]] {
+	return func(x, y Pair[A, B]) int {
+		if c := compareFirst(x.First, y.First); c != 0 {
+			return c
+		}
+		return compareSecond(x.Second, y.Second)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestPair(t *testing.T) {
+	edges := []Pair[string, string]{
+		PairOf("b", "c"),
+		PairOf("a", "c"),
+		PairOf("a", "b"),
+		PairOf("a", "b"),
+	}
+
+	t.Run("set", func(t *testing.T) {
+		s := From(edges)
+		must.Eq(t, 3, s.Size())
+		must.Contains[Pair[string, string]](t, PairOf("a", "c"), s)
+	})
+
+	t.Run("treeset", func(t *testing.T) {
+		s := TreeSetFrom(edges, ComparePairs[string, string])
+		must.Eq(t, "[(a, b) (a, c) (b, c)]", s.String())
+	})
+
+	t.Run("func", func(t *testing.T) {
+		compare := ComparePairsFunc(strings.Compare, func(a, b int) int {
+			return cmp.Compare(b, a)
+		})
+		s := TreeSetOf(compare, PairOf("x", 1), PairOf("y", 1), PairOf("x", 2))
+		must.Eq(t, []Pair[string, int]{PairOf("x", 2), PairOf("x", 1), PairOf("y", 1)}, s.Slice())
+	})
+}
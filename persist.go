@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// SaveFile writes the JSON encoding of col to the file at path, replacing the
+// file atomically, so that a concurrent or subsequent LoadFile observes either
+// the previous contents of the file or the elements of col, never a partially
+// written file.
+//
+// The elements are first written to a temporary file in the same directory as
+// path, which is then renamed to path. The file is created with permissions
+// 0600. SaveFile does not wait for the file to reach stable storage, and so
+// the file may revert to its previous contents after a crash of the operating
+// system; use SaveFileSync to prevent that.
+func SaveFile[T any](path string, col Collection[T]) error {
+	return saveFile(path, col, false)
+}
+
+// SaveFileSync writes the JSON encoding of col to the file at path, as with
+// SaveFile, and additionally waits for the file and its directory entry to
+// reach stable storage via fsync before returning.
+func SaveFileSync[T any](path string, col Collection[T]) error {
+	return saveFile(path, col, true)
+}
+
+func saveFile[T any](path string, col Collection[T], sync bool) (err error) {
+	data, err := json.Marshal(col)
+	if err != nil {
+		return err
+	}
+
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if sync {
+		if err = tmp.Sync(); err != nil {
+			return err
+		}
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	if sync {
+		return syncDir(dir)
+	}
+	return nil
+}
+
+// syncDir waits for the entries of the directory at path to reach stable
+// storage, ignoring platforms which do not support syncing a directory.
+func syncDir(path string) error {
+	d, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if errors.Is(err, os.ErrInvalid) || errors.Is(err, errors.ErrUnsupported) {
+		err = nil
+	}
+	return errors.Join(err, d.Close())
+}
+
+// LoadFile reads a set written by SaveFile from the file at path, inserting
+// each element into a set created by create, e.g.
+//
+//	s, err := set.LoadFile("members.json", func() *set.TreeSet[string] {
+//	  return set.NewTreeSet(cmp.Compare[string])
+//	})
+//
+// If the file does not exist, the returned error wraps fs.ErrNotExist.
+func LoadFile[T any, C Collection[T]](path string, create func() C) (C, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		var zero C
+		return zero, err
+	}
+	return load(data, create)
+}
+
+// LoadFS reads a set written by SaveFile from the file name of fsys, inserting
+// each element into a set created by create. See LoadFile.
+func LoadFS[T any, C Collection[T]](fsys fs.FS, name string, create func() C) (C, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		var zero C
+		return zero, err
+	}
+	return load(data, create)
+}
+
+func load[T any, C Collection[T]](data []byte, create func() C) (C, error) {
+	col := create()
+	if err := json.Unmarshal(data, col); err != nil {
+		var zero C
+		return zero, err
+	}
+	return col, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestSaveFile(t *testing.T) {
+	create := func() *TreeSet[int] {
+		return NewTreeSet(cmp.Compare[int])
+	}
+
+	t.Run("roundtrip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "members.json")
+		must.NoError(t, SaveFile[int](path, TreeSetOf(cmp.Compare[int], 3, 1, 2)))
+
+		s, err := LoadFile(path, create)
+		must.NoError(t, err)
+		must.Eq(t, []int{1, 2, 3}, s.Slice())
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "members.json")
+		must.NoError(t, SaveFileSync[int](path, Of(1, 2)))
+		must.NoError(t, SaveFileSync[int](path, Of(3)))
+
+		s, err := LoadFile(path, func() *Set[int] { return New[int](0) })
+		must.NoError(t, err)
+		must.Eq(t, Of(3), s)
+
+		// no temporary files are left behind
+		entries, err := os.ReadDir(dir)
+		must.NoError(t, err)
+		must.SliceLen(t, 1, entries)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "members.json")
+		must.ErrorIs(t, SaveFile[int](path, Of(1)), fs.ErrNotExist)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadFile(filepath.Join(t.TempDir(), "members.json"), create)
+		must.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		fsys := fstest.MapFS{"members.json": {Data: []byte(`["a"]`)}}
+		_, err := LoadFS(fsys, "members.json", create)
+		must.Error(t, err)
+	})
+
+	t.Run("fs", func(t *testing.T) {
+		fsys := fstest.MapFS{"members.json": {Data: []byte(`[2,1]`)}}
+		s, err := LoadFS(fsys, "members.json", create)
+		must.NoError(t, err)
+		must.Eq(t, []int{1, 2}, s.Slice())
+	})
+}
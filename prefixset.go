@@ -0,0 +1,575 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// PrefixSet provides a set implementation for strings which, in addition to
+// the usual set operations, is able to answer questions about the prefixes of
+// its elements. This makes it useful for sets of paths or namespaces, such as
+// ACL paths or KV key prefixes.
+//
+// The underlying data structure is a Radix Tree (compressed trie), where each
+// element is stored as the path of edge labels from the root to a node. The
+// elements of a PrefixSet are iterated in lexicographic (byte-wise) order.
+//
+// https://en.wikipedia.org/wiki/Radix_tree
+//
+// Not thread safe, and not safe for concurrent modification. Multiple goroutines
+// may read from a PrefixSet concurrently as long as no goroutine is modifying
+// it. Modifying a PrefixSet while iterating its elements is not supported, and
+// is detected when built with -tags setdebug.
+type PrefixSet struct {
+	guard guard
+	root  *radixNode
+	size  int
+}
+
+// NewPrefixSet creates a new empty PrefixSet.
+func NewPrefixSet() *PrefixSet {
+	return &PrefixSet{
+		root: new(radixNode),
+	}
+}
+
+// PrefixSetFrom creates a new PrefixSet containing each item in items.
+func PrefixSetFrom(items []string) *PrefixSet {
+	s := NewPrefixSet()
+	s.InsertSlice(items)
+	return s
+}
+
+// Insert item into s.
+//
+// Returns true if s was modified (item was not already in s), false otherwise.
+func (s *PrefixSet) Insert(item string) bool {
+	s.guard.check("insert")
+
+	n, key := s.root, item
+	for {
+		if key == "" {
+			if n.leaf {
+				return false
+			}
+			n.leaf = true
+			s.size++
+			return true
+		}
+
+		i, found := n.index(key[0])
+		if !found {
+			n.children = append(n.children, nil)
+			copy(n.children[i+1:], n.children[i:])
+			n.children[i] = &radixNode{label: key, leaf: true}
+			s.size++
+			return true
+		}
+
+		child := n.children[i]
+		l := commonPrefix(key, child.label)
+		if l < len(child.label) {
+			// split the edge to child where key diverges from its label
+			split := &radixNode{
+				label:    child.label[:l],
+				children: []*radixNode{child},
+			}
+			child.label = child.label[l:]
+			n.children[i] = split
+			child = split
+		}
+		n, key = child, key[l:]
+	}
+}
+
+// InsertSlice will insert each item in items into s.
+//
+// Return true if s was modified (at least one item was not already in s), false otherwise.
+func (s *PrefixSet) InsertSlice(items []string) bool {
+	modified := false
+	for _, item := range items {
+		if s.Insert(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// InsertSet will insert each element of col into s.
+//
+// Return true if s was modified (at least one item of col was not already in s), false otherwise.
+func (s *PrefixSet) InsertSet(col Collection[string]) bool {
+	modified := false
+	for item := range col.Items() {
+		if s.Insert(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// Remove item from s.
+//
+// Returns true if s was modified (item was in s), false otherwise.
+func (s *PrefixSet) Remove(item string) bool {
+	s.guard.check("remove")
+
+	if !s.root.remove(item) {
+		return false
+	}
+	s.size--
+	return true
+}
+
+// RemoveSlice will remove each item in items from s.
+//
+// Return true if s was modified (any item was in s), false otherwise.
+func (s *PrefixSet) RemoveSlice(items []string) bool {
+	modified := false
+	for _, item := range items {
+		if s.Remove(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// RemoveSet will remove each element in col from s.
+//
+// Returns true if s was modified (at least one item in col was in s), false otherwise.
+func (s *PrefixSet) RemoveSet(col Collection[string]) bool {
+	return removeSet(s, col)
+}
+
+// RemoveFunc will remove each element from s that satisfies condition f.
+//
+// Return true if s was modified, false otherwise.
+func (s *PrefixSet) RemoveFunc(f func(string) bool) bool {
+	return removeFunc(s, f)
+}
+
+// DeletePrefix will remove each element from s that begins with prefix,
+// including prefix itself.
+//
+// Returns the number of elements removed from s.
+func (s *PrefixSet) DeletePrefix(prefix string) int {
+	s.guard.check("delete prefix")
+
+	removed := s.root.removePrefix(prefix)
+	s.size -= removed
+	return removed
+}
+
+// Contains returns whether item is present in s.
+func (s *PrefixSet) Contains(item string) bool {
+	n, rest := s.root.locate(item)
+	return n != nil && rest == "" && n.leaf
+}
+
+// ContainsSlice returns whether all elements in items are present in s.
+func (s *PrefixSet) ContainsSlice(items []string) bool {
+	return containsSlice(s, items)
+}
+
+// HasPrefix returns whether any element of s begins with prefix.
+func (s *PrefixSet) HasPrefix(prefix string) bool {
+	n, _ := s.root.locate(prefix)
+	// every node other than the root is a leaf or has a leaf descendant
+	return n != nil && (n != s.root || s.size > 0)
+}
+
+// PrefixItems returns a generator function for iterating each element in s
+// that begins with prefix by using the range keyword. Elements are produced in
+// lexicographic order.
+//
+//	for element := range s.PrefixItems("acl/") { ... }
+func (s *PrefixSet) PrefixItems(prefix string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		s.guard.enter()
+		defer s.guard.exit()
+
+		n, rest := s.root.locate(prefix)
+		if n == nil {
+			return
+		}
+		// rest is the remainder of the label of n beyond the end of prefix
+		n.walk(append([]byte(prefix), rest...), yield)
+	}
+}
+
+// LongestPrefixOf returns the longest element of s which is a prefix of item,
+// which may be item itself.
+//
+// An empty string and false are returned if no element of s is a prefix of item.
+func (s *PrefixSet) LongestPrefixOf(item string) (string, bool) {
+	longest := -1
+	if s.root.leaf {
+		longest = 0
+	}
+
+	n, key := s.root, item
+	for key != "" {
+		i, found := n.index(key[0])
+		if !found || !strings.HasPrefix(key, n.children[i].label) {
+			break
+		}
+		n = n.children[i]
+		key = key[len(n.label):]
+		if n.leaf {
+			longest = len(item) - len(key)
+		}
+	}
+
+	if longest < 0 {
+		return "", false
+	}
+	return item[:longest], true
+}
+
+// Subset returns whether col is a subset of s.
+func (s *PrefixSet) Subset(col Collection[string]) bool {
+	return subset(s, col)
+}
+
+// ProperSubset returns whether col is a proper subset of s.
+func (s *PrefixSet) ProperSubset(col Collection[string]) bool {
+	if s.Size() <= col.Size() {
+		return false
+	}
+	return s.Subset(col)
+}
+
+// Clear removes every element from s.
+func (s *PrefixSet) Clear() {
+	s.guard.check("clear")
+
+	s.root = new(radixNode)
+	s.size = 0
+}
+
+// Size returns the number of elements in s.
+func (s *PrefixSet) Size() int {
+	return s.size
+}
+
+// Empty returns true if there are no elements in s.
+func (s *PrefixSet) Empty() bool {
+	return s.Size() == 0
+}
+
+// Union returns a set that contains all elements of s and col combined.
+func (s *PrefixSet) Union(col Collection[string]) Collection[string] {
+	result := NewPrefixSet()
+	insert(result, s)
+	insert(result, col)
+	return result
+}
+
+// Difference returns a set that contains elements of s that are not in col.
+func (s *PrefixSet) Difference(col Collection[string]) Collection[string] {
+	result := NewPrefixSet()
+	for item := range s.Items() {
+		if !col.Contains(item) {
+			result.Insert(item)
+		}
+	}
+	return result
+}
+
+// Intersect returns a set that contains elements that are present in both s and col.
+func (s *PrefixSet) Intersect(col Collection[string]) Collection[string] {
+	result := NewPrefixSet()
+	intersect(result, s, col)
+	return result
+}
+
+// Copy creates a copy of s.
+func (s *PrefixSet) Copy() *PrefixSet {
+	return &PrefixSet{
+		root: s.root.clone(),
+		size: s.size,
+	}
+}
+
+// Slice returns the elements of s as a slice, in lexicographic order.
+func (s *PrefixSet) Slice() []string {
+	return s.AppendSlice(make([]string, 0, s.Size()))
+}
+
+// AppendSlice appends the elements of s to dst and returns the extended slice,
+// e.g. for reusing the same buffer across calls rather than allocating a new
+// slice via Slice each time.
+//
+// Elements are appended in lexicographic order.
+func (s *PrefixSet) AppendSlice(dst []string) []string {
+	dst = slices.Grow(dst, s.Size())
+	for item := range s.Items() {
+		dst = append(dst, item)
+	}
+	return dst
+}
+
+// String creates a string representation of s, using "%v" printf formatting
+// each element into a string. The result contains elements in lexicographic
+// order.
+func (s *PrefixSet) String() string {
+	return s.StringFunc(func(element string) string {
+		return fmt.Sprintf("%v", element)
+	})
+}
+
+// StringFunc creates a string representation of s, using f to transform each
+// element into a string. The result contains elements in lexicographic order.
+func (s *PrefixSet) StringFunc(f func(string) string) string {
+	l := make([]string, 0, s.Size())
+	for item := range s.Items() {
+		l = append(l, f(item))
+	}
+	return fmt.Sprintf("%s", l)
+}
+
+// Equal return whether s and o contain the same elements.
+func (s *PrefixSet) Equal(o *PrefixSet) bool {
+	if s.Size() != o.Size() {
+		return false
+	}
+	next, stop := iter.Pull(o.Items())
+	defer stop()
+	for item := range s.Items() {
+		if other, _ := next(); item != other {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualSet returns s and col contain the same elements.
+func (s *PrefixSet) EqualSet(col Collection[string]) bool {
+	return equalSet(s, col)
+}
+
+// EqualSlice returns whether s and items contain the same elements.
+//
+// The items slice may contain duplicates.
+//
+// If the items slice is known to contain no duplicates, EqualSliceSet may be
+// used instead as a faster implementation.
+//
+// To detect if a slice is a subset of s, use ContainsSlice.
+func (s *PrefixSet) EqualSlice(items []string) bool {
+	return s.Equal(PrefixSetFrom(items))
+}
+
+// EqualSliceSet returns whether s and items contain exactly the same elements,
+// where the elements of items are expected to be set-like. If items contains
+// duplicates EqualSliceSet returns false. For comparing s to a slice that may
+// contain duplicate elements, use EqualSlice instead.
+//
+// To detect if a slice is a subset of s, use ContainsSlice.
+func (s *PrefixSet) EqualSliceSet(items []string) bool {
+	return equalSliceSet(s, NewPrefixSet(), items)
+}
+
+// Items returns a generator function for iterating each element in s by using
+// the range keyword. Elements are produced in lexicographic order.
+//
+// The elements of s must not be inserted or removed during iteration. Use
+// Snapshot to iterate s while modifying it.
+//
+//	for element := range s.Items() { ... }
+func (s *PrefixSet) Items() iter.Seq[string] {
+	return s.PrefixItems("")
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *PrefixSet) MarshalJSON() ([]byte, error) {
+	return marshalJSON[string](s)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *PrefixSet) UnmarshalJSON(data []byte) error {
+	if s.root == nil {
+		s.root = new(radixNode)
+	}
+	return unmarshalJSON[string](s, data)
+}
+
+// Radix Tree Invariants
+//
+// 1. the label of every node other than the root is non-empty
+// 2. the children of a node are sorted by, and unique in, the first byte of
+//    their labels
+// 3. every node other than the root is a leaf or has at least two children
+//
+// which means the tree for a given set of elements is unique, and the elements
+// of every subtree share a common prefix.
+
+type radixNode struct {
+	label    string
+	leaf     bool
+	children []*radixNode
+}
+
+// index returns the position of the child of n whose label begins with b, or
+// the position at which such a child would be inserted.
+func (n *radixNode) index(b byte) (int, bool) {
+	lo, hi := 0, len(n.children)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if n.children[mid].label[0] < b {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, lo < len(n.children) && n.children[lo].label[0] == b
+}
+
+// locate returns the first node beneath n whose path from n has key as a
+// prefix, along with the remainder of that node's label not covered by key.
+//
+// A nil node is returned if no path beneath n begins with key.
+func (n *radixNode) locate(key string) (*radixNode, string) {
+	for key != "" {
+		i, found := n.index(key[0])
+		if !found {
+			return nil, ""
+		}
+		child := n.children[i]
+		l := commonPrefix(key, child.label)
+		switch {
+		case l == len(key):
+			return child, child.label[l:]
+		case l < len(child.label):
+			return nil, ""
+		}
+		n, key = child, key[l:]
+	}
+	return n, ""
+}
+
+// remove unsets the leaf at the end of key beneath n, and returns whether it
+// was set.
+func (n *radixNode) remove(key string) bool {
+	if key == "" {
+		if !n.leaf {
+			return false
+		}
+		n.leaf = false
+		return true
+	}
+
+	i, found := n.index(key[0])
+	if !found {
+		return false
+	}
+	child := n.children[i]
+	if !strings.HasPrefix(key, child.label) || !child.remove(key[len(child.label):]) {
+		return false
+	}
+	n.compact(i)
+	return true
+}
+
+// removePrefix removes every leaf beneath n whose path from n begins with key,
+// and returns the number of leaves removed.
+func (n *radixNode) removePrefix(key string) int {
+	if key == "" {
+		removed := n.count()
+		n.leaf = false
+		n.children = nil
+		return removed
+	}
+
+	i, found := n.index(key[0])
+	if !found {
+		return 0
+	}
+	child := n.children[i]
+	l := commonPrefix(key, child.label)
+	switch {
+	case l == len(key):
+		// key ends within or at the end of the label of child
+		n.children = append(n.children[:i], n.children[i+1:]...)
+		return child.count()
+	case l < len(child.label):
+		return 0
+	}
+
+	removed := child.removePrefix(key[l:])
+	if removed > 0 {
+		n.compact(i)
+	}
+	return removed
+}
+
+// compact restores the invariants of the child of n at position i, after a
+// leaf has been removed from the subtree of that child.
+func (n *radixNode) compact(i int) {
+	child := n.children[i]
+	switch {
+	case child.leaf:
+	case len(child.children) == 0:
+		n.children = append(n.children[:i], n.children[i+1:]...)
+	case len(child.children) == 1:
+		grandchild := child.children[0]
+		grandchild.label = child.label + grandchild.label
+		n.children[i] = grandchild
+	}
+}
+
+// count returns the number of leaves beneath and including n.
+func (n *radixNode) count() int {
+	c := 0
+	if n.leaf {
+		c++
+	}
+	for _, child := range n.children {
+		c += child.count()
+	}
+	return c
+}
+
+// walk yields the path of each leaf beneath and including n in lexicographic
+// order, where buf is the path up to and including n.
+func (n *radixNode) walk(buf []byte, yield func(string) bool) bool {
+	if n.leaf && !yield(string(buf)) {
+		return false
+	}
+	for _, child := range n.children {
+		if !child.walk(append(buf, child.label...), yield) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *radixNode) clone() *radixNode {
+	c := &radixNode{
+		label: n.label,
+		leaf:  n.leaf,
+	}
+	if len(n.children) > 0 {
+		c.children = make([]*radixNode, len(n.children))
+		for i, child := range n.children {
+			c.children[i] = child.clone()
+		}
+	}
+	return c
+}
+
+// commonPrefix returns the length of the longest common prefix of a and b.
+func commonPrefix(a, b string) int {
+	l := min(len(a), len(b))
+	for i := 0; i < l; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return l
+}
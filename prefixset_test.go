@@ -0,0 +1,232 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+// radixInvariants makes assertions about the structure of a radix tree
+func radixInvariants(t *testing.T, s *PrefixSet) {
+	slice := s.Slice()
+	must.True(t, sort.StringsAreSorted(slice), must.Sprint("elements are not sorted"))
+	must.Eq(t, s.Size(), len(slice), must.Sprint("tree is wrong size"))
+
+	var check func(n *radixNode, path string)
+	check = func(n *radixNode, path string) {
+		for i, child := range n.children {
+			must.NotEq(t, "", child.label, must.Sprintf("empty label beneath %q", path))
+			if i > 0 {
+				must.Less(t, child.label[0], n.children[i-1].label[0], must.Sprintf("unsorted children beneath %q", path))
+			}
+			must.True(t, child.leaf || len(child.children) > 1, must.Sprintf("uncompacted node at %q", path+child.label))
+			check(child, path+child.label)
+		}
+	}
+	check(s.root, "")
+}
+
+// paths returns n random slash separated paths drawn from a small alphabet of
+// segments, so that many paths share prefixes.
+func paths(n int) []string {
+	segments := []string{"a", "ab", "abc", "b", "ba", "nomad", "vault", "consul"}
+	result := make([]string, n)
+	for i := range result {
+		parts := make([]string, 1+rand.Intn(4))
+		for j := range parts {
+			parts[j] = segments[rand.Intn(len(segments))]
+		}
+		result[i] = strings.Join(parts, "/")
+	}
+	return result
+}
+
+func TestPrefixSet_Insert(t *testing.T) {
+	s := NewPrefixSet()
+	must.Empty(t, s)
+	radixInvariants(t, s)
+
+	items := []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "rom", ""}
+	for _, item := range items {
+		must.True(t, s.Insert(item))
+		must.False(t, s.Insert(item))
+		radixInvariants(t, s)
+	}
+	must.Size(t, len(items), s)
+	sort.Strings(items)
+	must.Eq(t, items, s.Slice())
+	must.True(t, s.ContainsSlice(items))
+	must.False(t, s.Contains("r"))
+	must.False(t, s.Contains("roman"))
+	must.False(t, s.Contains("romanes"))
+}
+
+func TestPrefixSet_Remove(t *testing.T) {
+	items := []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "rom", ""}
+	s := PrefixSetFrom(items)
+	must.False(t, s.Remove("roman"))
+	must.False(t, s.Remove("r"))
+
+	for i, item := range items {
+		must.True(t, s.Remove(item))
+		must.False(t, s.Remove(item))
+		must.Size(t, len(items)-i-1, s)
+		radixInvariants(t, s)
+	}
+	must.Empty(t, s)
+}
+
+func TestPrefixSet_random(t *testing.T) {
+	s := NewPrefixSet()
+	ref := New[string](0)
+
+	for _, item := range paths(10 * size) {
+		if rand.Intn(2) == 0 {
+			must.Eq(t, ref.Insert(item), s.Insert(item))
+		} else {
+			must.Eq(t, ref.Remove(item), s.Remove(item))
+		}
+	}
+	radixInvariants(t, s)
+	must.True(t, s.EqualSet(ref))
+}
+
+func TestPrefixSet_PrefixItems(t *testing.T) {
+	s := PrefixSetFrom([]string{"acl/", "acl/nomad", "acl/nomad/jobs", "acl/vault", "kv/a", "kv/b"})
+
+	collect := func(prefix string) []string {
+		result := make([]string, 0)
+		for item := range s.PrefixItems(prefix) {
+			result = append(result, item)
+		}
+		return result
+	}
+
+	must.Eq(t, []string{"acl/", "acl/nomad", "acl/nomad/jobs", "acl/vault"}, collect("acl"))
+	must.Eq(t, []string{"acl/nomad", "acl/nomad/jobs"}, collect("acl/no"))
+	must.Eq(t, []string{"acl/nomad/jobs"}, collect("acl/nomad/"))
+	must.Eq(t, []string{"kv/a", "kv/b"}, collect("kv/"))
+	must.Eq(t, []string{}, collect("kv/c"))
+	must.Eq(t, []string{}, collect("acl/nomads"))
+	must.Eq(t, s.Slice(), collect(""))
+
+	must.True(t, s.HasPrefix(""))
+	must.True(t, s.HasPrefix("a"))
+	must.True(t, s.HasPrefix("acl/nomad/jobs"))
+	must.False(t, s.HasPrefix("acl/nomad/jobs/"))
+	must.False(t, s.HasPrefix("x"))
+	must.False(t, NewPrefixSet().HasPrefix(""))
+
+	for item := range s.PrefixItems("acl/") {
+		must.Eq(t, "acl/", item)
+		break
+	}
+}
+
+func TestPrefixSet_LongestPrefixOf(t *testing.T) {
+	s := PrefixSetFrom([]string{"acl/", "acl/nomad", "acl/nomad/jobs", "kv"})
+
+	try := func(item, exp string, expOK bool) {
+		result, ok := s.LongestPrefixOf(item)
+		must.Eq(t, expOK, ok, must.Sprintf("item %q", item))
+		must.Eq(t, exp, result, must.Sprintf("item %q", item))
+	}
+
+	try("acl/nomad/jobs/example", "acl/nomad/jobs", true)
+	try("acl/nomad/job", "acl/nomad", true)
+	try("acl/nomad", "acl/nomad", true)
+	try("acl/vault", "acl/", true)
+	try("acl", "", false)
+	try("kvs", "kv", true)
+	try("x", "", false)
+	try("", "", false)
+
+	s.Insert("")
+	try("x", "", true)
+}
+
+func TestPrefixSet_DeletePrefix(t *testing.T) {
+	items := []string{"acl/", "acl/nomad", "acl/nomad/jobs", "acl/vault", "aclx", "kv/a", "kv/b"}
+
+	s := PrefixSetFrom(items)
+	must.Eq(t, 2, s.DeletePrefix("acl/n"))
+	must.Eq(t, []string{"acl/", "acl/vault", "aclx", "kv/a", "kv/b"}, s.Slice())
+	radixInvariants(t, s)
+
+	must.Eq(t, 0, s.DeletePrefix("acl/nomad"))
+	must.Eq(t, 0, s.DeletePrefix("z"))
+	must.Eq(t, 0, s.DeletePrefix("acl/vaults"))
+
+	must.Eq(t, 2, s.DeletePrefix("acl/"))
+	must.Eq(t, []string{"aclx", "kv/a", "kv/b"}, s.Slice())
+	radixInvariants(t, s)
+
+	must.Eq(t, 1, s.DeletePrefix("kv/a"))
+	must.Eq(t, []string{"aclx", "kv/b"}, s.Slice())
+	radixInvariants(t, s)
+
+	must.Eq(t, 2, s.DeletePrefix(""))
+	must.Empty(t, s)
+	radixInvariants(t, s)
+
+	// compare against a reference after deleting random prefixes
+	s = PrefixSetFrom(paths(size))
+	ref := From(s.Slice())
+	for _, prefix := range paths(size / 10) {
+		prefix = prefix[:rand.Intn(len(prefix)+1)]
+		removed := ref.RemoveFunc(func(item string) bool {
+			return strings.HasPrefix(item, prefix)
+		})
+		must.Eq(t, removed, s.DeletePrefix(prefix) > 0)
+	}
+	radixInvariants(t, s)
+	must.True(t, s.EqualSet(ref))
+}
+
+func TestPrefixSet_Collection(t *testing.T) {
+	a := PrefixSetFrom([]string{"a", "b", "c", "d", "e"})
+	b := PrefixSetFrom([]string{"d", "e", "f", "g"})
+
+	must.Eq(t, "[a b c d e f g]", a.Union(b).String())
+	must.Eq(t, "[a b c]", a.Difference(b).String())
+	must.Eq(t, "[d e]", a.Intersect(b).String())
+	must.Eq(t, "[d e]", a.Intersect(From([]string{"d", "e", "z"})).String())
+
+	must.True(t, a.Subset(From([]string{"a", "e"})))
+	must.False(t, a.Subset(b))
+	must.True(t, a.ProperSubset(From([]string{"a", "b", "c", "d"})))
+	must.False(t, a.ProperSubset(From([]string{"a", "b", "c", "d", "e"})))
+
+	must.True(t, a.EqualSlice([]string{"e", "d", "c", "b", "a", "a"}))
+	must.False(t, a.EqualSliceSet([]string{"e", "d", "c", "b", "a", "a"}))
+	must.True(t, a.EqualSliceSet([]string{"e", "d", "c", "b", "a"}))
+
+	c := a.Copy()
+	must.True(t, c.Equal(a))
+	c.Remove("c")
+	must.False(t, c.Equal(a))
+	must.True(t, a.Contains("c"))
+
+	must.True(t, a.RemoveFunc(func(s string) bool { return s < "c" }))
+	must.Eq(t, []string{"c", "d", "e"}, a.Slice())
+	must.True(t, a.RemoveSet(From([]string{"c", "z"})))
+	must.Eq(t, []string{"d", "e"}, a.Slice())
+}
+
+func TestPrefixSet_JSON(t *testing.T) {
+	s := PrefixSetFrom([]string{"b", "c", "a"})
+	bs, err := json.Marshal(s)
+	must.NoError(t, err)
+	must.Eq(t, `["a","b","c"]`, string(bs))
+
+	var result PrefixSet
+	must.NoError(t, json.Unmarshal(bs, &result))
+	must.True(t, s.Equal(&result))
+}
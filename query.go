@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import "iter"
+
+// Query is a lazily evaluated selection of the elements of a set, as created
+// by Where, e.g.
+//
+//	n := set.Where(s, isAdmin).Where(isActive).Count()
+//
+// The elements of the underlying set are not visited until the query is
+// evaluated by Count, Exists, Slice, or Items, and are visited each time the
+// query is evaluated. No intermediate sets are created.
+type Query[T any] struct {
+	items iter.Seq[T]
+}
+
+// Where creates a Query selecting the elements of col which satisfy predicate.
+func Where[T any](col Collection[T], predicate func(T) bool) Query[T] {
+	return Query[T]{items: col.Items()}.Where(predicate)
+}
+
+// Where creates a Query selecting the elements of q which also satisfy
+// predicate.
+func (q Query[T]) Where(predicate func(T) bool) Query[T] {
+	return Query[T]{items: func(yield func(T) bool) {
+		for item := range q.items {
+			if predicate(item) && !yield(item) {
+				return
+			}
+		}
+	}}
+}
+
+// Count returns the number of elements selected by q.
+func (q Query[T]) Count() int {
+	count := 0
+	for range q.items {
+		count++
+	}
+	return count
+}
+
+// Exists returns whether q selects any element, stopping at the first.
+func (q Query[T]) Exists() bool {
+	for range q.items {
+		return true
+	}
+	return false
+}
+
+// Slice creates a slice of the elements selected by q, in the iteration order
+// of the underlying set.
+func (q Query[T]) Slice() []T {
+	result := make([]T, 0)
+	for item := range q.items {
+		result = append(result, item)
+	}
+	return result
+}
+
+// Items returns a generator function for iterating each element selected by q
+// by using the range keyword, in the iteration order of the underlying set.
+//
+//	for element := range q.Items() { ... }
+func (q Query[T]) Items() iter.Seq[T] {
+	return q.items
+}
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestWhere(t *testing.T) {
+	s := TreeSetFrom(ints(10), cmp.Compare[int])
+	even := func(i int) bool { return i%2 == 0 }
+	large := func(i int) bool { return i > 5 }
+
+	t.Run("count", func(t *testing.T) {
+		must.Eq(t, 5, Where[int](s, even).Count())
+		must.Eq(t, 3, Where[int](s, even).Where(large).Count())
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		must.True(t, Where[int](s, even).Exists())
+		must.False(t, Where[int](s, large).Where(func(i int) bool { return i > 10 }).Exists())
+	})
+
+	t.Run("exists stops", func(t *testing.T) {
+		visited := 0
+		must.True(t, Where[int](s, func(int) bool {
+			visited++
+			return true
+		}).Exists())
+		must.Eq(t, 1, visited)
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		must.Eq(t, []int{6, 8, 10}, Where[int](s, even).Where(large).Slice())
+		must.Eq(t, []int{}, Where[int](New[int](0), even).Slice())
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		q := Where[int](s, even)
+		s.Insert(12)
+		must.Eq(t, 6, q.Count())
+	})
+
+	t.Run("items", func(t *testing.T) {
+		var result []int
+		for item := range Where[int](s, large).Items() {
+			result = append(result, item)
+			if len(result) == 2 {
+				break
+			}
+		}
+		must.Eq(t, []int{6, 7}, result)
+	})
+}
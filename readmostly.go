@@ -0,0 +1,336 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"encoding/json"
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// ReadMostlySet provides a set that is safe for concurrent use by multiple
+// goroutines, optimized for workloads where reads vastly outnumber writes, e.g.
+// membership checks against a set of feature flags that changes rarely.
+//
+// The underlying data structure is an immutable Set, which is replaced as a
+// whole by each modification. Reads load the current Set via an atomic pointer
+// and so never block and never contend with one another, at the same cost as
+// reading a Set directly. Each modification copies the current Set, applies
+// the change to the copy, and then swaps in the copy; writers are serialized,
+// and each costs O(n) time and memory.
+//
+// Operations that read several elements (e.g. Items, Slice, Union) observe a
+// single consistent snapshot of s, unaffected by concurrent modification.
+//
+// For workloads with frequent modification, SkipSet scales better.
+type ReadMostlySet[T comparable] struct {
+	current atomic.Pointer[Set[T]]
+	lock    sync.Mutex // serializes writers
+}
+
+// NewReadMostlySet creates a new ReadMostlySet with initial underlying capacity
+// of size.
+//
+// T may be any comparable type.
+func NewReadMostlySet[T comparable](size int) *ReadMostlySet[T] {
+	s := new(ReadMostlySet[T])
+	s.current.Store(New[T](size))
+	return s
+}
+
+// ReadMostlySetFrom creates a new ReadMostlySet containing each item in items.
+//
+// T may be any comparable type.
+func ReadMostlySetFrom[T comparable](items []T) *ReadMostlySet[T] {
+	s := new(ReadMostlySet[T])
+	s.current.Store(From(items))
+	return s
+}
+
+// View returns the current contents of s as a Set, which is not affected by
+// subsequent modification of s.
+//
+// The returned Set is shared with s and other callers of View, and so must
+// not be modified. Use Copy for a Set that may be modified.
+func (s *ReadMostlySet[T]) View() *Set[T] {
+	return s.current.Load()
+}
+
+// update applies f to a copy of the current contents of s, and replaces the
+// contents of s with the copy if f reports the copy was modified.
+func (s *ReadMostlySet[T]) update(f func(next *Set[T]) bool) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	next := s.View().Copy()
+	if !f(next) {
+		return false
+	}
+	s.current.Store(next)
+	return true
+}
+
+// WithLock calls f with a copy of the contents of s, while preventing any other
+// modification of s, and then replaces the contents of s with the copy. This
+// enables modifications of several steps which must not be interleaved with
+// other modifications, e.g.
+//
+//	flags.WithLock(func(s *set.Set[string]) {
+//	  if !s.Contains("beta") {
+//	    s.Insert("beta")
+//	    s.Remove("alpha")
+//	  }
+//	})
+//
+// Readers observe either none or all of the changes made by f. The Set passed
+// to f must not be retained after f returns, and f must not call the methods
+// of the ReadMostlySet (which may deadlock).
+func (s *ReadMostlySet[T]) WithLock(f func(s *Set[T])) {
+	s.update(func(next *Set[T]) bool {
+		f(next)
+		return true
+	})
+}
+
+// RWithLock calls f with the current contents of s, which are not modified for
+// the duration of f, enabling reads of several steps to observe a consistent
+// view of s. Unlike WithLock, RWithLock does not block concurrent modification
+// of s, which is instead made to a copy.
+//
+// The Set passed to f must not be modified. Equivalent to calling f with the
+// result of View.
+func (s *ReadMostlySet[T]) RWithLock(f func(s *Set[T])) {
+	f(s.View())
+}
+
+// Insert item into s.
+//
+// Returns true if s was modified (item was not already in s), false otherwise.
+func (s *ReadMostlySet[T]) Insert(item T) bool {
+	if s.Contains(item) {
+		return false
+	}
+	return s.update(func(next *Set[T]) bool {
+		return next.Insert(item)
+	})
+}
+
+// InsertSlice will insert each item in items into s.
+//
+// Return true if s was modified (at least one item was not already in s), false otherwise.
+func (s *ReadMostlySet[T]) InsertSlice(items []T) bool {
+	return s.update(func(next *Set[T]) bool {
+		return next.InsertSlice(items)
+	})
+}
+
+// InsertSet will insert each element of col into s.
+//
+// Return true if s was modified (at least one item of col was not already in s), false otherwise.
+func (s *ReadMostlySet[T]) InsertSet(col Collection[T]) bool {
+	return s.update(func(next *Set[T]) bool {
+		return next.InsertSet(col)
+	})
+}
+
+// Remove will remove item from s.
+//
+// Return true if s was modified (item was present), false otherwise.
+func (s *ReadMostlySet[T]) Remove(item T) bool {
+	if !s.Contains(item) {
+		return false
+	}
+	return s.update(func(next *Set[T]) bool {
+		return next.Remove(item)
+	})
+}
+
+// RemoveSlice will remove each item in items from s.
+//
+// Return true if s was modified (any item was present), false otherwise.
+func (s *ReadMostlySet[T]) RemoveSlice(items []T) bool {
+	return s.update(func(next *Set[T]) bool {
+		return next.RemoveSlice(items)
+	})
+}
+
+// RemoveSet will remove each element of col from s.
+//
+// Return true if s was modified (any item of col was present in s), false otherwise.
+func (s *ReadMostlySet[T]) RemoveSet(col Collection[T]) bool {
+	return s.update(func(next *Set[T]) bool {
+		return next.RemoveSet(col)
+	})
+}
+
+// RemoveFunc will remove each element from s that satisfies condition f.
+//
+// Return true if s was modified, false otherwise.
+func (s *ReadMostlySet[T]) RemoveFunc(f func(T) bool) bool {
+	return s.update(func(next *Set[T]) bool {
+		return next.RemoveFunc(f)
+	})
+}
+
+// Contains returns whether item is present in s.
+func (s *ReadMostlySet[T]) Contains(item T) bool {
+	return s.View().Contains(item)
+}
+
+// ContainsSlice returns whether all elements in items are present in s.
+func (s *ReadMostlySet[T]) ContainsSlice(items []T) bool {
+	return s.View().ContainsSlice(items)
+}
+
+// MissingFrom returns the elements of items which are not present in s, e.g. for
+// reporting which of a list of requested identifiers do not exist.
+//
+// The result preserves the order of items, including any duplicates.
+func (s *ReadMostlySet[T]) MissingFrom(items []T) []T {
+	return s.View().MissingFrom(items)
+}
+
+// ClassifySlice partitions items into those present in s, those absent from
+// s, and duplicates (i.e. each repeated occurrence of an item earlier in
+// items), in a single pass, e.g. for reporting which of a list of requested
+// identifiers are already known, which are new, and which were repeated.
+//
+// Each result preserves the order of items. The first occurrence of an item
+// is in either present or absent, and each later occurrence is in duplicates.
+func (s *ReadMostlySet[T]) ClassifySlice(items []T) (present, absent, duplicates []T) {
+	return s.View().ClassifySlice(items)
+}
+
+// Subset returns whether col is a subset of s.
+func (s *ReadMostlySet[T]) Subset(col Collection[T]) bool {
+	return s.View().Subset(col)
+}
+
+// ProperSubset returns whether col is a proper subset of s.
+func (s *ReadMostlySet[T]) ProperSubset(col Collection[T]) bool {
+	return s.View().ProperSubset(col)
+}
+
+// Size returns the cardinality of s.
+func (s *ReadMostlySet[T]) Size() int {
+	return s.View().Size()
+}
+
+// Empty returns true if s contains no elements, false otherwise.
+func (s *ReadMostlySet[T]) Empty() bool {
+	return s.View().Empty()
+}
+
+// Union returns a set that contains all elements of s and col combined.
+//
+// The result is a Set, which is not safe for concurrent modification.
+func (s *ReadMostlySet[T]) Union(col Collection[T]) Collection[T] {
+	return s.View().Union(col)
+}
+
+// Difference returns a set that contains elements of s that are not in col.
+//
+// The result is a Set, which is not safe for concurrent modification.
+func (s *ReadMostlySet[T]) Difference(col Collection[T]) Collection[T] {
+	return s.View().Difference(col)
+}
+
+// Intersect returns a set that contains elements that are present in both s and col.
+//
+// The result is a Set, which is not safe for concurrent modification.
+func (s *ReadMostlySet[T]) Intersect(col Collection[T]) Collection[T] {
+	return s.View().Intersect(col)
+}
+
+// Copy creates a copy of s.
+func (s *ReadMostlySet[T]) Copy() *ReadMostlySet[T] {
+	result := new(ReadMostlySet[T])
+	result.current.Store(s.View())
+	return result
+}
+
+// Slice creates a copy of s as a slice. Elements are in no particular order.
+func (s *ReadMostlySet[T]) Slice() []T {
+	return s.View().Slice()
+}
+
+// String creates a string representation of s, using "%v" printf formating to transform
+// each element into a string. The result contains elements sorted by their lexical
+// string order.
+func (s *ReadMostlySet[T]) String() string {
+	return s.View().String()
+}
+
+// StringFunc creates a string representation of s, using f to transform each element
+// into a string. The result contains elements sorted by their lexical string order.
+func (s *ReadMostlySet[T]) StringFunc(f func(element T) string) string {
+	return s.View().StringFunc(f)
+}
+
+// ContentHash returns a hash of the elements of s which does not depend on
+// their order, combining the hash of each element as produced by hash, e.g.
+// for cheaply comparing sets of different types or in different processes.
+//
+// Sets containing the same elements have the same content hash regardless of
+// their type or internal layout. Sets containing different elements are
+// unlikely, but not guaranteed, to have different content hashes.
+func (s *ReadMostlySet[T]) ContentHash(hash func(T) uint64) uint64 {
+	return s.View().ContentHash(hash)
+}
+
+// EqualSet returns whether s and col contain the same elements.
+func (s *ReadMostlySet[T]) EqualSet(col Collection[T]) bool {
+	return s.View().EqualSet(col)
+}
+
+// EqualSlice returns whether s and items contain the same elements.
+//
+// The items slice may contain duplicates.
+func (s *ReadMostlySet[T]) EqualSlice(items []T) bool {
+	return s.View().EqualSlice(items)
+}
+
+// EqualSliceSet returns whether s and items contain exactly the same elements,
+// where the elements of items are expected to be set-like. If items contains
+// duplicates EqualSliceSet returns false. For comparing s to a slice that may
+// contain duplicate elements, use EqualSlice instead.
+//
+// To detect if a slice is a subset of s, use ContainsSlice.
+func (s *ReadMostlySet[T]) EqualSliceSet(items []T) bool {
+	return s.View().EqualSliceSet(items)
+}
+
+// Items returns a generator function for iterating each element in s by using
+// the range keyword. Elements are produced in no particular order.
+//
+// The elements produced are those of s at the time Items is called, and so s
+// may be freely modified during iteration.
+//
+//	for element := range s.Items() { ... }
+func (s *ReadMostlySet[T]) Items() iter.Seq[T] {
+	return s.View().Items()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *ReadMostlySet[T]) MarshalJSON() ([]byte, error) {
+	return s.View().MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// The set must have been created by NewReadMostlySet or ReadMostlySetFrom.
+func (s *ReadMostlySet[T]) UnmarshalJSON(data []byte) error {
+	if s.View() == nil {
+		return errNoConstructor("ReadMostlySet")
+	}
+	slice := make([]T, 0)
+	if err := json.Unmarshal(data, &slice); err != nil {
+		return err
+	}
+	s.InsertSlice(slice)
+	return nil
+}
+
+func (s *ReadMostlySet[T]) concurrent() {}
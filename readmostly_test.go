@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+var _ ConcurrentCollection[int] = (*ReadMostlySet[int])(nil)
+
+func TestReadMostlySet(t *testing.T) {
+	t.Run("insert remove", func(t *testing.T) {
+		s := NewReadMostlySet[int](0)
+		must.True(t, s.Insert(1))
+		must.False(t, s.Insert(1))
+		must.True(t, s.InsertSlice([]int{2, 3}))
+		must.Eq(t, 3, s.Size())
+		must.True(t, s.Remove(2))
+		must.False(t, s.Remove(2))
+		must.True(t, s.RemoveFunc(func(i int) bool { return i > 2 }))
+		must.Eq(t, "[1]", s.String())
+	})
+
+	t.Run("view", func(t *testing.T) {
+		s := ReadMostlySetFrom([]int{1, 2})
+		view := s.View()
+		s.Insert(3)
+		must.Eq(t, Of(1, 2), view)
+		must.Eq(t, Of(1, 2, 3), s.View())
+	})
+
+	t.Run("unmodified", func(t *testing.T) {
+		s := ReadMostlySetFrom([]int{1, 2})
+		view := s.View()
+		must.False(t, s.InsertSlice([]int{1, 2}))
+		must.False(t, s.RemoveSlice([]int{3}))
+		must.EqOp(t, view, s.View())
+	})
+
+	t.Run("items", func(t *testing.T) {
+		s := ReadMostlySetFrom([]int{1, 2, 3})
+		count := 0
+		for item := range s.Items() {
+			s.Remove(item)
+			s.Insert(item + 10)
+			count++
+		}
+		must.Eq(t, 3, count)
+		must.Eq(t, Of(11, 12, 13), s.View())
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		s := ReadMostlySetFrom([]int{1, 2})
+		c := s.Copy()
+		c.Insert(3)
+		must.Eq(t, 2, s.Size())
+		must.Eq(t, 3, c.Size())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		s := ReadMostlySetFrom([]int{1})
+		bs, err := json.Marshal(s)
+		must.NoError(t, err)
+		must.Eq(t, "[1]", string(bs))
+
+		dst := NewReadMostlySet[int](0)
+		must.NoError(t, json.Unmarshal([]byte("[2,3]"), dst))
+		must.Eq(t, Of(2, 3), dst.View())
+
+		var zero ReadMostlySet[int]
+		must.Error(t, json.Unmarshal([]byte("[2,3]"), &zero))
+	})
+}
+
+func TestReadMostlySet_WithLock(t *testing.T) {
+	s := ReadMostlySetFrom([]int{1})
+
+	// every goroutine moves the element 1 to 2, but only one may do so
+	var moved atomic.Int32
+	var wg sync.WaitGroup
+	for w := 0; w < readers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.WithLock(func(s *Set[int]) {
+				if s.Remove(1) {
+					s.Insert(2)
+					moved.Add(1)
+				}
+			})
+		}()
+	}
+	wg.Wait()
+	must.Eq(t, 1, moved.Load())
+
+	s.RWithLock(func(view *Set[int]) {
+		s.Insert(3)
+		must.Eq(t, Of(2), view)
+	})
+	must.Eq(t, Of(2, 3), s.View())
+}
+
+func TestReadMostlySet_concurrent(t *testing.T) {
+	s := NewReadMostlySet[int](0)
+
+	var wg sync.WaitGroup
+	for w := 0; w < readers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				s.Insert(i)
+				if i%2 == 0 {
+					s.Remove(i)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_ = s.Contains(i)
+				for item := range s.Items() {
+					_ = item
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	must.Eq(t, 50, s.Size())
+	for i := 1; i < 100; i += 2 {
+		must.True(t, s.Contains(i))
+	}
+}
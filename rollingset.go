@@ -0,0 +1,214 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"fmt"
+	"iter"
+	"time"
+)
+
+// RollingSet provides a set of elements inserted within a sliding window of
+// time, e.g. for deduplicating the events of the last 10 minutes.
+//
+// The window is divided into a fixed number of buckets, each spanning an
+// equal interval of time. Inserted elements are stamped into the bucket of
+// the current interval, and once the window has moved beyond the interval of
+// a bucket its elements expire all at once. An element therefore remains in
+// the set for at least (buckets - 1) intervals after it was last inserted,
+// and at most buckets intervals.
+//
+// Use OnEvict to be notified of elements leaving the set, e.g. for releasing
+// the resources held by elements which expire.
+//
+// Not thread safe, and not safe for concurrent modification.
+type RollingSet[T comparable] struct {
+	buckets  []*Set[T]
+	interval time.Duration
+	epoch    time.Time
+	head     int   // index of the bucket of the current interval
+	current  int64 // number of intervals from epoch to the current interval
+	now      func() time.Time
+	onEvict  func(T, EvictReason)
+}
+
+// EvictReason describes why an element was evicted from a set.
+type EvictReason int
+
+const (
+	// EvictExpired indicates an element was evicted because it expired.
+	EvictExpired EvictReason = iota + 1
+
+	// EvictRemoved indicates an element was explicitly removed.
+	EvictRemoved
+)
+
+// String returns a human readable form of r.
+func (r EvictReason) String() string {
+	switch r {
+	case EvictExpired:
+		return "expired"
+	case EvictRemoved:
+		return "removed"
+	default:
+		return fmt.Sprintf("EvictReason(%d)", int(r))
+	}
+}
+
+// NewRollingSet creates a RollingSet with a window of the given number of
+// buckets, each spanning interval, e.g. 10 buckets of 1 minute each for a
+// window of 10 minutes.
+//
+// Panics if buckets is less than 1 or interval is not positive.
+//
+// The window is measured by time.Now; use WithClock to measure it by another
+// clock.
+func NewRollingSet[T comparable](buckets int, interval time.Duration) *RollingSet[T] {
+	if buckets < 1 || interval <= 0 {
+		panic(fmt.Sprintf("rolling set: invalid window (%d buckets of %s)", buckets, interval))
+	}
+	s := &RollingSet[T]{
+		buckets:  make([]*Set[T], buckets),
+		interval: interval,
+		epoch:    time.Now(),
+		now:      time.Now,
+	}
+	for i := range s.buckets {
+		s.buckets[i] = New[T](0)
+	}
+	return s
+}
+
+// WithClock configures s to measure its window using now rather than time.Now,
+// e.g. for testing with a clock that is advanced manually. Returns s.
+//
+// The current interval of s is taken to begin at the time now returns when
+// WithClock is called, and so any elements already in s are retained.
+func (s *RollingSet[T]) WithClock(now func() time.Time) *RollingSet[T] {
+	s.now = now
+	s.epoch = now().Add(-time.Duration(s.current) * s.interval)
+	return s
+}
+
+// OnEvict configures s to call f for each element evicted from s, along with
+// the reason for its eviction. Returns s.
+//
+// The elements of an expired bucket are evicted (in no particular order) by
+// whichever method of s first observes the expiry, and f is called
+// synchronously from that method before it returns. Eviction is not
+// guaranteed to happen promptly, as s does not expire buckets in the
+// background; f must not call the methods of s.
+func (s *RollingSet[T]) OnEvict(f func(item T, reason EvictReason)) *RollingSet[T] {
+	s.onEvict = f
+	return s
+}
+
+// evict calls the OnEvict function of s, if any, for each item of bucket.
+func (s *RollingSet[T]) evict(bucket *Set[T], reason EvictReason) {
+	if s.onEvict == nil {
+		return
+	}
+	for item := range bucket.items {
+		s.onEvict(item, reason)
+	}
+}
+
+// rotate advances the window to the current interval, expiring the buckets of
+// intervals which are no longer within the window.
+func (s *RollingSet[T]) rotate() {
+	current := int64(s.now().Sub(s.epoch) / s.interval)
+	steps := min(current-s.current, int64(len(s.buckets)))
+	for range steps {
+		s.head = (s.head + 1) % len(s.buckets)
+		expired := s.buckets[s.head]
+		s.buckets[s.head] = New[T](0)
+		s.evict(expired, EvictExpired)
+	}
+	s.current = max(s.current, current)
+}
+
+// Insert item into the bucket of the current interval, refreshing its expiry
+// if it is already in s.
+//
+// Return true if item was not already in s, false otherwise.
+func (s *RollingSet[T]) Insert(item T) bool {
+	s.rotate()
+	present := false
+	for i, bucket := range s.buckets {
+		if i != s.head && bucket.Remove(item) {
+			present = true
+		}
+	}
+	return s.buckets[s.head].Insert(item) && !present
+}
+
+// Remove item from s, evicting it with EvictRemoved.
+//
+// Return true if s was modified (item was in s), false otherwise.
+func (s *RollingSet[T]) Remove(item T) bool {
+	s.rotate()
+	for _, bucket := range s.buckets {
+		if bucket.Remove(item) {
+			if s.onEvict != nil {
+				s.onEvict(item, EvictRemoved)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Contains returns whether item was inserted within the window.
+func (s *RollingSet[T]) Contains(item T) bool {
+	s.rotate()
+	for _, bucket := range s.buckets {
+		if bucket.Contains(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the number of elements inserted within the window.
+func (s *RollingSet[T]) Size() int {
+	s.rotate()
+	size := 0
+	for _, bucket := range s.buckets {
+		size += bucket.Size()
+	}
+	return size
+}
+
+// Empty returns true if no elements were inserted within the window.
+func (s *RollingSet[T]) Empty() bool {
+	return s.Size() == 0
+}
+
+// Items returns a generator function for iterating each element inserted
+// within the window by using the range keyword. Elements are produced from
+// the most recently inserted bucket to the oldest.
+//
+//	for element := range s.Items() { ... }
+func (s *RollingSet[T]) Items() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.rotate()
+		for i := range s.buckets {
+			bucket := s.buckets[(s.head-i+len(s.buckets))%len(s.buckets)]
+			for item := range bucket.items {
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Snapshot creates a Set of the elements inserted within the window.
+func (s *RollingSet[T]) Snapshot() *Set[T] {
+	result := New[T](s.Size())
+	for item := range s.Items() {
+		result.items[item] = sentinel
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+)
+
+// fakeClock is a clock which only advances when told to
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func TestRollingSet(t *testing.T) {
+	t.Run("expiry", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewRollingSet[string](3, time.Minute).WithClock(clock.now)
+
+		must.True(t, s.Insert("a"))
+		must.False(t, s.Insert("a"))
+		clock.advance(time.Minute)
+		must.True(t, s.Insert("b"))
+		clock.advance(time.Minute)
+		must.True(t, s.Insert("c"))
+		must.Eq(t, 3, s.Size())
+		must.Eq(t, Of("a", "b", "c"), s.Snapshot())
+
+		// the bucket of "a" expires
+		clock.advance(time.Minute)
+		must.False(t, s.Contains("a"))
+		must.True(t, s.Contains("b"))
+		must.Eq(t, 2, s.Size())
+
+		// every bucket expires
+		clock.advance(time.Hour)
+		must.True(t, s.Empty())
+		must.True(t, s.Insert("a"))
+	})
+
+	t.Run("clock", func(t *testing.T) {
+		s := NewRollingSet[string](2, time.Minute)
+		s.Insert("a")
+
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s.WithClock(clock.now)
+		must.True(t, s.Contains("a"))
+		clock.advance(time.Minute)
+		must.True(t, s.Contains("a"))
+		clock.advance(time.Minute)
+		must.False(t, s.Contains("a"))
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewRollingSet[string](2, time.Minute).WithClock(clock.now)
+
+		s.Insert("a")
+		clock.advance(time.Minute)
+		must.False(t, s.Insert("a"))
+		must.Eq(t, 1, s.Size())
+
+		// "a" was stamped into the current bucket, so survives another interval
+		clock.advance(time.Minute)
+		must.True(t, s.Contains("a"))
+		clock.advance(time.Minute)
+		must.False(t, s.Contains("a"))
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewRollingSet[int](2, time.Second).WithClock(clock.now)
+		s.Insert(1)
+		clock.advance(time.Second)
+		s.Insert(2)
+		must.True(t, s.Remove(1))
+		must.False(t, s.Remove(1))
+		must.Eq(t, []int{2}, SortedSlice[int](s.Snapshot()))
+	})
+
+	t.Run("items", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewRollingSet[int](3, time.Second).WithClock(clock.now)
+		s.Insert(0)
+		clock.advance(time.Second)
+		s.Insert(1)
+		clock.advance(time.Second)
+		s.Insert(2)
+
+		var result []int
+		for item := range s.Items() {
+			result = append(result, item)
+		}
+		must.Eq(t, []int{2, 1, 0}, result)
+	})
+
+	t.Run("evict", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		evicted := make(map[int]EvictReason)
+		s := NewRollingSet[int](2, time.Second).WithClock(clock.now).OnEvict(func(item int, reason EvictReason) {
+			evicted[item] = reason
+		})
+
+		s.Insert(1)
+		s.Insert(2)
+		clock.advance(time.Second)
+		s.Insert(3)
+		must.True(t, s.Remove(3))
+		must.MapEq(t, map[int]EvictReason{3: EvictRemoved}, evicted)
+
+		// expiry is observed by the next method call
+		clock.advance(time.Second)
+		must.MapLen(t, 1, evicted)
+		must.False(t, s.Contains(1))
+		must.MapEq(t, map[int]EvictReason{1: EvictExpired, 2: EvictExpired, 3: EvictRemoved}, evicted)
+		must.Eq(t, "expired", EvictExpired.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		defer func() {
+			must.NotNil(t, recover())
+		}()
+		NewRollingSet[int](0, time.Second)
+	})
+}
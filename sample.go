@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"container/heap"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// SampleWeighted selects up to n distinct elements of col at random, where the
+// probability of selecting each element is proportional to its weight. Elements
+// with a weight of zero or less (or NaN) are never selected.
+//
+// The elements of col are visited once via Items, using the A-Res reservoir
+// sampling algorithm, so only n elements are retained at a time. The result is
+// in the order of selection, i.e. the first element is a weighted random choice
+// among every element of col.
+//
+// If rng is nil, the top-level functions of math/rand/v2 are used. The result
+// is only reproducible for a seeded rng if col is iterated in a deterministic
+// order (e.g. a TreeSet).
+//
+// https://en.wikipedia.org/wiki/Reservoir_sampling#Algorithm_A-Res
+func SampleWeighted[T any](col Collection[T], n int, weight func(T) float64, rng *rand.Rand) []T {
+	if n <= 0 {
+		return []T{}
+	}
+
+	random := rand.Float64
+	if rng != nil {
+		random = rng.Float64
+	}
+
+	r := make(reservoir[T], 0, min(n, col.Size()))
+	for item := range col.Items() {
+		w := weight(item)
+		if !(w > 0) {
+			continue
+		}
+		// equivalent to u^(1/w), but does not underflow for small weights
+		key := math.Log(1-random()) / w
+		switch {
+		case len(r) < n:
+			heap.Push(&r, sample[T]{item: item, key: key})
+		case key > r[0].key:
+			r[0] = sample[T]{item: item, key: key}
+			heap.Fix(&r, 0)
+		}
+	}
+
+	slices.SortFunc(r, func(a, b sample[T]) int {
+		return cmp.Compare(b.key, a.key)
+	})
+	result := make([]T, len(r))
+	for i, s := range r {
+		result[i] = s.item
+	}
+	return result
+}
+
+type sample[T any] struct {
+	item T
+	key  float64
+}
+
+// reservoir is a min-heap of samples ordered by key.
+type reservoir[T any] []sample[T]
+
+func (r reservoir[T]) Len() int           { return len(r) }
+func (r reservoir[T]) Less(i, j int) bool { return r[i].key < r[j].key }
+func (r reservoir[T]) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+func (r *reservoir[T]) Push(x any) {
+	*r = append(*r, x.(sample[T]))
+}
+
+func (r *reservoir[T]) Pop() any {
+	old := *r
+	s := old[len(old)-1]
+	*r = old[:len(old)-1]
+	return s
+}
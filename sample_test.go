@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestSampleWeighted(t *testing.T) {
+	s := TreeSetFrom(ints(size), cmp.Compare[int])
+	weight := func(i int) float64 { return float64(i) }
+
+	result := SampleWeighted[int](s, 10, weight, rand.New(rand.NewPCG(1, 2)))
+	must.SliceLen(t, 10, result)
+	must.Size(t, 10, From(result))
+	must.True(t, s.ContainsSlice(result))
+
+	// reproducible for a seeded rng and ordered set
+	must.Eq(t, result, SampleWeighted[int](s, 10, weight, rand.New(rand.NewPCG(1, 2))))
+
+	// every element is selected when n exceeds the size of the set
+	must.Eq(t, size, len(SampleWeighted[int](s, 2*size, weight, nil)))
+
+	must.SliceEmpty(t, SampleWeighted[int](s, 0, weight, nil))
+	must.SliceEmpty(t, SampleWeighted[int](New[int](0), 3, weight, nil))
+}
+
+func TestSampleWeighted_weights(t *testing.T) {
+	s := From([]string{"never", "rare", "common"})
+	weights := map[string]float64{"never": 0, "rare": 1, "common": 99}
+	weight := func(item string) float64 { return weights[item] }
+
+	// elements with no weight are never selected
+	must.Eq(t, 2, len(SampleWeighted[string](s, 3, weight, nil)))
+
+	counts := make(map[string]int)
+	rng := rand.New(rand.NewPCG(3, 4))
+	for i := 0; i < 1000; i++ {
+		counts[SampleWeighted[string](s, 1, weight, rng)[0]]++
+	}
+	must.Zero(t, counts["never"])
+	must.Greater(t, 900, counts["common"])
+	must.Positive(t, counts["rare"])
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package schemaset provides interoperability between the sets of package set
+// and the schema.Set type of terraform-plugin-sdk, for providers migrating from
+// one to the other.
+//
+// A schema.Set identifies its elements by an int hash code produced by a
+// SchemaSetFunc. The hash functions in this package produce the same hash codes
+// as their counterparts in the SDK (e.g. HashString and schema.HashString), so
+// that a HashSet keyed by those hash codes agrees with a schema.Set about which
+// elements are distinct.
+//
+// This package does not import the SDK. The conversion functions accept any
+// type with a List method, such as *schema.Set, and produce the []interface{}
+// expected by schema.NewSet.
+//
+//	s := set.NewHashSetFunc[string, int](0, schemaset.HashFunc[string](schema.HashString))
+//	ss := schema.NewSet(schema.HashString, schemaset.List[string](s))
+package schemaset
+
+import (
+	"fmt"
+	"hash/crc32"
+	"strconv"
+
+	"github.com/hashicorp/go-set/v3"
+)
+
+// SchemaSetFunc is the signature of the hash function of a schema.Set.
+type SchemaSetFunc = func(interface{}) int
+
+// Lister represents a type with a List method returning each of its elements,
+// such as *schema.Set.
+type Lister interface {
+	List() []interface{}
+}
+
+// String hashes s into a non-negative int, identically to the hashcode.String
+// function used by the SDK.
+func String(s string) int {
+	v := int(crc32.ChecksumIEEE([]byte(s)))
+	if v >= 0 {
+		return v
+	}
+	if -v >= 0 {
+		return -v
+	}
+	// v == MinInt
+	return 0
+}
+
+// HashString is a SchemaSetFunc for string elements, identical to
+// schema.HashString.
+func HashString(v interface{}) int {
+	return String(v.(string))
+}
+
+// HashInt is a SchemaSetFunc for int elements, identical to schema.HashInt.
+func HashInt(v interface{}) int {
+	return String(strconv.Itoa(v.(int)))
+}
+
+// SetFunc adapts a HashFunc producing int hash codes for use as the hash
+// function of a schema.Set.
+//
+// The returned function panics if given an element that is not a T, as do the
+// hash functions of the SDK.
+func SetFunc[T any](fn set.HashFunc[T, int]) SchemaSetFunc {
+	return func(v interface{}) int {
+		return fn(v.(T))
+	}
+}
+
+// HashFunc adapts the hash function of a schema.Set for use as the HashFunc of
+// a HashSet.
+func HashFunc[T any](fn SchemaSetFunc) set.HashFunc[T, int] {
+	return func(item T) int {
+		return fn(item)
+	}
+}
+
+// From creates a HashSet containing each element of l (e.g. a *schema.Set),
+// hashed using fn (e.g. the hash function of the schema.Set).
+//
+// Returns an error if any element of l is not a T.
+func From[T any](l Lister, fn SchemaSetFunc) (*set.HashSet[T, int], error) {
+	items := l.List()
+	s := set.NewHashSetFunc[T, int](len(items), HashFunc[T](fn))
+	for i, item := range items {
+		element, ok := item.(T)
+		if !ok {
+			var zero T
+			return nil, fmt.Errorf("schemaset: element %d is of type %T, not %T", i, item, zero)
+		}
+		s.Insert(element)
+	}
+	return s, nil
+}
+
+// List returns the elements of col as a []interface{}, as expected by
+// schema.NewSet.
+func List[T any](col set.Collection[T]) []interface{} {
+	return set.SliceFunc(col, func(item T) interface{} {
+		return item
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schemaset
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/go-set/v3"
+	"github.com/shoenig/test/must"
+)
+
+// list is a minimal stand-in for *schema.Set
+type list []interface{}
+
+func (l list) List() []interface{} {
+	return l
+}
+
+func TestString(t *testing.T) {
+	// hash codes as produced by the SDK
+	must.Eq(t, 3904355907, String("a"))
+	must.Eq(t, 891568578, String("abc"))
+	must.Eq(t, 0, String(""))
+	must.Eq(t, 891568578, HashString("abc"))
+	must.Eq(t, 841265288, HashInt(42))
+}
+
+func TestSetFunc(t *testing.T) {
+	fn := SetFunc[string](func(s string) int { return len(s) })
+	must.Eq(t, 3, fn("abc"))
+
+	hash := HashFunc[string](fn)
+	must.Eq(t, 3, hash("abc"))
+}
+
+func TestFrom(t *testing.T) {
+	s, err := From[string](list{"b", "a", "b"}, HashString)
+	must.NoError(t, err)
+	must.Size(t, 2, s)
+	must.True(t, s.ContainsSlice([]string{"a", "b"}))
+
+	_, err = From[string](list{"a", 1}, HashString)
+	must.EqError(t, err, "schemaset: element 1 is of type int, not string")
+}
+
+func TestList(t *testing.T) {
+	items := List[string](set.From([]string{"a", "b", "c"}))
+	must.SliceLen(t, 3, items)
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.(string))
+	}
+	sort.Strings(result)
+	must.Eq(t, []string{"a", "b", "c"}, result)
+
+	// round trip through a HashSet keyed by the SDK hash function
+	s, err := From[string](list(items), HashString)
+	must.NoError(t, err)
+	must.True(t, s.EqualSlice([]string{"a", "b", "c"}))
+}
@@ -3,7 +3,15 @@
 
 package set
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
 
 // marshalJSON will serialize a Serializable[T] into a json byte array
 func marshalJSON[T any](s Collection[T]) ([]byte, error) {
@@ -20,3 +28,255 @@ func unmarshalJSON[T any](s Collection[T], data []byte) error {
 	s.InsertSlice(slice)
 	return nil
 }
+
+// errNoConstructor creates the error returned when unmarshaling into the zero
+// value of a set that requires a constructor.
+func errNoConstructor(kind string) error {
+	return fmt.Errorf("set: cannot unmarshal into zero value %s, which must be created by its constructor", kind)
+}
+
+// UnmarshalJSONSets deserializes a JSON array of arrays, such as produced by
+// marshaling a []*TreeSet[T], into a slice of sets each created by create.
+//
+// Unlike Set, the zero values of HashSet and the ordered sets cannot be used
+// until their hash or compare function is provided, and so cannot be decoded
+// directly by json.Unmarshal when nested in a slice, e.g.
+//
+//	sets, err := set.UnmarshalJSONSets(data, func() *set.TreeSet[int] {
+//	  return set.NewTreeSet(cmp.Compare[int])
+//	})
+//
+// A null element of data produces an empty set.
+func UnmarshalJSONSets[T any, C Collection[T]](data []byte, create func() C) ([]C, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	result := make([]C, len(raw))
+	for i, element := range raw {
+		col := create()
+		if err := json.Unmarshal(element, col); err != nil {
+			return nil, err
+		}
+		result[i] = col
+	}
+	return result, nil
+}
+
+// UnmarshalJSONSetMap deserializes a JSON object whose values are arrays, such
+// as produced by marshaling a map[K]*TreeSet[T], into a map of sets each
+// created by create. See UnmarshalJSONSets.
+func UnmarshalJSONSetMap[K comparable, T any, C Collection[T]](data []byte, create func() C) (map[K]C, error) {
+	var raw map[K]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	result := make(map[K]C, len(raw))
+	for key, element := range raw {
+		col := create()
+		if err := json.Unmarshal(element, col); err != nil {
+			return nil, err
+		}
+		result[key] = col
+	}
+	return result, nil
+}
+
+// envelope is the JSON encoding of the elements of an ordered set along with a
+// tag identifying their order.
+type envelope[T any] struct {
+	Order    string `json:"order"`
+	Elements []T    `json:"elements"`
+}
+
+// isEnvelope returns whether data encodes a JSON object rather than an array.
+func isEnvelope(data []byte) bool {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	return len(data) > 0 && data[0] == '{'
+}
+
+// CanonicalJSON serializes the elements of col into a JSON array, in an order
+// that does not depend on the underlying implementation of col. Identical sets
+// always serialize to identical bytes, which is useful for content addressed
+// caching or for detecting changes by comparing serialized forms.
+//
+// Elements are ordered by their "%v" printf formatting, with ties broken by
+// their JSON encoding. Use CanonicalJSONFunc to provide a specific order.
+func CanonicalJSON[T any](col Collection[T]) ([]byte, error) {
+	return canonicalJSON(col, func(element T) string {
+		return fmt.Sprintf("%v", element)
+	}, nil)
+}
+
+// CanonicalJSONFunc serializes the elements of col into a JSON array, ordered
+// by compare. Elements which compare as equal are ordered by their JSON
+// encoding, so that the result is deterministic even if compare is not a
+// total order over the elements of col.
+func CanonicalJSONFunc[T any](col Collection[T], compare CompareFunc[T]) ([]byte, error) {
+	return canonicalJSON(col, nil, compare)
+}
+
+func canonicalJSON[T any](col Collection[T], format func(T) string, compare CompareFunc[T]) ([]byte, error) {
+	type encoded struct {
+		element T
+		key     string
+		data    []byte
+	}
+
+	elements := make([]encoded, 0, col.Size())
+	for item := range col.Items() {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		e := encoded{element: item, data: data}
+		if format != nil {
+			e.key = format(item)
+		}
+		elements = append(elements, e)
+	}
+
+	slices.SortFunc(elements, func(a, b encoded) int {
+		if compare != nil {
+			if c := compare(a.element, b.element); c != 0 {
+				return c
+			}
+		}
+		if c := strings.Compare(a.key, b.key); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.data, b.data)
+	})
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, e := range elements {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(e.data)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSONLimit deserializes a JSON array from data, inserting each
+// element into col, as with the UnmarshalJSON method of each set type. Unlike
+// UnmarshalJSON, the elements are decoded one at a time, and decoding stops
+// once more than limit elements have been decoded. As with UnmarshalJSON, a
+// null data leaves col unchanged, and data must contain nothing after the
+// array.
+//
+// This is useful for decoding sets from untrusted input, where the size of
+// data is not otherwise restricted.
+//
+// Returns an error wrapping ErrSizeLimit if data contains more than limit
+// elements, in which case col will contain the first limit elements of data.
+// A negative limit is rejected with an error wrapping ErrSizeLimit, leaving
+// col unchanged, rather than being treated as no limit.
+func UnmarshalJSONLimit[T any](col Collection[T], data []byte, limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("%w: negative limit %d", ErrSizeLimit, limit)
+	}
+	return decodeArray(data, func(index int, item T) error {
+		if index == limit {
+			return fmt.Errorf("%w: more than %d elements", ErrSizeLimit, limit)
+		}
+		col.Insert(item)
+		return nil
+	})
+}
+
+// JSONSchema is a JSON Schema fragment describing the JSON encoding of a set,
+// as created by NewJSONSchema, e.g. for documenting the request and response
+// bodies of an HTTP API in an OpenAPI specification.
+//
+// The fragment describes the array produced by the MarshalJSON method of each
+// set type, not the object produced by MarshalJSONTagged.
+type JSONSchema struct {
+	// Type is always "array".
+	Type string `json:"type"`
+
+	// Items is the JSON Schema of each element of the set, or nil if the
+	// elements are unconstrained.
+	Items json.RawMessage `json:"items,omitempty"`
+
+	// UniqueItems is always true, as a set contains each element once.
+	UniqueItems bool `json:"uniqueItems"`
+}
+
+// NewJSONSchema creates a JSONSchema describing a set whose elements are
+// described by the JSON Schema items, e.g.
+//
+//	schema := set.NewJSONSchema(json.RawMessage(`{"type": "string"}`))
+//	data, err := json.Marshal(schema)
+//	// {"type":"array","items":{"type":"string"},"uniqueItems":true}
+//
+// A nil items leaves the elements unconstrained. Marshaling the JSONSchema
+// fails if items is not valid JSON.
+func NewJSONSchema(items json.RawMessage) JSONSchema {
+	return JSONSchema{
+		Type:        "array",
+		Items:       items,
+		UniqueItems: true,
+	}
+}
+
+// UnmarshalJSONUnique deserializes a JSON array from data, inserting each
+// element into col, as with UnmarshalJSONLimit. Unlike UnmarshalJSON, which
+// silently ignores repeated elements, decoding stops at the first element of
+// data which is already in col, e.g. for rejecting a request body which
+// violates the uniqueItems constraint of a JSONSchema.
+//
+// Elements are considered to be the same according to col (i.e. by the hash
+// function of a HashSet, or the CompareFunc of a TreeSet), which may differ
+// from the equality of their JSON encodings. col should usually be empty, as
+// elements already in col are also rejected.
+//
+// Returns an error wrapping ErrDuplicate if data contains an element already
+// in col, in which case col will contain the elements of data preceding it.
+func UnmarshalJSONUnique[T any](col Collection[T], data []byte) error {
+	return decodeArray(data, func(index int, item T) error {
+		if !col.Insert(item) {
+			return fmt.Errorf("%w: %v at index %d", ErrDuplicate, item, index)
+		}
+		return nil
+	})
+}
+
+// decodeArray decodes a JSON array from data one element at a time, passing
+// each element and its index to insert, and stopping at the first error
+// returned by insert.
+//
+// As with json.Unmarshal, data may be null, which is treated as an empty
+// array, and data must not contain anything after the array.
+func decodeArray[T any](data []byte, insert func(index int, item T) error) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	token, err := dec.Token()
+	switch {
+	case err != nil:
+		return err
+	case token == json.Delim('['):
+		for index := 0; dec.More(); index++ {
+			var item T
+			if err := dec.Decode(&item); err != nil {
+				return err
+			}
+			if err := insert(index, item); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	case token != nil:
+		return fmt.Errorf("set: cannot unmarshal %v into set, expected array", token)
+	}
+
+	if token, err := dec.Token(); err == nil {
+		return fmt.Errorf("set: unexpected %v after array", token)
+	} else if !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
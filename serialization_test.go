@@ -6,6 +6,7 @@ package set
 import (
 	"cmp"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/shoenig/test/must"
@@ -57,3 +58,225 @@ func TestSerialization(t *testing.T) {
 		must.Eq(t, set.Slice(), dstSet.Slice())
 	})
 }
+
+func TestTreeSet_JSONTagged(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		s := TreeSetFrom([]int{10, 3, 13}, cmp.Compare[int])
+		bs, err := s.MarshalJSONTagged("numeric/v1")
+		must.NoError(t, err)
+		must.Eq(t, `{"order":"numeric/v1","elements":[3,10,13]}`, string(bs))
+
+		dst := NewTreeSet[int](cmp.Compare[int])
+		must.NoError(t, dst.UnmarshalJSONTagged(bs, "numeric/v1"))
+		must.Eq(t, []int{3, 10, 13}, dst.Slice())
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		bs := []byte(`{"order":"numeric/v2","elements":[3,10,13]}`)
+		dst := TreeSetFrom([]int{1}, cmp.Compare[int])
+		err := dst.UnmarshalJSONTagged(bs, "numeric/v1")
+		must.ErrorIs(t, err, ErrIncompatibleComparator)
+		must.Eq(t, []int{1}, dst.Slice())
+	})
+
+	t.Run("untagged", func(t *testing.T) {
+		dst := NewTreeSet[int](cmp.Compare[int])
+		err := dst.UnmarshalJSONTagged([]byte(`[3,10,13]`), "numeric/v1")
+		must.ErrorIs(t, err, ErrIncompatibleComparator)
+		must.Empty(t, dst)
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		bs := []byte(` {"order":"numeric/v2","elements":[3,10,13]}`)
+		dst := NewTreeSet[int](cmp.Compare[int])
+		must.NoError(t, json.Unmarshal(bs, dst))
+		must.Eq(t, []int{3, 10, 13}, dst.Slice())
+	})
+}
+
+func TestUnmarshalJSONSets(t *testing.T) {
+	create := func() *TreeSet[int] {
+		return NewTreeSet(cmp.Compare[int])
+	}
+
+	t.Run("slice", func(t *testing.T) {
+		sets := []*TreeSet[int]{TreeSetOf(cmp.Compare[int], 3, 1), create()}
+		bs, err := json.Marshal(sets)
+		must.NoError(t, err)
+
+		result, err := UnmarshalJSONSets(bs, create)
+		must.NoError(t, err)
+		must.SliceLen(t, 2, result)
+		must.Eq(t, []int{1, 3}, result[0].Slice())
+		must.Empty(t, result[1])
+	})
+
+	t.Run("map", func(t *testing.T) {
+		sets := map[string]*TreeSet[int]{"a": TreeSetOf(cmp.Compare[int], 2, 1)}
+		bs, err := json.Marshal(sets)
+		must.NoError(t, err)
+
+		result, err := UnmarshalJSONSetMap[string](bs, create)
+		must.NoError(t, err)
+		must.MapLen(t, 1, result)
+		must.Eq(t, []int{1, 2}, result["a"].Slice())
+	})
+
+	t.Run("null", func(t *testing.T) {
+		result, err := UnmarshalJSONSets([]byte(`[null,[1]]`), create)
+		must.NoError(t, err)
+		must.Empty(t, result[0])
+		must.Eq(t, []int{1}, result[1].Slice())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := UnmarshalJSONSets([]byte(`[["a"]]`), create)
+		must.Error(t, err)
+	})
+
+	t.Run("nested set", func(t *testing.T) {
+		// the zero value of Set is ready to use, so no helper is needed
+		var result map[string]*Set[int]
+		must.NoError(t, json.Unmarshal([]byte(`{"a":[1,2]}`), &result))
+		must.Eq(t, Of(1, 2), result["a"])
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var result map[string]*TreeSet[int]
+		err := json.Unmarshal([]byte(`{"a":[1,2]}`), &result)
+		must.ErrorContains(t, err, "zero value TreeSet")
+	})
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	t.Run("Set", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			bs, err := CanonicalJSON[string](From([]string{"red", "green", "blue"}))
+			must.NoError(t, err)
+			must.Eq(t, `["blue","green","red"]`, string(bs))
+		}
+	})
+
+	t.Run("HashSet", func(t *testing.T) {
+		set := HashSetFrom[*company, string]([]*company{c3, c1, c2})
+		bs, err := CanonicalJSONFunc[*company](set, func(a, b *company) int {
+			return cmp.Compare(b.floor, a.floor)
+		})
+		must.NoError(t, err)
+		must.Eq(t, `[{"street":3},{"street":2},{"street":1}]`, string(bs))
+	})
+
+	t.Run("ties", func(t *testing.T) {
+		// every element compares equal, leaving the JSON encoding to decide
+		set := From([]int{3, 1, 2})
+		bs, err := CanonicalJSONFunc[int](set, func(int, int) int { return 0 })
+		must.NoError(t, err)
+		must.Eq(t, `[1,2,3]`, string(bs))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		bs, err := CanonicalJSON[int](New[int](0))
+		must.NoError(t, err)
+		must.Eq(t, `[]`, string(bs))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := CanonicalJSON[chan int](From([]chan int{make(chan int)}))
+		must.Error(t, err)
+	})
+}
+
+func TestUnmarshalJSONLimit(t *testing.T) {
+	t.Run("within limit", func(t *testing.T) {
+		s := New[int](0)
+		must.NoError(t, UnmarshalJSONLimit[int](s, []byte(`[1, 2, 3, 2]`), 4))
+		must.Eq(t, From([]int{1, 2, 3}), s)
+	})
+
+	t.Run("exceeds limit", func(t *testing.T) {
+		s := NewTreeSet[int](cmp.Compare[int])
+		err := UnmarshalJSONLimit[int](s, []byte(`[5, 4, 3, 2, 1]`), 3)
+		must.ErrorIs(t, err, ErrSizeLimit)
+		must.EqError(t, err, "set: size limit exceeded: more than 3 elements")
+		must.Eq(t, []int{3, 4, 5}, s.Slice())
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		s := New[int](0)
+		err := UnmarshalJSONLimit[int](s, []byte(`[1, 2]`), -1)
+		must.ErrorIs(t, err, ErrSizeLimit)
+		must.EqError(t, err, "set: size limit exceeded: negative limit -1")
+		must.Empty(t, s)
+	})
+
+	t.Run("not an array", func(t *testing.T) {
+		s := New[int](0)
+		must.Error(t, UnmarshalJSONLimit[int](s, []byte(`{"a": 1}`), 3))
+		must.Error(t, UnmarshalJSONLimit[int](s, []byte(``), 3))
+		must.Error(t, UnmarshalJSONLimit[int](s, []byte(`["a"]`), 3))
+		must.Error(t, UnmarshalJSONLimit[int](s, []byte(`[1, 2`), 3))
+		must.Error(t, UnmarshalJSONLimit[int](s, []byte(`[1] garbage`), 3))
+		must.Error(t, UnmarshalJSONLimit[int](s, []byte(`[1][2]`), 3))
+		must.Error(t, UnmarshalJSONLimit[int](s, []byte(`null 1`), 3))
+	})
+
+	t.Run("null", func(t *testing.T) {
+		s := New[int](0)
+		must.NoError(t, UnmarshalJSONLimit[int](s, []byte(` null `), 3))
+		must.Empty(t, s)
+	})
+}
+
+func TestNewJSONSchema(t *testing.T) {
+	data, err := json.Marshal(NewJSONSchema(json.RawMessage(`{"type": "string"}`)))
+	must.NoError(t, err)
+	must.Eq(t, `{"type":"array","items":{"type":"string"},"uniqueItems":true}`, string(data))
+
+	data, err = json.Marshal(NewJSONSchema(nil))
+	must.NoError(t, err)
+	must.Eq(t, `{"type":"array","uniqueItems":true}`, string(data))
+
+	_, err = json.Marshal(NewJSONSchema(json.RawMessage(`{`)))
+	must.Error(t, err)
+}
+
+func TestUnmarshalJSONUnique(t *testing.T) {
+	t.Run("unique", func(t *testing.T) {
+		s := NewTreeSet[int](cmp.Compare[int])
+		must.NoError(t, UnmarshalJSONUnique[int](s, []byte(`[3, 1, 2]`)))
+		must.Eq(t, []int{1, 2, 3}, s.Slice())
+
+		must.NoError(t, UnmarshalJSONUnique[int](New[int](0), []byte(`[]`)))
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		s := New[string](0)
+		err := UnmarshalJSONUnique[string](s, []byte(`["a", "b", "a", "c"]`))
+		must.ErrorIs(t, err, ErrDuplicate)
+		must.EqError(t, err, "set: duplicate element: a at index 2")
+		must.Eq(t, From([]string{"a", "b"}), s)
+	})
+
+	t.Run("duplicate by hash", func(t *testing.T) {
+		s := NewHashSetFunc[string, string](0, func(s string) string { return s }).WithKeyNormalizer(strings.ToLower)
+		err := UnmarshalJSONUnique[string](s, []byte(`["a", "A"]`))
+		must.ErrorIs(t, err, ErrDuplicate)
+	})
+
+	t.Run("not an array", func(t *testing.T) {
+		s := New[int](0)
+		must.Error(t, UnmarshalJSONUnique[int](s, []byte(`{"a": 1}`)))
+		must.Error(t, UnmarshalJSONUnique[int](s, []byte(``)))
+		must.Error(t, UnmarshalJSONUnique[int](s, []byte(`["a"]`)))
+		must.Error(t, UnmarshalJSONUnique[int](s, []byte(`[1, 2`)))
+		must.Error(t, UnmarshalJSONUnique[int](s, []byte(`[1] garbage`)))
+		must.Error(t, UnmarshalJSONUnique[int](s, []byte(`[1][2]`)))
+		must.Error(t, UnmarshalJSONUnique[int](s, []byte(`null 1`)))
+	})
+
+	t.Run("null", func(t *testing.T) {
+		s := New[int](0)
+		must.NoError(t, UnmarshalJSONUnique[int](s, []byte(` null `)))
+		must.Empty(t, s)
+	})
+}
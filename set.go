@@ -4,11 +4,66 @@
 // Package set provides a basic generic set implementation.
 //
 // https://en.wikipedia.org/wiki/Set_(mathematics)
+//
+// # Concurrency
+//
+// Other than SkipSet and ReadMostlySet, none of the set implementations are
+// safe for concurrent modification. Any number of goroutines may read from a
+// set concurrently, so long as no goroutine is modifying the set at the same
+// time. Callers sharing a set between goroutines that modify it must provide
+// their own synchronization, e.g. by guarding the set with a sync.RWMutex.
+//
+// Of the sets which are safe for concurrent use, ReadMostlySet suits sets
+// which are read far more often than they are modified, as its reads never
+// contend with one another, but each modification copies the whole set.
+// SkipSet suits sets which are modified often, as modifications of unrelated
+// elements proceed in parallel. BenchmarkContention compares the two (and a
+// Set guarded by a sync.RWMutex) at increasing numbers of goroutines.
+//
+// Removing elements from a Set or HashSet while iterating via Items is safe,
+// as with the builtin map. Modifying a TreeSet while iterating via Items is
+// not. Building with -tags setdebug enables runtime checks which panic when a
+// TreeSet is modified during iteration, so that such misuse is caught during
+// development.
+//
+// # Iteration
+//
+// Items is the supported way of iterating the elements of any set, and
+// produces the elements of TreeSet, CompactTreeSet, and SkipSet in ascending
+// order. Breaking out of a range loop over Items ends the iteration, so an
+// ordered walk may be consumed partially, e.g. to find the first few elements
+// satisfying some condition.
+//
+// For a walk consumed across several calls rather than within one loop, use
+// iter.Pull to convert Items into a cursor producing one element per call.
+// The stop function returned by iter.Pull must be called once the cursor is no
+// longer needed, and the set must not be modified (as with Items) until then.
+//
+//	next, stop := iter.Pull(s.Items())
+//	defer stop()
+//
+// TreeSet.StreamOrdered produces elements over a channel instead, for a
+// consumer in another goroutine, and is built on Items.
+//
+// # Capacity
+//
+// The size parameter of New, NewHashSet, and similar constructors is the
+// number of elements the set is expected to contain. The underlying map is
+// allocated so that it holds that many elements without growing; Go maps do
+// not expose a load factor, so no further tuning is possible or necessary.
+// Overestimating size wastes memory, and underestimating it costs one or more
+// rehashes as the set grows, so an approximate count is better than none.
+//
+// TreeSet and its variants allocate a node per element. TreeSet.Grow
+// preallocates the nodes for a known number of elements as a single slab.
 package set
 
 import (
+	"cmp"
+	"context"
 	"fmt"
 	"iter"
+	"slices"
 	"sort"
 )
 
@@ -41,6 +96,15 @@ func From[T comparable](items []T) *Set[T] {
 	return s
 }
 
+// Of creates a new Set containing each of items.
+//
+// Of is a more concise alternative to From for a fixed list of elements, e.g.
+//
+//	s := set.Of("apple", "banana", "cherry")
+func Of[T comparable](items ...T) *Set[T] {
+	return From(items)
+}
+
 // FromFunc creates a new Set containing a conversion of each item in items.
 //
 // T may be any comparable type. Keep in mind that pointer types or structs
@@ -54,11 +118,47 @@ func FromFunc[A any, T comparable](items []A, conversion func(A) T) *Set[T] {
 	return s
 }
 
+// FromFuncReport creates a new Set containing a conversion of each item in
+// items, as with FromFunc, and also reports which items were collapsed into
+// the same element by conversion.
+//
+// The returned map contains an entry for each element of the Set which was
+// produced by more than one item, listing each of those items in their order
+// in items. Elements produced by exactly one item are not included, such that
+// an empty map means no items were deduplicated.
+func FromFuncReport[A any, T comparable](items []A, conversion func(A) T) (*Set[T], map[T][]A) {
+	// the index of the first item converted into each element
+	first := make(map[T]int, len(items))
+	collisions := make(map[T][]A)
+	for i, item := range items {
+		element := conversion(item)
+		j, exists := first[element]
+		switch {
+		case !exists:
+			first[element] = i
+		case len(collisions[element]) == 0:
+			collisions[element] = []A{items[j], item}
+		default:
+			collisions[element] = append(collisions[element], item)
+		}
+	}
+
+	s := New[T](len(first))
+	for element := range first {
+		s.items[element] = sentinel
+	}
+	return s, collisions
+}
+
 // Set is a simple, generic implementation of the set mathematical data structure.
 // It is optimized for correctness and convenience, as a replacement for the use
 // of map[interface{}]struct{}.
+//
+// Not thread safe, and not safe for concurrent modification. Multiple goroutines
+// may read from a Set concurrently as long as no goroutine is modifying it.
 type Set[T comparable] struct {
-	items map[T]nothing
+	items   map[T]nothing
+	version uint64 // incremented on each modification, see SortedView
 }
 
 // Insert item into s.
@@ -72,6 +172,7 @@ func (s *Set[T]) Insert(item T) bool {
 		s.items = make(map[T]nothing)
 	}
 	s.items[item] = sentinel
+	s.version++
 	return true
 }
 
@@ -109,6 +210,7 @@ func (s *Set[T]) Remove(item T) bool {
 		return false
 	}
 	delete(s.items, item)
+	s.version++
 	return true
 }
 
@@ -139,17 +241,54 @@ func (s *Set[T]) RemoveFunc(f func(T) bool) bool {
 	return removeFunc(s, f)
 }
 
+// RemoveFuncN will remove up to n elements from s that satisfy condition f,
+// e.g. for bounding the work done by each pass of a periodic purge of s.
+//
+// Returns the number of elements removed.
+func (s *Set[T]) RemoveFuncN(f func(T) bool, n int) int {
+	return removeFuncN(s, f, n)
+}
+
 // Contains returns whether item is present in s.
 func (s *Set[T]) Contains(item T) bool {
 	_, exists := s.items[item]
 	return exists
 }
 
+// ContainsBytes returns whether the string form of b is present in s, e.g. for
+// probing s with a token parsed from a network buffer.
+//
+// Unlike s.Contains(string(b)), ContainsBytes does not allocate a string.
+func ContainsBytes(s *Set[string], b []byte) bool {
+	// the compiler elides the allocation of string(b) in a map index expression
+	_, exists := s.items[string(b)]
+	return exists
+}
+
 // ContainsSlice returns whether all elements in items are present in s.
 func (s *Set[T]) ContainsSlice(items []T) bool {
 	return containsSlice(s, items)
 }
 
+// MissingFrom returns the elements of items which are not present in s, e.g. for
+// reporting which of a list of requested identifiers do not exist.
+//
+// The result preserves the order of items, including any duplicates.
+func (s *Set[T]) MissingFrom(items []T) []T {
+	return missingFrom(s, items)
+}
+
+// ClassifySlice partitions items into those present in s, those absent from
+// s, and duplicates (i.e. each repeated occurrence of an item earlier in
+// items), in a single pass, e.g. for reporting which of a list of requested
+// identifiers are already known, which are new, and which were repeated.
+//
+// Each result preserves the order of items. The first occurrence of an item
+// is in either present or absent, and each later occurrence is in duplicates.
+func (s *Set[T]) ClassifySlice(items []T) (present, absent, duplicates []T) {
+	return classifySlice(s, New[T](len(items)), items)
+}
+
 // Subset returns whether col is a subset of s.
 func (s *Set[T]) Subset(col Collection[T]) bool {
 	return subset(s, col)
@@ -163,6 +302,14 @@ func (s *Set[T]) ProperSubset(col Collection[T]) bool {
 	return s.Subset(col)
 }
 
+// Clear removes every element from s.
+//
+// The underlying capacity of s is retained for reuse.
+func (s *Set[T]) Clear() {
+	clear(s.items)
+	s.version++
+}
+
 // Size returns the cardinality of s.
 func (s *Set[T]) Size() int {
 	return len(s.items)
@@ -200,6 +347,59 @@ func (s *Set[T]) Intersect(col Collection[T]) Collection[T] {
 	return result
 }
 
+// UnionCtx returns a set that contains all elements of s and col combined, as
+// with Union, while periodically checking whether ctx has been canceled.
+//
+// If ctx is canceled before the union is complete, the partial result is
+// returned along with an error wrapping ErrPartialResult and the error of ctx.
+func (s *Set[T]) UnionCtx(ctx context.Context, col Collection[T]) (Collection[T], error) {
+	result := New[T](max(s.Size(), col.Size()))
+	err := insertCtx(ctx, result, s, col)
+	return result, err
+}
+
+// DifferenceCtx returns a set that contains elements of s that are not in col,
+// as with Difference, while periodically checking whether ctx has been
+// canceled.
+//
+// If ctx is canceled before the difference is complete, the partial result is
+// returned along with an error wrapping ErrPartialResult and the error of ctx.
+func (s *Set[T]) DifferenceCtx(ctx context.Context, col Collection[T]) (Collection[T], error) {
+	result := New[T](max(0, s.Size()-col.Size()))
+	err := differenceCtx(ctx, result, s, col)
+	return result, err
+}
+
+// IntersectCtx returns a set that contains elements that are present in both s
+// and col, as with Intersect, while periodically checking whether ctx has been
+// canceled.
+//
+// If ctx is canceled before the intersection is complete, the partial result
+// is returned along with an error wrapping ErrPartialResult and the error of ctx.
+func (s *Set[T]) IntersectCtx(ctx context.Context, col Collection[T]) (Collection[T], error) {
+	result := New[T](0)
+	err := intersectCtx(ctx, result, s, col)
+	return result, err
+}
+
+// UnionFunc returns a set that contains the elements of s and col combined
+// which satisfy predicate, as with Union followed by filtering the result,
+// but in a single pass without creating the unfiltered union.
+func (s *Set[T]) UnionFunc(col Collection[T], predicate func(T) bool) Collection[T] {
+	result := New[T](0)
+	insertFunc(result, predicate, s, col)
+	return result
+}
+
+// IntersectFunc returns a set that contains the elements present in both s
+// and col which satisfy predicate, as with Intersect followed by filtering the
+// result, but in a single pass without creating the unfiltered intersection.
+func (s *Set[T]) IntersectFunc(col Collection[T], predicate func(T) bool) Collection[T] {
+	result := New[T](0)
+	intersectFunc(result, s, col, predicate)
+	return result
+}
+
 // Copy creates a copy of s.
 func (s *Set[T]) Copy() *Set[T] {
 	result := New[T](s.Size())
@@ -209,12 +409,82 @@ func (s *Set[T]) Copy() *Set[T] {
 	return result
 }
 
-// Slice creates a copy of s as a slice. Elements are in no particular order.
+// CopyFunc creates a deep copy of s, where each element of the copy is created
+// by passing an element of s through clone.
+//
+// If clone produces the same element for two elements of s, the copy contains
+// fewer elements than s.
+func (s *Set[T]) CopyFunc(clone func(T) T) *Set[T] {
+	result := New[T](s.Size())
+	for item := range s.items {
+		result.items[clone(item)] = sentinel
+	}
+	return result
+}
+
+// SplitN partitions the elements of s into k sets of roughly equal size, e.g.
+// for distributing work items across k workers. The sizes of the resulting
+// sets differ by at most one.
+//
+// The partition is deterministic, such that sets with the same elements are
+// always split in the same way. As with IterOrderSeed, elements are assigned
+// based on their "%#v" printf formatting.
+//
+// Panics if k is less than 1.
+func (s *Set[T]) SplitN(k int) []*Set[T] {
+	checkSplitN(k)
+	parts := make([]*Set[T], k)
+	for i := range parts {
+		parts[i] = New[T](s.Size()/k + 1)
+	}
+	items := seededOrder(s.Slice(), func(item T) string {
+		return fmt.Sprintf("%#v", item)
+	}, 0)
+	for i, item := range items {
+		parts[i%k].items[item] = sentinel
+	}
+	return parts
+}
+
+// Slice creates a copy of s as a slice. Elements are in no particular order;
+// use SliceStable or SortedSlice for a consistently ordered result.
 func (s *Set[T]) Slice() []T {
-	result := make([]T, 0, s.Size())
+	return s.AppendSlice(make([]T, 0, s.Size()))
+}
+
+// AppendSlice appends the elements of s to dst and returns the extended slice,
+// e.g. for reusing the same buffer across calls rather than allocating a new
+// slice via Slice each time.
+//
+// Elements are in no particular order.
+func (s *Set[T]) AppendSlice(dst []T) []T {
+	dst = slices.Grow(dst, s.Size())
 	for item := range s.items {
-		result = append(result, item)
+		dst = append(dst, item)
 	}
+	return dst
+}
+
+// SliceStable creates a copy of s as a slice, sorted according to less.
+//
+// Unlike sorting the result of Slice, the order of the result is guaranteed to
+// be the same across calls even if less does not define a total order over the
+// elements of s. Elements which are equal according to less are ordered by
+// their "%v" printf formatting.
+//
+// Use SortedSlice for elements of a cmp.Ordered type.
+func (s *Set[T]) SliceStable(less func(a, b T) bool) []T {
+	result := s.Slice()
+	slices.SortFunc(result, func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		default:
+			return cmp.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+		}
+	})
 	return result
 }
 
@@ -251,6 +521,17 @@ func (s *Set[T]) Equal(o *Set[T]) bool {
 	return true
 }
 
+// ContentHash returns a hash of the elements of s which does not depend on
+// their order, combining the hash of each element as produced by hash, e.g.
+// for cheaply comparing sets of different types or in different processes.
+//
+// Sets containing the same elements have the same content hash regardless of
+// their type or internal layout. Sets containing different elements are
+// unlikely, but not guaranteed, to have different content hashes.
+func (s *Set[T]) ContentHash(hash func(T) uint64) uint64 {
+	return contentHash(s, hash)
+}
+
 // EqualSet returns whether s and col contain the same elements.
 func (s *Set[T]) EqualSet(col Collection[T]) bool {
 	return equalSet(s, col)
@@ -269,26 +550,20 @@ func (s *Set[T]) EqualSlice(items []T) bool {
 	return s.Equal(other)
 }
 
-// EqualSliceSet returns whether s and items contain exactly the same elements.
-//
-// If items contains duplicates EqualSliceSet will return false. The elements of
-// items are assumed to be set-like. For comparing s to a slice that may contain
-// duplicate elements, use EqualSlice instead.
+// EqualSliceSet returns whether s and items contain exactly the same elements,
+// where the elements of items are expected to be set-like. If items contains
+// duplicates EqualSliceSet returns false. For comparing s to a slice that may
+// contain duplicate elements, use EqualSlice instead.
 //
 // To detect if a slice is a subset of s, use ContainsSlice.
 func (s *Set[T]) EqualSliceSet(items []T) bool {
-	if len(items) != s.Size() {
-		return false
-	}
-	for _, item := range items {
-		if !s.Contains(item) {
-			return false
-		}
-	}
-	return true
+	return equalSliceSet(s, New[T](len(items)), items)
 }
 
 // MarshalJSON implements the json.Marshaler interface.
+//
+// Elements are encoded in no particular order. Use CanonicalJSON for an
+// encoding that is identical for identical sets.
 func (s *Set[T]) MarshalJSON() ([]byte, error) {
 	return marshalJSON[T](s)
 }
@@ -301,6 +576,10 @@ func (s *Set[T]) UnmarshalJSON(data []byte) error {
 // Items returns a generator function for iterating each element in s by using
 // the range keyword.
 //
+// Elements of s may be removed during iteration, and will not be produced if
+// not yet reached. Elements inserted during iteration may or may not be
+// produced. Use Snapshot to iterate a fixed view of s instead.
+//
 //	for element := range s.Items() { ... }
 func (s *Set[T]) Items() iter.Seq[T] {
 	return func(yield func(T) bool) {
@@ -311,3 +590,29 @@ func (s *Set[T]) Items() iter.Seq[T] {
 		}
 	}
 }
+
+// IterOrderSeed returns a generator function for iterating each element in s
+// by using the range keyword, in a pseudo-random order determined by seed.
+//
+// Unlike Items, the order is the same each time s is iterated with the same
+// seed, for as long as s contains the same elements. This is useful for
+// reproducing test failures, or for round-robin selection that should not
+// always favor the same elements. The order is derived from the "%#v" printf
+// formatting of each element, and so is not reproducible across processes for
+// elements containing pointers.
+//
+// The elements of s are copied when iteration begins, and s may be modified
+// during iteration without affecting the elements produced.
+//
+//	for element := range s.IterOrderSeed(42) { ... }
+func (s *Set[T]) IterOrderSeed(seed int64) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range seededOrder(s.Slice(), func(item T) string {
+			return fmt.Sprintf("%#v", item)
+		}, seed) {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
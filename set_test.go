@@ -51,6 +51,19 @@ func TestSet_From(t *testing.T) {
 	})
 }
 
+func TestSet_Of(t *testing.T) {
+	t.Run("of none", func(t *testing.T) {
+		s := Of[string]()
+		must.MapEmpty(t, s.items)
+	})
+
+	t.Run("of some", func(t *testing.T) {
+		s := Of("apple", "banana", "cherry", "apple")
+		must.MapContainsKeys(t, s.items, []string{"apple", "banana", "cherry"})
+		must.Size(t, 3, s)
+	})
+}
+
 func TestSet_FromFunc(t *testing.T) {
 	employees := []employee{
 		{"alice", 1}, {"bob", 2}, {"bob", 2}, {"carol", 3}, {"dave", 4},
@@ -61,6 +74,24 @@ func TestSet_FromFunc(t *testing.T) {
 	must.MapContainsKeys(t, s.items, []string{"alice", "bob", "carol", "dave"})
 }
 
+func TestSet_FromFuncReport(t *testing.T) {
+	employees := []employee{
+		{"alice", 1}, {"bob", 2}, {"bob", 3}, {"carol", 4}, {"bob", 5}, {"dave", 6}, {"carol", 7},
+	}
+	s, collisions := FromFuncReport(employees, func(e employee) string {
+		return e.name
+	})
+	must.Eq(t, From([]string{"alice", "bob", "carol", "dave"}), s)
+	must.Eq(t, map[string][]employee{
+		"bob":   {{"bob", 2}, {"bob", 3}, {"bob", 5}},
+		"carol": {{"carol", 4}, {"carol", 7}},
+	}, collisions)
+
+	ids, none := FromFuncReport([]int{1, 2, 3}, func(i int) int { return i })
+	must.Size(t, 3, ids)
+	must.MapEmpty(t, none)
+}
+
 func TestSet_Insert(t *testing.T) {
 	t.Run("one int", func(t *testing.T) {
 		s := New[int](10)
@@ -164,6 +195,21 @@ func TestSet_Contains(t *testing.T) {
 	})
 }
 
+func TestContainsBytes(t *testing.T) {
+	s := Of("apple", "banana")
+	must.True(t, ContainsBytes(s, []byte("apple")))
+	must.False(t, ContainsBytes(s, []byte("app")))
+	must.False(t, ContainsBytes(s, nil))
+
+	buf := []byte("banana split")
+	allocs := testing.AllocsPerRun(100, func() {
+		if !ContainsBytes(s, buf[:6]) {
+			t.Fatal("expected banana")
+		}
+	})
+	must.Zero(t, allocs)
+}
+
 func TestSet_ContainsSlice(t *testing.T) {
 	t.Run("empty empty", func(t *testing.T) {
 		a := New[int](0)
@@ -442,6 +488,19 @@ func TestSet_RemoveFunc(t *testing.T) {
 	})
 }
 
+func TestSet_RemoveFuncN(t *testing.T) {
+	even := func(i int) bool { return i%2 == 0 }
+
+	a := From(ints(10))
+	must.Eq(t, 3, a.RemoveFuncN(even, 3))
+	must.Size(t, 7, a)
+	must.Eq(t, 2, a.RemoveFuncN(even, 5))
+	must.Eq(t, []int{1, 3, 5, 7, 9}, SortedSlice[int](a))
+	must.Zero(t, a.RemoveFuncN(even, 5))
+	must.Zero(t, a.RemoveFuncN(func(int) bool { return true }, 0))
+	must.Size(t, 5, a)
+}
+
 func TestSet_Copy(t *testing.T) {
 	t.Run("copy empty", func(t *testing.T) {
 		a := New[int](0)
@@ -462,6 +521,25 @@ func TestSet_Copy(t *testing.T) {
 	})
 }
 
+func TestSet_CopyFunc(t *testing.T) {
+	t.Run("deep copy", func(t *testing.T) {
+		a := From([]*token{tokenA, tokenB})
+		b := a.CopyFunc(func(t *token) *token {
+			return &token{id: t.id}
+		})
+		must.Size(t, 2, b)
+		must.False(t, b.Contains(tokenA))
+		must.Eq(t, []string{"A", "B"}, SortedSlice[string](FromFunc(b.Slice(), (*token).String)))
+	})
+
+	t.Run("collapse", func(t *testing.T) {
+		a := From([]int{1, 2, 3, 4})
+		b := a.CopyFunc(func(i int) int { return i / 2 })
+		must.Eq(t, From([]int{0, 1, 2}), b)
+		must.Size(t, 4, a)
+	})
+}
+
 func TestSet_Slice(t *testing.T) {
 	t.Run("slice empty", func(t *testing.T) {
 		a := New[string](10)
@@ -479,6 +557,38 @@ func TestSet_Slice(t *testing.T) {
 	})
 }
 
+func TestSet_AppendSlice(t *testing.T) {
+	a := From([]int{1, 2, 3})
+	buf := []int{0}
+	buf = a.AppendSlice(buf)
+	must.Eq(t, 0, buf[0])
+	must.SliceContainsAll(t, []int{0, 1, 2, 3}, buf)
+
+	// the buffer is reused without allocating
+	allocs := testing.AllocsPerRun(10, func() {
+		buf = a.AppendSlice(buf[:0])
+	})
+	must.Zero(t, allocs)
+	must.SliceContainsAll(t, []int{1, 2, 3}, buf)
+}
+
+func TestSet_SliceStable(t *testing.T) {
+	t.Run("total order", func(t *testing.T) {
+		a := From([]string{"cherry", "apple", "banana"})
+		l := a.SliceStable(func(a, b string) bool { return a < b })
+		must.Eq(t, []string{"apple", "banana", "cherry"}, l)
+	})
+
+	t.Run("partial order", func(t *testing.T) {
+		a := From([]string{"bb", "a", "cc", "d", "aa"})
+		byLen := func(a, b string) bool { return len(a) < len(b) }
+		for range 10 {
+			l := a.SliceStable(byLen)
+			must.Eq(t, []string{"a", "d", "aa", "bb", "cc"}, l)
+		}
+	})
+}
+
 func TestSet_String(t *testing.T) {
 	t.Run("ints", func(t *testing.T) {
 		a := From([]int{1, 2, 3})
@@ -759,3 +869,33 @@ func TestSet_Items(t *testing.T) {
 
 	must.Eq(t, 15, sum)
 }
+
+func TestSet_SplitN(t *testing.T) {
+	s := From(ints(10))
+
+	parts := s.SplitN(3)
+	must.SliceLen(t, 3, parts)
+	union := New[int](0)
+	for _, part := range parts {
+		must.Between(t, 3, part.Size(), 4)
+		must.False(t, union.ContainsSlice(part.Slice()))
+		union.InsertSet(part)
+	}
+	must.Eq(t, s, union)
+
+	// deterministic for the same elements
+	again := From(shuffle(ints(10))).SplitN(3)
+	for i := range parts {
+		must.Eq(t, parts[i], again[i])
+	}
+
+	parts = From([]int{1}).SplitN(2)
+	must.Size(t, 1, parts[0])
+	must.Empty(t, parts[1])
+
+	for _, k := range []int{0, -1} {
+		msg := fmt.Sprintf("set: SplitN into %d sets, must be at least 1", k)
+		must.Eq[any](t, msg, recovered(func() { s.SplitN(k) }))
+		must.Eq[any](t, msg, recovered(func() { New[int](0).SplitN(k) }))
+	}
+}
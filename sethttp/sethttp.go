@@ -0,0 +1,202 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package sethttp provides an http.Handler for inspecting the sets of a
+// running server, e.g. for debugging a long-lived set whose contents have
+// drifted from what was expected.
+//
+// Unlike expvar, nothing is published implicitly; a set is rendered only once
+// it has been explicitly registered with a Handler, so that the contents of
+// sets are not exposed by accident.
+//
+//	h := sethttp.NewHandler()
+//	sethttp.Register(h, "peers", peers)
+//	mux.Handle("/debug/sets", h)
+//
+// The Handler renders JSON by default, or HTML if the request has the query
+// parameter format=html. The query parameter name restricts the response to a
+// single set, and additionally includes the shape of the underlying tree of a
+// TreeSet.
+package sethttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-set/v3"
+)
+
+// Handler is an http.Handler which renders each set registered with it.
+//
+// A Handler is safe for concurrent use by multiple goroutines.
+type Handler struct {
+	lock    sync.Mutex
+	sets    map[string]func(detail bool) entry
+	samples int
+}
+
+// NewHandler creates a Handler with no registered sets, which renders up to
+// 10 sample elements of each set.
+func NewHandler() *Handler {
+	return &Handler{
+		sets:    make(map[string]func(bool) entry),
+		samples: 10,
+	}
+}
+
+// WithSamples configures h to render up to n sample elements of each set.
+//
+// Returns h.
+func (h *Handler) WithSamples(n int) *Handler {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.samples = max(0, n)
+	return h
+}
+
+// Register adds col to the sets rendered by h under name, replacing any set
+// previously registered under name.
+//
+// The elements of col are read by the goroutine serving each request, and so
+// col must be safe for concurrent reads while it is modified, e.g. a SkipSet or
+// ReadMostlySet. Use RegisterLocked for a set guarded by a lock.
+func Register[T any](h *Handler, name string, col set.Collection[T]) {
+	RegisterLocked(h, name, col, nil)
+}
+
+// RegisterLocked adds col to the sets rendered by h under name, as with
+// Register, where lock is held while col is read, e.g. the read lock of the
+// sync.RWMutex guarding col.
+//
+//	sethttp.RegisterLocked(h, "peers", peers, lock.RLocker())
+func RegisterLocked[T any](h *Handler, name string, col set.Collection[T], lock sync.Locker) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.sets[name] = func(detail bool) entry {
+		if lock != nil {
+			lock.Lock()
+			defer lock.Unlock()
+		}
+		return render(name, col, h.sampleSize(), detail)
+	}
+}
+
+// Unregister removes the set registered under name from h, if any.
+func (h *Handler) Unregister(name string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	delete(h.sets, name)
+}
+
+func (h *Handler) sampleSize() int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.samples
+}
+
+// entry is the rendering of one registered set.
+type entry struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Size   int               `json:"size"`
+	Sample []string          `json:"sample"`
+	Stats  *set.TreeSetStats `json:"stats,omitempty"`
+	Tree   []string          `json:"tree,omitempty"`
+}
+
+// render describes col, including the shape of its tree if detail is set and
+// col is a TreeSet.
+func render[T any](name string, col set.Collection[T], samples int, detail bool) entry {
+	e := entry{
+		Name:   name,
+		Type:   fmt.Sprintf("%T", col),
+		Size:   col.Size(),
+		Sample: make([]string, 0, min(samples, col.Size())),
+	}
+	for item := range col.Items() {
+		if len(e.Sample) == samples {
+			break
+		}
+		e.Sample = append(e.Sample, fmt.Sprintf("%v", item))
+	}
+
+	if tree, ok := col.(*set.TreeSet[T]); ok {
+		stats := tree.Stats()
+		e.Stats = &stats
+		if detail {
+			e.Tree = make([]string, 0, tree.Size())
+			tree.WalkPreOrder(func(element T, depth int) bool {
+				e.Tree = append(e.Tree, fmt.Sprintf("%s%v", strings.Repeat("  ", depth-1), element))
+				return true
+			})
+		}
+	}
+	return e
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+
+	h.lock.Lock()
+	var renderers []func(bool) entry
+	switch fn, exists := h.sets[name]; {
+	case name == "":
+		names := make([]string, 0, len(h.sets))
+		for name := range h.sets {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			renderers = append(renderers, h.sets[name])
+		}
+	case exists:
+		renderers = append(renderers, fn)
+	}
+	h.lock.Unlock()
+
+	if name != "" && len(renderers) == 0 {
+		http.Error(w, fmt.Sprintf("no set registered as %q", name), http.StatusNotFound)
+		return
+	}
+
+	entries := make([]entry, 0, len(renderers))
+	for _, fn := range renderers {
+		entries = append(entries, fn(name != ""))
+	}
+
+	if r.URL.Query().Get("format") == "html" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = page.Execute(w, entries)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string][]entry{"sets": entries})
+}
+
+var page = template.Must(template.New("sets").Parse(`<!DOCTYPE html>
+<html>
+<head><title>sets</title></head>
+<body>
+{{range .}}
+<h2><a href="?format=html&amp;name={{.Name}}">{{.Name}}</a></h2>
+<p>{{.Type}}, {{.Size}} elements</p>
+<p>sample: {{range $i, $e := .Sample}}{{if $i}}, {{end}}{{$e}}{{end}}</p>
+{{with .Stats}}<p>rotations: {{.Rotations}}, recolorings: {{.Recolorings}}, max depth: {{.MaxDepth}}</p>{{end}}
+{{with .Tree}}<pre>{{range .}}{{.}}
+{{end}}</pre>{{end}}
+{{else}}
+<p>no sets registered</p>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package sethttp
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-set/v3"
+	"github.com/shoenig/test/must"
+)
+
+func get(t *testing.T, h http.Handler, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) []entry {
+	t.Helper()
+	must.Eq(t, http.StatusOK, w.Code)
+	must.Eq(t, "application/json", w.Header().Get("Content-Type"))
+	var result map[string][]entry
+	must.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	return result["sets"]
+}
+
+func TestHandler_empty(t *testing.T) {
+	h := NewHandler()
+	must.SliceEmpty(t, decode(t, get(t, h, "/")))
+}
+
+func TestHandler_list(t *testing.T) {
+	h := NewHandler().WithSamples(2)
+	Register[string](h, "names", set.From([]string{"a"}))
+	Register[int](h, "numbers", set.TreeSetFrom([]int{3, 1, 2}, cmp.Compare[int]))
+
+	entries := decode(t, get(t, h, "/"))
+	must.Len(t, 2, entries)
+
+	must.Eq(t, "names", entries[0].Name)
+	must.Eq(t, "*set.Set[string]", entries[0].Type)
+	must.Eq(t, 1, entries[0].Size)
+	must.Eq(t, []string{"a"}, entries[0].Sample)
+	must.Nil(t, entries[0].Stats)
+
+	must.Eq(t, "numbers", entries[1].Name)
+	must.Eq(t, 3, entries[1].Size)
+	must.Eq(t, []string{"1", "2"}, entries[1].Sample)
+	must.NotNil(t, entries[1].Stats)
+	must.SliceEmpty(t, entries[1].Tree) // only rendered for a single set
+}
+
+func TestHandler_name(t *testing.T) {
+	h := NewHandler()
+	Register[int](h, "numbers", set.TreeSetFrom([]int{1, 2, 3}, cmp.Compare[int]))
+
+	entries := decode(t, get(t, h, "/?name=numbers"))
+	must.Len(t, 1, entries)
+	must.Eq(t, []string{"2", "  1", "  3"}, entries[0].Tree)
+
+	w := get(t, h, "/?name=missing")
+	must.Eq(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandler_unregister(t *testing.T) {
+	h := NewHandler()
+	Register[string](h, "names", set.From([]string{"a"}))
+	h.Unregister("names")
+	must.SliceEmpty(t, decode(t, get(t, h, "/")))
+}
+
+func TestHandler_locked(t *testing.T) {
+	var lock sync.RWMutex
+	s := set.From([]int{1})
+
+	h := NewHandler()
+	RegisterLocked[int](h, "numbers", s, lock.RLocker())
+
+	lock.Lock()
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		done <- w
+	}()
+	s.Insert(2)
+	lock.Unlock()
+
+	entries := decode(t, <-done)
+	must.Eq(t, 2, entries[0].Size)
+}
+
+func TestHandler_html(t *testing.T) {
+	h := NewHandler()
+	Register[string](h, "<names>", set.From([]string{"<script>"}))
+
+	w := get(t, h, "/?format=html")
+	must.Eq(t, http.StatusOK, w.Code)
+	must.StrContains(t, w.Body.String(), "&lt;names&gt;")
+	must.StrContains(t, w.Body.String(), "&lt;script&gt;")
+	must.StrNotContains(t, w.Body.String(), "<script>")
+}
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import "iter"
+
+// SetMap is a map of keys of type K to sets of values of type V, i.e. a
+// multimap. Sets are created as values are inserted for a key, and removed
+// once the last value for a key is removed, such that every key of a SetMap
+// is associated with a non-empty set.
+//
+// Not thread safe, and not safe for concurrent modification.
+type SetMap[K, V comparable] struct {
+	sets map[K]*Set[V]
+}
+
+// NewSetMap creates an empty SetMap.
+func NewSetMap[K, V comparable]() *SetMap[K, V] {
+	return &SetMap[K, V]{
+		sets: make(map[K]*Set[V]),
+	}
+}
+
+// Insert value into the set of key.
+//
+// Returns true if m was modified (value was not already in the set of key),
+// false otherwise.
+func (m *SetMap[K, V]) Insert(key K, value V) bool {
+	s, exists := m.sets[key]
+	if !exists {
+		s = New[V](1)
+		m.sets[key] = s
+	}
+	return s.Insert(value)
+}
+
+// Remove value from the set of key, removing key from m if its set becomes
+// empty.
+//
+// Returns true if m was modified (value was in the set of key), false otherwise.
+func (m *SetMap[K, V]) Remove(key K, value V) bool {
+	s, exists := m.sets[key]
+	if !exists || !s.Remove(value) {
+		return false
+	}
+	if s.Empty() {
+		delete(m.sets, key)
+	}
+	return true
+}
+
+// Delete removes key and its set from m.
+//
+// Returns true if m was modified (key was in m), false otherwise.
+func (m *SetMap[K, V]) Delete(key K) bool {
+	if _, exists := m.sets[key]; !exists {
+		return false
+	}
+	delete(m.sets, key)
+	return true
+}
+
+// Contains returns whether value is in the set of key.
+func (m *SetMap[K, V]) Contains(key K, value V) bool {
+	s, exists := m.sets[key]
+	return exists && s.Contains(value)
+}
+
+// Get returns the set of key, or nil if key is not in m.
+//
+// The returned set is owned by m and must not be modified.
+func (m *SetMap[K, V]) Get(key K) *Set[V] {
+	return m.sets[key]
+}
+
+// Len returns the number of keys in m.
+func (m *SetMap[K, V]) Len() int {
+	return len(m.sets)
+}
+
+// Keys returns a generator function for iterating each key in m by using the
+// range keyword.
+//
+//	for key := range m.Keys() { ... }
+func (m *SetMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range m.sets {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Items returns a generator function for iterating each key in m along with
+// its set by using the range keyword. The sets are owned by m and must not be
+// modified.
+//
+//	for key, values := range m.Items() { ... }
+func (m *SetMap[K, V]) Items() iter.Seq2[K, *Set[V]] {
+	return func(yield func(K, *Set[V]) bool) {
+		for key, s := range m.sets {
+			if !yield(key, s) {
+				return
+			}
+		}
+	}
+}
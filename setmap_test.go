@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestSetMap(t *testing.T) {
+	m := NewSetMap[string, int]()
+	must.Eq(t, 0, m.Len())
+	must.Nil(t, m.Get("a"))
+
+	must.True(t, m.Insert("a", 1))
+	must.True(t, m.Insert("a", 2))
+	must.False(t, m.Insert("a", 2))
+	must.True(t, m.Insert("b", 1))
+	must.Eq(t, 2, m.Len())
+	must.Eq(t, From([]int{1, 2}), m.Get("a"))
+	must.True(t, m.Contains("a", 1))
+	must.False(t, m.Contains("b", 2))
+	must.False(t, m.Contains("c", 1))
+
+	keys := New[string](0)
+	for key, values := range m.Items() {
+		keys.Insert(key)
+		must.NotEmpty(t, values)
+	}
+	must.Eq(t, From([]string{"a", "b"}), keys)
+	must.Eq(t, keys, From(slices.Collect(m.Keys())))
+
+	// removing the last value of a key removes the key
+	must.True(t, m.Remove("b", 1))
+	must.False(t, m.Remove("b", 1))
+	must.Nil(t, m.Get("b"))
+	must.Eq(t, 1, m.Len())
+
+	must.True(t, m.Delete("a"))
+	must.False(t, m.Delete("a"))
+	must.Eq(t, 0, m.Len())
+}
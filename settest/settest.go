@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package settest provides helpers for testing code which uses the set
+// package.
+//
+// A TreeSet relies on its CompareFunc defining a strict weak ordering of its
+// elements; an invalid CompareFunc silently corrupts the tree, such that
+// elements which were inserted cannot be found. CheckComparator detects
+// invalid comparators from a test of the type being compared, e.g.
+//
+//	func TestVersion_Compare(t *testing.T) {
+//	  settest.CheckComparator(t, CompareVersions, []Version{v1, v2, v3})
+//	}
+package settest
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-set/v3"
+)
+
+// CheckComparator checks that compare defines a valid ordering of samples, by
+// calling compare with every pair and triple of samples. The test fails if
+// compare is not
+//
+//   - reflexive: compare(a, a) == 0
+//   - antisymmetric: compare(a, b) and compare(b, a) have opposite signs
+//   - transitive: if a <= b and b <= c, then a <= c, and likewise for ==
+//   - consistent with equality: compare(a, b) == 0 if and only if a == b
+//
+// Only the first violation of each property is reported. The number of calls
+// to compare grows with the cube of the number of samples, so a few dozen
+// samples, including edge cases (e.g. zero values and equal elements), are
+// usually best.
+func CheckComparator[T comparable](t testing.TB, compare set.CompareFunc[T], samples []T) {
+	t.Helper()
+	CheckComparatorFunc(t, compare, func(a, b T) bool { return a == b }, samples)
+}
+
+// CheckComparatorFunc checks that compare defines a valid ordering of samples,
+// as with CheckComparator, where two elements are equal according to equal,
+// e.g. for elements which are not comparable. If equal is nil, consistency
+// with equality is not checked.
+func CheckComparatorFunc[T any](t testing.TB, compare set.CompareFunc[T], equal func(a, b T) bool, samples []T) {
+	t.Helper()
+
+	// whether a violation of each property has been reported
+	var failedReflexive, failedAntisymmetric, failedTransitive, failedConsistent bool
+
+	for _, a := range samples {
+		if c := compare(a, a); c != 0 && !failedReflexive {
+			failedReflexive = true
+			t.Errorf("comparator is not reflexive: compare(%v, %v) = %d, want 0", a, a, c)
+		}
+
+		for _, b := range samples {
+			ab, ba := sign(compare(a, b)), sign(compare(b, a))
+			if ab != -ba && !failedAntisymmetric {
+				failedAntisymmetric = true
+				t.Errorf("comparator is not antisymmetric: compare(%v, %v) = %d and compare(%v, %v) = %d", a, b, ab, b, a, ba)
+			}
+
+			if equal != nil && (ab == 0) != equal(a, b) && !failedConsistent {
+				failedConsistent = true
+				t.Errorf("comparator is not consistent with equality: compare(%v, %v) = %d, but equal is %t", a, b, ab, equal(a, b))
+			}
+
+			if ab > 0 || failedTransitive {
+				continue
+			}
+			for _, c := range samples {
+				bc, ac := sign(compare(b, c)), sign(compare(a, c))
+				if bc > 0 {
+					continue
+				}
+				// a <= b <= c, so a < c unless a == b == c
+				want := -1
+				if ab == 0 && bc == 0 {
+					want = 0
+				}
+				if ac != want {
+					failedTransitive = true
+					t.Errorf("comparator is not transitive: compare(%v, %v) = %d and compare(%v, %v) = %d, but compare(%v, %v) = %d",
+						a, b, ab, b, c, bc, a, c, ac)
+					break
+				}
+			}
+		}
+	}
+}
+
+// sign returns -1, 0, or 1 as c is negative, zero, or positive.
+func sign(c int) int {
+	switch {
+	case c < 0:
+		return -1
+	case c > 0:
+		return 1
+	default:
+		return 0
+	}
+}
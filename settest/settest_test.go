@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package settest
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+// recorder is a testing.TB which records errors rather than failing.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func check[T comparable](compare func(a, b T) int, samples []T) []string {
+	r := new(recorder)
+	CheckComparator(r, compare, samples)
+	return r.errors
+}
+
+func TestCheckComparator_valid(t *testing.T) {
+	must.SliceEmpty(t, check(cmp.Compare[int], []int{3, 1, 2, 2, 0, -5}))
+	must.SliceEmpty(t, check(strings.Compare, []string{"", "a", "B", "b", "ab"}))
+	must.SliceEmpty(t, check(cmp.Compare[int], nil))
+}
+
+func TestCheckComparator_invalid(t *testing.T) {
+	samples := []int{1, 2, 3, 4}
+
+	cases := []struct {
+		name    string
+		compare func(a, b int) int
+		want    string
+	}{
+		{
+			name:    "reflexive",
+			compare: func(a, b int) int { return -1 },
+			want:    "not reflexive",
+		},
+		{
+			name: "antisymmetric",
+			compare: func(a, b int) int {
+				if a == b {
+					return 0
+				}
+				return 1
+			},
+			want: "not antisymmetric",
+		},
+		{
+			name: "transitive",
+			compare: func(a, b int) int {
+				// rock, paper, scissors
+				switch {
+				case a == b:
+					return 0
+				case (a+1)%3 == b%3:
+					return -1
+				default:
+					return 1
+				}
+			},
+			want: "not transitive",
+		},
+		{
+			name: "consistent",
+			compare: func(a, b int) int {
+				return cmp.Compare(a/2, b/2)
+			},
+			want: "not consistent with equality",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errors := check(tc.compare, samples)
+			must.SliceNotEmpty(t, errors)
+			must.SliceContainsFunc(t, errors, tc.want, func(a string, b string) bool {
+				return strings.Contains(a, b)
+			})
+		})
+	}
+}
+
+func TestCheckComparatorFunc(t *testing.T) {
+	type point struct {
+		_    func() // not comparable
+		x, y int
+	}
+	byX := func(a, b *point) int { return cmp.Compare(a.x, b.x) }
+	samples := []*point{{x: 1, y: 1}, {x: 1, y: 2}, {x: 2}}
+
+	t.Run("nil equal", func(t *testing.T) {
+		r := new(recorder)
+		CheckComparatorFunc(r, byX, nil, samples)
+		must.SliceEmpty(t, r.errors)
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		r := new(recorder)
+		CheckComparatorFunc(r, byX, func(a, b *point) bool {
+			return a.x == b.x && a.y == b.y
+		}, samples)
+		must.Len(t, 1, r.errors)
+		must.StrContains(t, r.errors[0], "not consistent with equality")
+	})
+}
@@ -0,0 +1,667 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// skipLevels is the maximum number of levels of a SkipSet, which is enough
+// to efficiently store 2^32 elements.
+const skipLevels = 32
+
+// SkipSet provides a generic sortable set implementation for Go that is safe
+// for concurrent use by multiple goroutines.
+//
+// The underlying data structure is a lazy concurrent Skip List. Lookups and
+// iteration never block, and insertions and removals lock only the handful of
+// nodes adjacent to the element being modified, so unrelated modifications
+// from many goroutines proceed in parallel.
+//
+// https://en.wikipedia.org/wiki/Skip_list
+//
+// Iteration via Items is weakly consistent: each element is produced in
+// order, and elements inserted or removed concurrently with the iteration may
+// or may not be produced. Operations that combine several elements (e.g.
+// Union, Slice, Size) are not atomic with respect to concurrent modification.
+//
+// For ordered sets that are not shared between goroutines, TreeSet is faster
+// and uses less memory.
+type SkipSet[T any] struct {
+	comparison CompareFunc[T]
+	head       *skipNode[T]
+	size       atomic.Int64
+
+	source     rand.Source // nil for the top-level functions of math/rand/v2
+	sourceLock sync.Mutex
+}
+
+// NewSkipSet creates a SkipSet of type T, comparing elements via a given
+// CompareFunc[T].
+//
+// T may be any type.
+func NewSkipSet[T any](compare CompareFunc[T]) *SkipSet[T] {
+	head := &skipNode[T]{
+		next: make([]atomic.Pointer[skipNode[T]], skipLevels),
+	}
+	head.linked.Store(true)
+	return &SkipSet[T]{
+		comparison: compare,
+		head:       head,
+	}
+}
+
+// SkipSetFrom creates a new SkipSet containing each item in items.
+//
+// T may be any type.
+func SkipSetFrom[T any](items []T, compare CompareFunc[T]) *SkipSet[T] {
+	s := NewSkipSet[T](compare)
+	s.InsertSlice(items)
+	return s
+}
+
+// skipNode is an element of a SkipSet, linked into the levels [0, len(next)).
+//
+// A node is visible once linked is set, and logically removed once marked is
+// set. The lock protects the next pointers of a node from concurrent writers.
+type skipNode[T any] struct {
+	element T
+	next    []atomic.Pointer[skipNode[T]]
+	lock    sync.Mutex
+	marked  atomic.Bool
+	linked  atomic.Bool
+}
+
+func (n *skipNode[T]) top() int {
+	return len(n.next) - 1
+}
+
+// WithRand configures s to use source for the random choices of its Skip List,
+// rather than the top-level functions of math/rand/v2. Returns s.
+//
+// The shape of a Skip List does not affect which elements it contains, but a
+// seeded source makes the performance of s reproducible, e.g. for benchmarks.
+// Calls to source are serialized by s, so source need not be safe for
+// concurrent use. WithRand must be called before s is shared.
+func (s *SkipSet[T]) WithRand(source rand.Source) *SkipSet[T] {
+	s.source = source
+	return s
+}
+
+// level returns a random level for a new node, where each level is half as
+// likely as the level below it.
+func (s *SkipSet[T]) level() int {
+	var u uint64
+	if s.source == nil {
+		u = rand.Uint64()
+	} else {
+		s.sourceLock.Lock()
+		u = s.source.Uint64()
+		s.sourceLock.Unlock()
+	}
+	return min(bits.TrailingZeros64(u), skipLevels-1)
+}
+
+// find locates the predecessors and successors of item at each level, and
+// returns the highest level at which item was found, or -1 if not found.
+func (s *SkipSet[T]) find(item T, preds, succs []*skipNode[T]) int {
+	found := -1
+	pred := s.head
+	for level := skipLevels - 1; level >= 0; level-- {
+		curr := pred.next[level].Load()
+		for curr != nil && s.comparison(item, curr.element) > 0 {
+			pred = curr
+			curr = pred.next[level].Load()
+		}
+		if found == -1 && curr != nil && s.comparison(item, curr.element) == 0 {
+			found = level
+		}
+		preds[level] = pred
+		succs[level] = curr
+	}
+	return found
+}
+
+// lockPreds locks each distinct predecessor in preds up to level top, and
+// returns whether each remains unmarked and still points to succs[level].
+//
+// The returned function unlocks the locked predecessors.
+func (s *SkipSet[T]) lockPreds(top int, preds, succs []*skipNode[T], succValid func(*skipNode[T]) bool) (bool, func()) {
+	locked := make([]*skipNode[T], 0, top+1)
+	unlock := func() {
+		for _, n := range locked {
+			n.lock.Unlock()
+		}
+	}
+
+	var prev *skipNode[T]
+	for level := 0; level <= top; level++ {
+		pred, succ := preds[level], succs[level]
+		if pred != prev {
+			pred.lock.Lock()
+			locked = append(locked, pred)
+			prev = pred
+		}
+		if pred.marked.Load() || !succValid(succ) || pred.next[level].Load() != succ {
+			return false, unlock
+		}
+	}
+	return true, unlock
+}
+
+// Insert item into s.
+//
+// Returns true if s was modified (item was not already in s), false otherwise.
+func (s *SkipSet[T]) Insert(item T) bool {
+	return s.insert(item) != nil
+}
+
+// insert inserts item into s, returning the node of item, or nil if item was
+// already in s.
+func (s *SkipSet[T]) insert(item T) *skipNode[T] {
+	top := s.level()
+	var preds, succs [skipLevels]*skipNode[T]
+
+	for {
+		if found := s.find(item, preds[:], succs[:]); found != -1 {
+			existing := succs[found]
+			if !existing.marked.Load() {
+				// wait for a concurrent insertion of item to complete
+				for !existing.linked.Load() {
+					runtime.Gosched()
+				}
+				return nil
+			}
+			// a concurrent removal of item is in progress, try again
+			continue
+		}
+
+		valid, unlock := s.lockPreds(top, preds[:], succs[:], func(succ *skipNode[T]) bool {
+			return succ == nil || !succ.marked.Load()
+		})
+		if !valid {
+			unlock()
+			continue
+		}
+
+		n := &skipNode[T]{
+			element: item,
+			next:    make([]atomic.Pointer[skipNode[T]], top+1),
+		}
+		for level := 0; level <= top; level++ {
+			n.next[level].Store(succs[level])
+		}
+		for level := 0; level <= top; level++ {
+			preds[level].next[level].Store(n)
+		}
+		n.linked.Store(true)
+		unlock()
+
+		s.size.Add(1)
+		return n
+	}
+}
+
+// InsertLease inserts item into s for the duration of a lease, returning a
+// release function which removes item from s. Calling release more than once
+// has no further effect.
+//
+// Returns true if s was modified (item was not already in s), false otherwise.
+// If item was already in s, the lease is not granted and release does nothing,
+// so that item is not removed on behalf of whoever inserted it.
+//
+// The lease covers only the insertion it was granted for: if item is removed
+// and then inserted again by other means before the lease is released,
+// release leaves it in s.
+func (s *SkipSet[T]) InsertLease(item T) (bool, func()) {
+	n := s.insert(item)
+	if n == nil {
+		return false, func() {}
+	}
+	var once sync.Once
+	return true, func() {
+		once.Do(func() {
+			s.remove(item, n)
+		})
+	}
+}
+
+// InsertUntil inserts item into s until ctx is done, at which point item is
+// removed from s, e.g. for tracking the members of a session for as long as
+// the session lasts.
+//
+// Returns true if s was modified (item was not already in s), false otherwise.
+// If item was already in s, it is not removed when ctx is done. If ctx is
+// already done, item is not inserted and false is returned. As with
+// InsertLease, item is not removed when ctx is done if it was removed and
+// inserted again by other means in the meantime.
+func (s *SkipSet[T]) InsertUntil(ctx context.Context, item T) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	inserted, release := s.InsertLease(item)
+	if inserted {
+		context.AfterFunc(ctx, release)
+	}
+	return inserted
+}
+
+// InsertSlice will insert each item in items into s.
+//
+// Return true if s was modified (at least one item was not already in s), false otherwise.
+func (s *SkipSet[T]) InsertSlice(items []T) bool {
+	modified := false
+	for _, item := range items {
+		if s.Insert(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// InsertSet will insert each element of col into s.
+//
+// Return true if s was modified (at least one item of col was not already in s), false otherwise.
+func (s *SkipSet[T]) InsertSet(col Collection[T]) bool {
+	modified := false
+	for item := range col.Items() {
+		if s.Insert(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// Remove item from s.
+//
+// Returns true if s was modified (item was in s), false otherwise.
+func (s *SkipSet[T]) Remove(item T) bool {
+	return s.remove(item, nil)
+}
+
+// remove removes item from s, provided its node is lease if lease is not nil.
+//
+// Returns true if s was modified (item was removed), false otherwise.
+func (s *SkipSet[T]) remove(item T, lease *skipNode[T]) bool {
+	var (
+		victim *skipNode[T]
+		marked bool
+		preds  [skipLevels]*skipNode[T]
+		succs  [skipLevels]*skipNode[T]
+	)
+
+	for {
+		found := s.find(item, preds[:], succs[:])
+
+		if !marked {
+			if found == -1 {
+				return false
+			}
+			victim = succs[found]
+			if lease != nil && victim != lease {
+				// item was removed and inserted again since the lease
+				return false
+			}
+			if !victim.linked.Load() || victim.top() != found || victim.marked.Load() {
+				return false
+			}
+
+			victim.lock.Lock()
+			if victim.marked.Load() {
+				// lost a race with a concurrent removal
+				victim.lock.Unlock()
+				return false
+			}
+			victim.marked.Store(true)
+			marked = true
+		}
+
+		valid, unlock := s.lockPreds(victim.top(), preds[:], succs[:], func(succ *skipNode[T]) bool {
+			return succ == victim
+		})
+		if !valid {
+			unlock()
+			continue
+		}
+
+		for level := victim.top(); level >= 0; level-- {
+			preds[level].next[level].Store(victim.next[level].Load())
+		}
+		victim.lock.Unlock()
+		unlock()
+
+		s.size.Add(-1)
+		return true
+	}
+}
+
+// RemoveSlice will remove each item in items from s.
+//
+// Return true if s was modified (any item was in s), false otherwise.
+func (s *SkipSet[T]) RemoveSlice(items []T) bool {
+	modified := false
+	for _, item := range items {
+		if s.Remove(item) {
+			modified = true
+		}
+	}
+	return modified
+}
+
+// RemoveSet will remove each element in col from s.
+//
+// Returns true if s was modified (at least one item in col was in s), false otherwise.
+func (s *SkipSet[T]) RemoveSet(col Collection[T]) bool {
+	return removeSet(s, col)
+}
+
+// RemoveFunc will remove each element from s that satisfies condition f.
+//
+// Return true if s was modified, false otherwise.
+func (s *SkipSet[T]) RemoveFunc(f func(T) bool) bool {
+	return removeFunc(s, f)
+}
+
+// RemoveFuncN will remove up to n elements from s that satisfy condition f,
+// e.g. for bounding the work done by each pass of a periodic purge of s.
+//
+// Elements are considered in order, and so the smallest n elements which
+// satisfy f are removed.
+//
+// Returns the number of elements removed.
+func (s *SkipSet[T]) RemoveFuncN(f func(T) bool, n int) int {
+	return removeFuncN(s, f, n)
+}
+
+// Compare compares a and b according to the CompareFunc of s, implementing
+// OrderedCollection.
+func (s *SkipSet[T]) Compare(a, b T) int {
+	return s.comparison(a, b)
+}
+
+func (s *SkipSet[T]) concurrent() {}
+
+// Contains returns whether item is present in s.
+func (s *SkipSet[T]) Contains(item T) bool {
+	pred := s.head
+	for level := skipLevels - 1; level >= 0; level-- {
+		curr := pred.next[level].Load()
+		for curr != nil && s.comparison(item, curr.element) > 0 {
+			pred = curr
+			curr = pred.next[level].Load()
+		}
+		if curr != nil && s.comparison(item, curr.element) == 0 {
+			return curr.linked.Load() && !curr.marked.Load()
+		}
+	}
+	return false
+}
+
+// ContainsSlice returns whether all elements in items are present in s.
+func (s *SkipSet[T]) ContainsSlice(items []T) bool {
+	return containsSlice(s, items)
+}
+
+// MissingFrom returns the elements of items which are not present in s, e.g. for
+// reporting which of a list of requested identifiers do not exist.
+//
+// The result preserves the order of items, including any duplicates.
+func (s *SkipSet[T]) MissingFrom(items []T) []T {
+	return missingFrom(s, items)
+}
+
+// ClassifySlice partitions items into those present in s, those absent from
+// s, and duplicates (i.e. each repeated occurrence of an item earlier in
+// items), in a single pass, e.g. for reporting which of a list of requested
+// identifiers are already known, which are new, and which were repeated.
+//
+// Each result preserves the order of items. The first occurrence of an item
+// is in either present or absent, and each later occurrence is in duplicates.
+func (s *SkipSet[T]) ClassifySlice(items []T) (present, absent, duplicates []T) {
+	return classifySlice(s, NewTreeSet[T](s.comparison), items)
+}
+
+// Subset returns whether col is a subset of s.
+func (s *SkipSet[T]) Subset(col Collection[T]) bool {
+	return subset(s, col)
+}
+
+// ProperSubset returns whether col is a proper subset of s.
+func (s *SkipSet[T]) ProperSubset(col Collection[T]) bool {
+	if s.Size() <= col.Size() {
+		return false
+	}
+	return s.Subset(col)
+}
+
+// Size returns the number of elements in s.
+func (s *SkipSet[T]) Size() int {
+	return int(s.size.Load())
+}
+
+// Empty returns true if there are no elements in s.
+func (s *SkipSet[T]) Empty() bool {
+	return s.Size() == 0
+}
+
+// Min returns the smallest element in s.
+//
+// Must not be called on an empty set; panics with an error wrapping
+// ErrEmptySet if s is empty. Use TryMin if s may be empty, or may become empty
+// due to concurrent modification.
+func (s *SkipSet[T]) Min() T {
+	item, err := s.TryMin()
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// Max returns the largest element in s.
+//
+// Must not be called on an empty set; panics with an error wrapping
+// ErrEmptySet if s is empty. Use TryMax if s may be empty, or may become empty
+// due to concurrent modification.
+func (s *SkipSet[T]) Max() T {
+	item, err := s.TryMax()
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryMin returns the smallest element in s.
+//
+// Returns an error wrapping ErrEmptySet if s is empty.
+func (s *SkipSet[T]) TryMin() (T, error) {
+	for item := range s.Items() {
+		return item, nil
+	}
+	var zero T
+	return zero, emptyError("min", s)
+}
+
+// TryMax returns the largest element in s.
+//
+// Returns an error wrapping ErrEmptySet if s is empty.
+func (s *SkipSet[T]) TryMax() (T, error) {
+	// descend to the last node, remembering the last node passed which is
+	// present, and then scan forward from it for the last node present
+	start := s.head
+	pred := s.head
+	for level := skipLevels - 1; level >= 0; level-- {
+		for curr := pred.next[level].Load(); curr != nil; curr = pred.next[level].Load() {
+			pred = curr
+			if pred.linked.Load() && !pred.marked.Load() {
+				start = pred
+			}
+		}
+	}
+
+	var last *skipNode[T]
+	for n := start; n != nil; n = n.next[0].Load() {
+		if n != s.head && n.linked.Load() && !n.marked.Load() {
+			last = n
+		}
+	}
+	if last == nil {
+		var zero T
+		return zero, emptyError("max", s)
+	}
+	return last.element, nil
+}
+
+// Union returns a set that contains all elements of s and col combined.
+func (s *SkipSet[T]) Union(col Collection[T]) Collection[T] {
+	result := NewSkipSet[T](s.comparison)
+	insert(result, s)
+	insert(result, col)
+	return result
+}
+
+// Difference returns a set that contains elements of s that are not in col.
+func (s *SkipSet[T]) Difference(col Collection[T]) Collection[T] {
+	result := NewSkipSet[T](s.comparison)
+	for item := range s.Items() {
+		if !col.Contains(item) {
+			result.Insert(item)
+		}
+	}
+	return result
+}
+
+// Intersect returns a set that contains elements that are present in both s and col.
+func (s *SkipSet[T]) Intersect(col Collection[T]) Collection[T] {
+	result := NewSkipSet[T](s.comparison)
+	intersect(result, s, col)
+	return result
+}
+
+// CopyFunc creates a deep copy of s, where each element of the copy is created
+// by passing an element of s through clone.
+//
+// The elements of the copy are ordered anew, and so if clone produces elements
+// which compare as equal for two elements of s, the copy contains fewer
+// elements than s.
+func (s *SkipSet[T]) CopyFunc(clone func(T) T) *SkipSet[T] {
+	result := NewSkipSet[T](s.comparison)
+	for item := range s.Items() {
+		result.Insert(clone(item))
+	}
+	return result
+}
+
+// Slice returns the elements of s as a slice, in order.
+func (s *SkipSet[T]) Slice() []T {
+	return s.AppendSlice(make([]T, 0, s.Size()))
+}
+
+// AppendSlice appends the elements of s to dst and returns the extended slice,
+// e.g. for reusing the same buffer across calls rather than allocating a new
+// slice via Slice each time.
+//
+// Elements are appended in order.
+func (s *SkipSet[T]) AppendSlice(dst []T) []T {
+	dst = slices.Grow(dst, s.Size())
+	for item := range s.Items() {
+		dst = append(dst, item)
+	}
+	return dst
+}
+
+// String creates a string representation of s, using "%v" printf formatting
+// each element into a string. The result contains elements in order.
+func (s *SkipSet[T]) String() string {
+	return s.StringFunc(func(element T) string {
+		return fmt.Sprintf("%v", element)
+	})
+}
+
+// StringFunc creates a string representation of s, using f to transform each
+// element into a string. The result contains elements in order.
+func (s *SkipSet[T]) StringFunc(f func(T) string) string {
+	l := make([]string, 0, s.Size())
+	for item := range s.Items() {
+		l = append(l, f(item))
+	}
+	return fmt.Sprintf("%s", l)
+}
+
+// ContentHash returns a hash of the elements of s which does not depend on
+// their order, combining the hash of each element as produced by hash, e.g.
+// for cheaply comparing sets of different types or in different processes.
+//
+// Sets containing the same elements have the same content hash regardless of
+// their type or internal layout. Sets containing different elements are
+// unlikely, but not guaranteed, to have different content hashes.
+func (s *SkipSet[T]) ContentHash(hash func(T) uint64) uint64 {
+	return contentHash(s, hash)
+}
+
+// EqualSet returns s and col contain the same elements.
+func (s *SkipSet[T]) EqualSet(col Collection[T]) bool {
+	return equalSet(s, col)
+}
+
+// EqualSlice returns whether s and items contain the same elements.
+//
+// The items slice may contain duplicates.
+func (s *SkipSet[T]) EqualSlice(items []T) bool {
+	return s.EqualSet(SkipSetFrom[T](items, s.comparison))
+}
+
+// EqualSliceSet returns whether s and items contain exactly the same elements,
+// where the elements of items are expected to be set-like. If items contains
+// duplicates EqualSliceSet returns false. For comparing s to a slice that may
+// contain duplicate elements, use EqualSlice instead.
+//
+// To detect if a slice is a subset of s, use ContainsSlice.
+func (s *SkipSet[T]) EqualSliceSet(items []T) bool {
+	return equalSliceSet(s, NewSkipSet[T](s.comparison), items)
+}
+
+// Items returns a generator function for iterating each element in s by using
+// the range keyword. Elements are produced in order.
+//
+// Iteration is weakly consistent, and s may be modified concurrently (even by
+// the iterating goroutine) without blocking or invalidating the iteration.
+//
+//	for element := range s.Items() { ... }
+func (s *SkipSet[T]) Items() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := s.head.next[0].Load(); n != nil; n = n.next[0].Load() {
+			if !n.linked.Load() || n.marked.Load() {
+				continue
+			}
+			if !yield(n.element) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *SkipSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[T](s)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// The set must have been created by NewSkipSet, as the zero value has no
+// CompareFunc. Use UnmarshalJSONSets or UnmarshalJSONSetMap for decoding
+// nested sets.
+func (s *SkipSet[T]) UnmarshalJSON(data []byte) error {
+	if s.comparison == nil {
+		return errNoConstructor("SkipSet")
+	}
+	return unmarshalJSON[T](s, data)
+}
@@ -0,0 +1,258 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"math/rand"
+	randv2 "math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+// skipInvariants makes assertions about the structure of a skip list
+func skipInvariants[T any](t *testing.T, s *SkipSet[T]) {
+	slice := s.Slice()
+	must.AscendingCmp(t, slice, s.comparison)
+	must.Eq(t, s.Size(), len(slice), must.Sprint("skip list is wrong size"))
+
+	for level := 1; level < skipLevels; level++ {
+		for n := s.head.next[level].Load(); n != nil; n = n.next[level].Load() {
+			must.Greater(t, level-1, n.top(), must.Sprintf("node %v linked above its top level", n.element))
+			must.True(t, s.Contains(n.element), must.Sprintf("node %v not linked at level 0", n.element))
+		}
+	}
+}
+
+func TestSkipSet_Insert(t *testing.T) {
+	s := NewSkipSet[int](cmp.Compare[int])
+	must.Empty(t, s)
+	skipInvariants(t, s)
+
+	for _, i := range shuffle(ints(size)) {
+		must.True(t, s.Insert(i))
+		must.False(t, s.Insert(i))
+	}
+	skipInvariants(t, s)
+	must.Size(t, size, s)
+	must.Eq(t, ints(size), s.Slice())
+
+	must.Eq(t, 1, s.Min())
+	must.Eq(t, size, s.Max())
+	greatest, err := s.TryMax()
+	must.NoError(t, err)
+	must.Eq(t, size, greatest)
+
+	must.True(t, s.Remove(size))
+	must.Eq(t, size-1, s.Max())
+}
+
+func TestSkipSet_InsertLease(t *testing.T) {
+	s := SkipSetFrom[int]([]int{1}, cmp.Compare[int])
+
+	inserted, release := s.InsertLease(2)
+	must.True(t, inserted)
+	must.Contains[int](t, 2, s)
+	release()
+	must.NotContains[int](t, 2, s)
+
+	// releasing again does not remove a later insertion
+	s.Insert(2)
+	release()
+	must.Contains[int](t, 2, s)
+
+	// a lease does not cover an insertion after its element was removed
+	inserted, release = s.InsertLease(3)
+	must.True(t, inserted)
+	must.True(t, s.Remove(3))
+	must.True(t, s.Insert(3))
+	release()
+	must.Contains[int](t, 3, s)
+
+	// no lease is granted for an existing element
+	inserted, release = s.InsertLease(1)
+	must.False(t, inserted)
+	release()
+	must.Contains[int](t, 1, s)
+}
+
+func TestSkipSet_InsertUntil(t *testing.T) {
+	s := SkipSetFrom[int]([]int{1}, cmp.Compare[int])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	must.True(t, s.InsertUntil(ctx, 2))
+	must.False(t, s.InsertUntil(ctx, 1))
+	must.Contains[int](t, 2, s)
+
+	cancel()
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool { return !s.Contains(2) }),
+		wait.Timeout(5*time.Second),
+		wait.Gap(time.Millisecond),
+	))
+	must.Contains[int](t, 1, s)
+
+	// an element is not inserted for a context that is already done
+	must.False(t, s.InsertUntil(ctx, 3))
+	must.NotContains[int](t, 3, s)
+
+	done, cancelDone := context.WithCancel(context.Background())
+	cancelDone()
+	must.False(t, s.InsertUntil(done, 4))
+	must.NotContains[int](t, 4, s)
+	must.Eq(t, []int{1}, s.Slice())
+}
+
+func TestSkipSet_Remove(t *testing.T) {
+	s := SkipSetFrom[int](shuffle(ints(size)), cmp.Compare[int])
+	must.False(t, s.Remove(0))
+
+	for i, item := range shuffle(ints(size)) {
+		must.True(t, s.Remove(item))
+		must.False(t, s.Remove(item))
+		must.Size(t, size-i-1, s)
+	}
+	must.Empty(t, s)
+	skipInvariants(t, s)
+
+	_, err := s.TryMin()
+	must.ErrorIs(t, err, ErrEmptySet)
+	_, err = s.TryMax()
+	must.ErrorIs(t, err, ErrEmptySet)
+	must.EqError(t, err, "max of empty *set.SkipSet[int]: set: set is empty")
+}
+
+func TestSkipSet_CopyFunc(t *testing.T) {
+	s := SkipSetFrom[int](ints(100), cmp.Compare[int])
+	c := s.CopyFunc(func(i int) int { return -i })
+	skipInvariants(t, c)
+	must.Eq(t, -100, c.Slice()[0])
+	must.Size(t, 100, c)
+}
+
+func TestSkipSet_random(t *testing.T) {
+	s := NewSkipSet[int](cmp.Compare[int])
+	ref := New[int](0)
+
+	for i := 0; i < 10*size; i++ {
+		item := rand.Intn(size / 4)
+		if rand.Intn(2) == 0 {
+			must.Eq(t, ref.Insert(item), s.Insert(item))
+		} else {
+			must.Eq(t, ref.Remove(item), s.Remove(item))
+		}
+	}
+	skipInvariants(t, s)
+	must.True(t, s.EqualSet(ref))
+}
+
+func TestSkipSet_WithRand(t *testing.T) {
+	levels := func(s *SkipSet[int]) []int {
+		var result []int
+		for n := s.head.next[0].Load(); n != nil; n = n.next[0].Load() {
+			result = append(result, n.top())
+		}
+		return result
+	}
+
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	a := NewSkipSet[int](cmp.Compare[int]).WithRand(randv2.NewPCG(1, 2))
+	a.InsertSlice(items)
+	b := NewSkipSet[int](cmp.Compare[int]).WithRand(randv2.NewPCG(1, 2))
+	b.InsertSlice(items)
+
+	must.Eq(t, items, a.Slice())
+	must.Eq(t, levels(a), levels(b))
+}
+
+func TestSkipSet_concurrent(t *testing.T) {
+	s := NewSkipSet[int](cmp.Compare[int])
+
+	var wg sync.WaitGroup
+	for w := 0; w < readers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			// every writer contends for the same elements
+			for _, i := range shuffle(ints(size)) {
+				s.Insert(i)
+				if i%2 == 0 {
+					s.Remove(i)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < size; i++ {
+				_ = s.Contains(i)
+				prev := 0
+				for item := range s.Items() {
+					must.Greater(t, prev, item)
+					prev = item
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// each odd element is inserted and never removed, whereas an even element
+	// may have been re-inserted by one writer after being removed by another
+	skipInvariants(t, s)
+	for i := 1; i <= size; i += 2 {
+		must.True(t, s.Contains(i))
+	}
+	for i := 2; i <= size; i += 2 {
+		s.Remove(i)
+	}
+	must.Size(t, size/2, s)
+	skipInvariants(t, s)
+}
+
+func TestSkipSet_Collection(t *testing.T) {
+	a := SkipSetFrom[int]([]int{1, 2, 3, 4, 5}, cmp.Compare[int])
+	b := SkipSetFrom[int]([]int{4, 5, 6, 7}, cmp.Compare[int])
+
+	must.Eq(t, "[1 2 3 4 5 6 7]", a.Union(b).String())
+	must.Eq(t, "[1 2 3]", a.Difference(b).String())
+	must.Eq(t, "[4 5]", a.Intersect(b).String())
+	must.Eq(t, "[4 5]", a.Intersect(From([]int{4, 5, 9})).String())
+
+	must.True(t, a.Subset(From([]int{1, 5})))
+	must.False(t, a.Subset(b))
+	must.True(t, a.ProperSubset(From([]int{1, 2, 3, 4})))
+	must.False(t, a.ProperSubset(From([]int{1, 2, 3, 4, 5})))
+
+	must.True(t, a.ContainsSlice([]int{2, 3}))
+	must.False(t, a.ContainsSlice([]int{2, 9}))
+	must.True(t, a.EqualSlice([]int{5, 4, 3, 2, 1, 1}))
+	must.False(t, a.EqualSliceSet([]int{5, 4, 3, 2, 1, 1}))
+	must.True(t, a.EqualSliceSet([]int{5, 4, 3, 2, 1}))
+	must.True(t, a.EqualSet(From([]int{1, 2, 3, 4, 5})))
+
+	must.True(t, a.RemoveFunc(func(i int) bool { return i%2 == 0 }))
+	must.Eq(t, []int{1, 3, 5}, a.Slice())
+	must.True(t, a.RemoveSet(From([]int{1, 9})))
+	must.Eq(t, []int{3, 5}, a.Slice())
+}
+
+func TestSkipSet_JSON(t *testing.T) {
+	s := SkipSetFrom[string]([]string{"b", "c", "a"}, cmp.Compare[string])
+	bs, err := json.Marshal(s)
+	must.NoError(t, err)
+	must.Eq(t, `["a","b","c"]`, string(bs))
+
+	result := NewSkipSet[string](cmp.Compare[string])
+	must.NoError(t, json.Unmarshal(bs, result))
+	must.True(t, s.EqualSet(result))
+}
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"iter"
+	"slices"
+)
+
+// SortedView provides the elements of a Set or HashSet in sorted order, as
+// created by Set.SortedView or HashSet.SortedView.
+//
+// The view sorts the elements of the set on first use and retains the sorted
+// slice until the set is next modified, so that repeated ordered reads of an
+// unmodified set cost O(1) rather than O(n log n) each. The first read after a
+// modification of the set sorts the elements again.
+//
+// A SortedView is not thread safe, even for reads, as reading may update the
+// retained slice. For sets which are frequently modified and read in order,
+// a TreeSet is a better fit.
+type SortedView[T any] struct {
+	col     Collection[T]
+	version *uint64
+	compare CompareFunc[T]
+
+	sorted []T
+	built  uint64 // version of the set at which sorted was built
+	valid  bool
+}
+
+// SortedView returns a view of the elements of s sorted by compare. For
+// elements satisfying cmp.Ordered, use cmp.Compare as compare.
+func (s *Set[T]) SortedView(compare CompareFunc[T]) *SortedView[T] {
+	return &SortedView[T]{col: s, version: &s.version, compare: compare}
+}
+
+// SortedView returns a view of the elements of s sorted by compare. For
+// elements satisfying cmp.Ordered, use cmp.Compare as compare.
+func (s *HashSet[T, H]) SortedView(compare CompareFunc[T]) *SortedView[T] {
+	return &SortedView[T]{col: s, version: &s.version, compare: compare}
+}
+
+// refresh sorts the elements of the set again if the set has been modified
+// since they were last sorted.
+func (v *SortedView[T]) refresh() {
+	if v.valid && v.built == *v.version {
+		return
+	}
+	// a new slice, as the previous one may still be referenced via Slice
+	sorted := make([]T, 0, v.col.Size())
+	for item := range v.col.Items() {
+		sorted = append(sorted, item)
+	}
+	slices.SortFunc(sorted, v.compare)
+	v.sorted = sorted
+	v.built = *v.version
+	v.valid = true
+}
+
+// Len returns the number of elements in the set.
+func (v *SortedView[T]) Len() int {
+	return v.col.Size()
+}
+
+// At returns the element at index i in sorted order, panicking if i is not in
+// the range [0, Len()).
+func (v *SortedView[T]) At(i int) T {
+	v.refresh()
+	return v.sorted[i]
+}
+
+// Slice returns the elements of the set in sorted order.
+//
+// The returned slice is shared with the view and so must not be modified. It
+// is not affected by subsequent modification of the set.
+func (v *SortedView[T]) Slice() []T {
+	v.refresh()
+	return v.sorted
+}
+
+// Items returns a generator function for iterating each element of the set in
+// sorted order by using the range keyword.
+//
+//	for element := range v.Items() { ... }
+func (v *SortedView[T]) Items() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range v.Slice() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
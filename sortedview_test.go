@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestSortedView(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		s := From([]int{5, 3, 9, 1})
+		v := s.SortedView(cmp.Compare[int])
+		must.Eq(t, []int{1, 3, 5, 9}, v.Slice())
+		must.Eq(t, 4, v.Len())
+		must.Eq(t, 1, v.At(0))
+		must.Eq(t, 9, v.At(3))
+		must.Eq(t, []int{1, 3, 5, 9}, slices.Collect(v.Items()))
+	})
+
+	t.Run("retained", func(t *testing.T) {
+		s := From([]int{5, 3, 9, 1})
+		v := s.SortedView(cmp.Compare[int])
+		first := v.Slice()
+		must.True(t, &first[0] == &v.Slice()[0])
+
+		// no modification, so no new slice
+		s.Insert(3)
+		s.Remove(4)
+		must.True(t, &first[0] == &v.Slice()[0])
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		s := From([]int{5, 3, 9, 1})
+		v := s.SortedView(cmp.Compare[int])
+		first := v.Slice()
+
+		s.Insert(4)
+		must.Eq(t, []int{1, 3, 4, 5, 9}, v.Slice())
+		must.Eq(t, []int{1, 3, 5, 9}, first) // unaffected
+
+		s.Remove(1)
+		must.Eq(t, []int{3, 4, 5, 9}, v.Slice())
+
+		s.Clear()
+		must.Eq(t, []int{}, v.Slice())
+		must.Eq(t, 0, v.Len())
+	})
+
+	t.Run("hashset", func(t *testing.T) {
+		s := HashSetFrom[*company, string]([]*company{c1, c2, c3})
+		byFloor := func(a, b *company) int { return cmp.Compare(a.floor, b.floor) }
+		v := s.SortedView(byFloor)
+		must.Eq(t, []*company{c1, c2, c3}, v.Slice())
+
+		s.Remove(c2)
+		must.Eq(t, []*company{c1, c3}, v.Slice())
+
+		s.RemoveKey(c1.Hash())
+		must.Eq(t, []*company{c3}, v.Slice())
+
+		s.Insert(c4)
+		must.Eq(t, []*company{c3, c4}, v.Slice())
+	})
+
+	t.Run("reverse", func(t *testing.T) {
+		s := From([]string{"b", "c", "a"})
+		v := s.SortedView(func(a, b string) int { return cmp.Compare(b, a) })
+		must.Eq(t, []string{"c", "b", "a"}, v.Slice())
+	})
+}
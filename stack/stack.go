@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package stack provides a minimal generic LIFO stack.
+//
+// The stack is used by the set package for iterating the elements of a
+// TreeSet in order, and is exported for use by similar iterator
+// implementations.
+package stack
+
+// Stack is a generic last-in-first-out stack, backed by a linked list.
+//
+// The zero value is an empty stack ready to use.
+//
+// Not thread safe, and not safe for concurrent modification.
+type Stack[T any] struct {
+	top  *object[T]
+	size int
+}
+
+type object[T any] struct {
+	item T
+	next *object[T]
+}
+
+// New creates an empty Stack.
+func New[T any]() *Stack[T] {
+	return new(Stack[T])
+}
+
+// Push item onto the top of s.
+func (s *Stack[T]) Push(item T) {
+	obj := &object[T]{
+		item: item,
+		next: s.top,
+	}
+	s.top = obj
+	s.size++
+}
+
+// Pop removes and returns the item on the top of s.
+//
+// Must not be called on an empty stack.
+func (s *Stack[T]) Pop() T {
+	if s.top == nil {
+		panic("pop: stack is empty")
+	}
+	obj := s.top
+	s.top = obj.next
+	obj.next = nil
+	s.size--
+	return obj.item
+}
+
+// Peek returns the item on the top of s without removing it.
+//
+// A zero value and false are returned if s is empty.
+func (s *Stack[T]) Peek() (T, bool) {
+	if s.top == nil {
+		var zero T
+		return zero, false
+	}
+	return s.top.item, true
+}
+
+// Empty returns whether s contains no items.
+func (s *Stack[T]) Empty() bool {
+	return s.top == nil
+}
+
+// Size returns the number of items in s.
+func (s *Stack[T]) Size() int {
+	return s.size
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestStack_simple(t *testing.T) {
+	s := New[int]()
+	must.True(t, s.Empty())
+	must.Zero(t, s.Size())
+
+	s.Push(1)
+	must.False(t, s.Empty())
+	must.Eq(t, 1, s.Size())
+
+	value := s.Pop()
+	must.Eq(t, 1, value)
+	must.True(t, s.Empty())
+	must.Zero(t, s.Size())
+}
+
+func TestStack_complex(t *testing.T) {
+	s := New[byte]()
+
+	s.Push('a')
+	s.Push('b')
+	s.Push('c')
+	s.Push('d')
+	s.Push('e')
+	s.Push('f')
+
+	must.Eq(t, 'f', s.Pop())
+	must.Eq(t, 'e', s.Pop())
+	must.Eq(t, 'd', s.Pop())
+
+	s.Push('x')
+	s.Push('y')
+
+	must.Eq(t, 'y', s.Pop())
+
+	s.Push('z')
+
+	must.Eq(t, 5, s.Size())
+	must.Eq(t, 'z', s.Pop())
+	must.Eq(t, 'x', s.Pop())
+	must.Eq(t, 'c', s.Pop())
+	must.Eq(t, 'b', s.Pop())
+	must.Eq(t, 'a', s.Pop())
+	must.True(t, s.Empty())
+}
+
+func TestStack_Peek(t *testing.T) {
+	var s Stack[string]
+
+	_, ok := s.Peek()
+	must.False(t, ok)
+
+	s.Push("a")
+	s.Push("b")
+
+	top, ok := s.Peek()
+	must.True(t, ok)
+	must.Eq(t, "b", top)
+	must.Eq(t, 2, s.Size())
+}
+
+func TestStack_Pop_empty(t *testing.T) {
+	defer func() {
+		must.NotNil(t, recover())
+	}()
+	New[int]().Pop()
+}
@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"iter"
+	"time"
+)
+
+// TimestampedSet provides a set which records the time each element was
+// inserted, e.g. for garbage collecting elements which have been present for
+// too long, without maintaining a separate map of times alongside the set.
+//
+// Not thread safe, and not safe for concurrent modification.
+type TimestampedSet[T comparable] struct {
+	items map[T]time.Time // time of insertion of each element
+	now   func() time.Time
+}
+
+// NewTimestampedSet creates a TimestampedSet with underlying capacity of size.
+//
+// Elements are stamped with time.Now; use WithClock to stamp them using
+// another clock.
+func NewTimestampedSet[T comparable](size int) *TimestampedSet[T] {
+	return &TimestampedSet[T]{
+		items: make(map[T]time.Time, max(0, size)),
+		now:   time.Now,
+	}
+}
+
+// WithClock configures s to stamp elements and measure their age using now
+// rather than time.Now, e.g. for testing with a clock that is advanced
+// manually. Returns s.
+func (s *TimestampedSet[T]) WithClock(now func() time.Time) *TimestampedSet[T] {
+	s.now = now
+	return s
+}
+
+// Insert item into s, stamped with the current time.
+//
+// If item is already in s, its original time of insertion is retained.
+//
+// Return true if s was modified (item was not already in s), false otherwise.
+func (s *TimestampedSet[T]) Insert(item T) bool {
+	if _, exists := s.items[item]; exists {
+		return false
+	}
+	s.items[item] = s.now()
+	return true
+}
+
+// Remove item from s.
+//
+// Return true if s was modified (item was in s), false otherwise.
+func (s *TimestampedSet[T]) Remove(item T) bool {
+	if _, exists := s.items[item]; !exists {
+		return false
+	}
+	delete(s.items, item)
+	return true
+}
+
+// RemoveOlderThan removes each element of s inserted more than d ago.
+//
+// Returns the number of elements removed.
+func (s *TimestampedSet[T]) RemoveOlderThan(d time.Duration) int {
+	cutoff := s.now().Add(-d)
+	removed := 0
+	for item, inserted := range s.items {
+		if inserted.Before(cutoff) {
+			delete(s.items, item)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Contains returns whether item is present in s.
+func (s *TimestampedSet[T]) Contains(item T) bool {
+	_, exists := s.items[item]
+	return exists
+}
+
+// InsertedAt returns the time item was inserted into s, and whether item is
+// present in s.
+func (s *TimestampedSet[T]) InsertedAt(item T) (time.Time, bool) {
+	inserted, exists := s.items[item]
+	return inserted, exists
+}
+
+// OlderThan creates a Set of the elements of s inserted more than d ago.
+func (s *TimestampedSet[T]) OlderThan(d time.Duration) *Set[T] {
+	cutoff := s.now().Add(-d)
+	result := New[T](0)
+	for item, inserted := range s.items {
+		if inserted.Before(cutoff) {
+			result.items[item] = sentinel
+		}
+	}
+	return result
+}
+
+// Size returns the cardinality of s.
+func (s *TimestampedSet[T]) Size() int {
+	return len(s.items)
+}
+
+// Empty returns true if s contains no elements, false otherwise.
+func (s *TimestampedSet[T]) Empty() bool {
+	return len(s.items) == 0
+}
+
+// Items returns a generator function for iterating each element in s along
+// with its time of insertion by using the range keyword. Elements are
+// produced in no particular order.
+//
+//	for element, inserted := range s.Items() { ... }
+func (s *TimestampedSet[T]) Items() iter.Seq2[T, time.Time] {
+	return func(yield func(T, time.Time) bool) {
+		for item, inserted := range s.items {
+			if !yield(item, inserted) {
+				return
+			}
+		}
+	}
+}
+
+// Snapshot creates a Set of the elements of s.
+func (s *TimestampedSet[T]) Snapshot() *Set[T] {
+	result := New[T](len(s.items))
+	for item := range s.items {
+		result.items[item] = sentinel
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestTimestampedSet(t *testing.T) {
+	t.Run("inserted at", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewTimestampedSet[string](0).WithClock(clock.now)
+
+		must.True(t, s.Insert("a"))
+		clock.advance(time.Minute)
+		must.True(t, s.Insert("b"))
+		must.False(t, s.Insert("a")) // retains original time
+
+		inserted, exists := s.InsertedAt("a")
+		must.True(t, exists)
+		must.Eq(t, time.Unix(0, 0), inserted)
+		inserted, exists = s.InsertedAt("b")
+		must.True(t, exists)
+		must.Eq(t, time.Unix(60, 0), inserted)
+		_, exists = s.InsertedAt("c")
+		must.False(t, exists)
+
+		must.True(t, s.Remove("a"))
+		must.False(t, s.Remove("a"))
+		must.False(t, s.Contains("a"))
+		_, exists = s.InsertedAt("a")
+		must.False(t, exists)
+	})
+
+	t.Run("older than", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewTimestampedSet[string](0).WithClock(clock.now)
+
+		s.Insert("a")
+		clock.advance(time.Minute)
+		s.Insert("b")
+		clock.advance(time.Minute)
+		s.Insert("c")
+
+		must.Eq(t, Of("a"), s.OlderThan(90*time.Second))
+		must.Eq(t, Of("a", "b"), s.OlderThan(30*time.Second))
+		must.Empty(t, s.OlderThan(5*time.Minute))
+
+		must.Eq(t, 1, s.RemoveOlderThan(90*time.Second))
+		must.Eq(t, Of("b", "c"), s.Snapshot())
+		clock.advance(time.Hour)
+		must.Eq(t, 2, s.RemoveOlderThan(time.Minute))
+		must.True(t, s.Empty())
+	})
+
+	t.Run("items", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewTimestampedSet[int](0).WithClock(clock.now)
+		for i := range 3 {
+			s.Insert(i)
+			clock.advance(time.Second)
+		}
+		must.Eq(t, 3, s.Size())
+		for element, inserted := range s.Items() {
+			must.Eq(t, time.Unix(int64(element), 0), inserted)
+		}
+	})
+}
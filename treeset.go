@@ -4,8 +4,17 @@
 package set
 
 import (
+	"cmp"
+	"context"
+	"encoding/json"
 	"fmt"
 	"iter"
+	"math/bits"
+	"reflect"
+	"runtime"
+	"slices"
+
+	"github.com/hashicorp/go-set/v3/stack"
 )
 
 // CompareFunc represents a function that compares two elements.
@@ -16,7 +25,9 @@ import (
 // > 0 if the first parameters is greater than the second parameter
 //
 // Often T will be a type that satisfies cmp.Ordered, and CompareFunc can
-// be implemented by using cmp.Compare.
+// be implemented by using cmp.Compare. Otherwise, the CompareFunc must define
+// a consistent ordering of the elements, which settest.CheckComparator can
+// verify from a test.
 type CompareFunc[T any] func(T, T) int
 
 // TreeSet provides a generic sortable set implementation for Go.
@@ -27,12 +38,20 @@ type CompareFunc[T any] func(T, T) int
 // The underlying data structure is a Red-Black Binary Search Tree.
 // https://en.wikipedia.org/wiki/Red–black_tree
 //
-// Not thread safe, and not safe for concurrent modification.
+// Not thread safe, and not safe for concurrent modification. Multiple goroutines
+// may read from a TreeSet concurrently (e.g. Contains, Items, Min) as long as
+// no goroutine is modifying it. Modifying a TreeSet while iterating its
+// elements is not supported, and is detected when built with -tags setdebug.
 type TreeSet[T any] struct {
+	guard      guard
 	comparison CompareFunc[T]
 	root       *node[T]
-	marker     *node[T]
 	size       int
+	stats      TreeSetStats
+	slab       []node[T] // preallocated nodes, see Grow
+	native     bool      // comparison is cmp.Compare of an integer type, see locate
+
+	onDuplicate func(existing, incoming T) T
 }
 
 // NewTreeSet creates a TreeSet of type T, comparing elements via a given
@@ -40,13 +59,17 @@ type TreeSet[T any] struct {
 //
 // T may be any type.
 //
-// For builtin types, CompareBuiltin provides a convenient CompareFunc implementation.
+// For builtin types, cmp.Compare provides a convenient CompareFunc
+// implementation. A TreeSet of int or uint64 elements using cmp.Compare
+// searches the tree comparing elements directly rather than by calling
+// cmp.Compare, which speeds up Insert, Contains, and Find. Other operations,
+// e.g. FirstBelow or CountRange, call cmp.Compare as for any CompareFunc.
 func NewTreeSet[T any](compare CompareFunc[T]) *TreeSet[T] {
 	return &TreeSet[T]{
 		comparison: compare,
 		root:       nil,
-		marker:     &node[T]{color: black},
 		size:       0,
+		native:     isNativeCompare(compare),
 	}
 }
 
@@ -62,14 +85,107 @@ func TreeSetFrom[T any](items []T, compare CompareFunc[T]) *TreeSet[T] {
 	return s
 }
 
+// TreeSetOf creates a new TreeSet containing each of items, comparing elements
+// via compare.
+//
+// TreeSetOf is a more concise alternative to TreeSetFrom for a fixed list of
+// elements, e.g.
+//
+//	s := set.TreeSetOf(cmp.Compare[int], 3, 1, 2)
+func TreeSetOf[T any](compare CompareFunc[T], items ...T) *TreeSet[T] {
+	return TreeSetFrom(items, compare)
+}
+
+// OnDuplicate configures s to resolve the insertion of an element equal to an
+// element already in s by replacing the existing element with the result of
+// calling f with the existing element and the inserted element. Returns s.
+//
+// By default the existing element is kept. OnDuplicate is useful when elements
+// are ordered by a subset of their fields, e.g. for an index where inserting
+// an element with the same key replaces the element (see ReplaceExisting), or
+// merges the remaining fields of both elements.
+//
+// The result of f must compare as equal to the existing element. Insert still
+// reports a duplicate as not modifying s, as the elements of s are unchanged
+// according to the CompareFunc of s. Sets created from s via Copy, CopyFunc,
+// Union, UnionFunc, UnionCtx, DifferenceCtx, and IntersectCtx use f as well.
+func (s *TreeSet[T]) OnDuplicate(f func(existing, incoming T) T) *TreeSet[T] {
+	s.onDuplicate = f
+	return s
+}
+
+// ReplaceExisting returns incoming, for use with TreeSet.OnDuplicate to replace
+// an existing element with an inserted duplicate.
+func ReplaceExisting[T any](existing, incoming T) T {
+	return incoming
+}
+
 // Insert item into s.
 //
 // Returns true if s was modified (item was not already in s), false otherwise.
+// If an equal element is already in s, it is kept unless s is configured via
+// OnDuplicate.
 func (s *TreeSet[T]) Insert(item T) bool {
-	return s.insert(&node[T]{
-		element: item,
-		color:   red,
-	})
+	n := s.newNode()
+	n.element = item
+	n.color = red
+	if !s.insert(n) {
+		s.releaseNode(n)
+		return false
+	}
+	return true
+}
+
+// Grow preallocates storage for at least n more elements, so that inserting
+// the next n elements into s does not allocate, e.g. before inserting a
+// known number of elements.
+//
+// The storage for the n elements is allocated as a single slab, which is only
+// released once every element in it is removed from s (though removed elements
+// themselves are not retained). Growing a TreeSet
+// which then only briefly contains most of its elements may therefore retain
+// more memory than without Grow.
+//
+// Grow is never called implicitly, e.g. by TreeSetFrom or Copy, since only the
+// caller knows how many distinct elements will be inserted and how long they
+// will remain in s.
+func (s *TreeSet[T]) Grow(n int) {
+	if n > len(s.slab) {
+		s.slab = make([]node[T], n)
+	}
+}
+
+// newNode returns a node from the slab of s, or a newly allocated node if the
+// slab is exhausted.
+//
+// Once its last node is taken, s no longer refers to the slab, so that it is
+// released once every node in it is unused.
+func (s *TreeSet[T]) newNode() *node[T] {
+	if last := len(s.slab) - 1; last >= 0 {
+		n := &s.slab[last]
+		s.slab = s.slab[:last]
+		if last == 0 {
+			s.slab = nil
+		}
+		return n
+	}
+	return new(node[T])
+}
+
+// releaseNode returns n to the slab of s, if n is the node most recently
+// taken from the slab and was not used.
+func (s *TreeSet[T]) releaseNode(n *node[T]) {
+	if len(s.slab) < cap(s.slab) && &s.slab[:len(s.slab)+1][len(s.slab)] == n {
+		*n = node[T]{}
+		s.slab = s.slab[:len(s.slab)+1]
+	}
+}
+
+// reclaimSlab zeroes each node taken from the slab of s and returns it to the
+// slab, once the tree of s no longer refers to any node.
+func (s *TreeSet[T]) reclaimSlab() {
+	s.slab = s.slab[:cap(s.slab)]
+	clear(s.slab)
 }
 
 // InsertSlice will insert each item in items into s.
@@ -132,12 +248,24 @@ func (s *TreeSet[T]) RemoveFunc(f func(T) bool) bool {
 	return removeFunc(s, f)
 }
 
+// RemoveFuncN will remove up to n elements from s that satisfy condition f,
+// e.g. for bounding the work done by each pass of a periodic purge of s.
+//
+// Elements are considered in order, and so the smallest n elements which
+// satisfy f are removed.
+//
+// Returns the number of elements removed.
+func (s *TreeSet[T]) RemoveFuncN(f func(T) bool, n int) int {
+	return removeFuncN(s, f, n)
+}
+
 // Min returns the smallest item in the set.
 //
-// Must not be called on an empty set.
+// Must not be called on an empty set; panics with an error wrapping
+// ErrEmptySet if s is empty. Use TryMin if s may be empty.
 func (s *TreeSet[T]) Min() T {
 	if s.root == nil {
-		panic("min: tree is empty")
+		panic(emptyError("min", s))
 	}
 	n := s.min(s.root)
 	return n.element
@@ -145,15 +273,38 @@ func (s *TreeSet[T]) Min() T {
 
 // Max returns the largest item in s.
 //
-// Must not be called on an empty set.
+// Must not be called on an empty set; panics with an error wrapping
+// ErrEmptySet if s is empty. Use TryMax if s may be empty.
 func (s *TreeSet[T]) Max() T {
 	if s.root == nil {
-		panic("max: tree is empty")
+		panic(emptyError("max", s))
 	}
 	n := s.max(s.root)
 	return n.element
 }
 
+// TryMin returns the smallest item in s.
+//
+// Returns an error wrapping ErrEmptySet if s is empty.
+func (s *TreeSet[T]) TryMin() (T, error) {
+	if s.root == nil {
+		var zero T
+		return zero, emptyError("min", s)
+	}
+	return s.Min(), nil
+}
+
+// TryMax returns the largest item in s.
+//
+// Returns an error wrapping ErrEmptySet if s is empty.
+func (s *TreeSet[T]) TryMax() (T, error) {
+	if s.root == nil {
+		var zero T
+		return zero, emptyError("max", s)
+	}
+	return s.Max(), nil
+}
+
 // TopK returns the top n (smallest) elements in s, in ascending order.
 func (s *TreeSet[T]) TopK(n int) []T {
 	result := make([]T, 0, n)
@@ -168,6 +319,51 @@ func (s *TreeSet[T]) BottomK(n int) []T {
 	return result
 }
 
+// TruncateToSmallestK removes every element of s except the k smallest, i.e.
+// those returned by TopK(k). Returns the number of elements removed.
+//
+// Unlike removing elements individually, when most of s is being removed the
+// retained elements are rebuilt into a new balanced tree in O(k) time.
+func (s *TreeSet[T]) TruncateToSmallestK(k int) int {
+	return s.truncate(k, s.TopK, s.Max)
+}
+
+// TruncateToLargestK removes every element of s except the k largest, i.e.
+// those returned by BottomK(k). Returns the number of elements removed.
+//
+// Unlike removing elements individually, when most of s is being removed the
+// retained elements are rebuilt into a new balanced tree in O(k) time.
+func (s *TreeSet[T]) TruncateToLargestK(k int) int {
+	return s.truncate(k, func(n int) []T {
+		items := s.BottomK(n)
+		slices.Reverse(items)
+		return items
+	}, s.Min)
+}
+
+// truncate reduces s to k elements, where keep returns the k retained elements
+// in ascending order, and discard returns the next element to be removed.
+func (s *TreeSet[T]) truncate(k int, keep func(int) []T, discard func() T) int {
+	s.guard.check("truncate")
+
+	k = max(k, 0)
+	removed := s.size - k
+	if removed <= 0 {
+		return 0
+	}
+
+	// removing a few elements is cheaper than rebuilding the whole tree
+	if removed <= k {
+		for range removed {
+			s.delete(discard())
+		}
+		return removed
+	}
+
+	s.rebuild(keep(k))
+	return removed
+}
+
 // FirstBelow returns the first element strictly below item.
 //
 // A zero value and false are returned if no such element exists.
@@ -284,6 +480,40 @@ func (s *TreeSet[T]) AboveEqual(item T) *TreeSet[T] {
 	return result
 }
 
+// CountRange returns the number of elements in s that are ≥ lo and ≤ hi.
+//
+// Unlike creating a range of s via AboveEqual and BelowEqual, CountRange does
+// not create any intermediate sets and runs in O(log n) time.
+func (s *TreeSet[T]) CountRange(lo, hi T) int {
+	if s.comparison(lo, hi) > 0 {
+		return 0
+	}
+	return s.countBelow(hi, true) - s.countBelow(lo, false)
+}
+
+// countBelow returns the number of elements in s that are < item, or ≤ item
+// if inclusive is set.
+func (s *TreeSet[T]) countBelow(item T, inclusive bool) int {
+	count := 0
+	n := s.root
+	for n != nil {
+		c := s.comparison(item, n.element)
+		if c > 0 || (inclusive && c == 0) {
+			count += n.left.count() + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return count
+}
+
+// Compare compares a and b according to the CompareFunc of s, implementing
+// OrderedCollection.
+func (s *TreeSet[T]) Compare(a, b T) int {
+	return s.comparison(a, b)
+}
+
 // Contains returns whether item is present in s.
 func (s *TreeSet[T]) Contains(item T) bool {
 	return s.locate(s.root, item) != nil
@@ -294,6 +524,34 @@ func (s *TreeSet[T]) ContainsSlice(items []T) bool {
 	return containsSlice(s, items)
 }
 
+// MissingFrom returns the elements of items which are not present in s, e.g. for
+// reporting which of a list of requested identifiers do not exist.
+//
+// The result preserves the order of items, including any duplicates.
+func (s *TreeSet[T]) MissingFrom(items []T) []T {
+	return missingFrom(s, items)
+}
+
+// ClassifySlice partitions items into those present in s, those absent from
+// s, and duplicates (i.e. each repeated occurrence of an item earlier in
+// items), in a single pass, e.g. for reporting which of a list of requested
+// identifiers are already known, which are new, and which were repeated.
+//
+// Each result preserves the order of items. The first occurrence of an item
+// is in either present or absent, and each later occurrence is in duplicates.
+func (s *TreeSet[T]) ClassifySlice(items []T) (present, absent, duplicates []T) {
+	return classifySlice(s, NewTreeSet[T](s.comparison), items)
+}
+
+// Clear removes every element from s.
+func (s *TreeSet[T]) Clear() {
+	s.guard.check("clear")
+
+	s.root = nil
+	s.size = 0
+	s.reclaimSlab()
+}
+
 // Size returns the number of elements in s.
 func (s *TreeSet[T]) Size() int {
 	return s.size
@@ -306,15 +564,70 @@ func (s *TreeSet[T]) Empty() bool {
 
 // Slice returns the elements of s as a slice, in order.
 func (s *TreeSet[T]) Slice() []T {
-	result := make([]T, 0, s.Size())
+	return s.AppendSlice(make([]T, 0, s.Size()))
+}
+
+// AppendSlice appends the elements of s to dst and returns the extended slice,
+// e.g. for reusing the same buffer across calls rather than allocating a new
+// slice via Slice each time.
+//
+// Elements are appended in order.
+func (s *TreeSet[T]) AppendSlice(dst []T) []T {
+	dst = slices.Grow(dst, s.Size())
 	for item := range s.Items() {
-		result = append(result, item)
+		dst = append(dst, item)
 	}
-	return result
+	return dst
+}
+
+// CheckCompatible returns an error wrapping ErrIncompatibleComparator if s and o
+// were not created with the same CompareFunc.
+//
+// Operations that walk two TreeSets in lockstep (e.g. Subset, Equal) assume
+// both sets share the same ordering, and silently produce wrong results
+// otherwise. Build with -tags setdebug to have those operations panic when
+// given incompatible sets.
+//
+// Functions in Go cannot be compared for equality, so the check is based on
+// the identity of the underlying function code. Two closures created from the
+// same function literal but capturing different state are indistinguishable
+// and will be considered compatible.
+func (s *TreeSet[T]) CheckCompatible(o *TreeSet[T]) error {
+	if funcID(s.comparison) != funcID(o.comparison) {
+		return fmt.Errorf("%w (%s and %s)",
+			ErrIncompatibleComparator, funcName(s.comparison), funcName(o.comparison))
+	}
+	return nil
+}
+
+// mustBeCompatible panics if debug is enabled and col is a TreeSet created
+// with a different CompareFunc than s.
+func (s *TreeSet[T]) mustBeCompatible(col Collection[T]) {
+	if !debug {
+		return
+	}
+	if o, ok := col.(*TreeSet[T]); ok {
+		if err := s.CheckCompatible(o); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func funcID[T any](f CompareFunc[T]) uintptr {
+	return reflect.ValueOf(f).Pointer()
+}
+
+func funcName[T any](f CompareFunc[T]) string {
+	if fn := runtime.FuncForPC(funcID(f)); fn != nil {
+		return fn.Name()
+	}
+	return "<unknown>"
 }
 
 // Subset returns whether col is a subset of s.
 func (s *TreeSet[T]) Subset(col Collection[T]) bool {
+	s.mustBeCompatible(col)
+
 	// try the fast paths
 	if col.Empty() {
 		return true
@@ -325,10 +638,14 @@ func (s *TreeSet[T]) Subset(col Collection[T]) bool {
 	if s.Size() < col.Size() {
 		return false
 	}
+	o, ok := col.(*TreeSet[T])
+	if !ok {
+		return subset[T](s, col)
+	}
 
 	// iterate o, and increment s finding each element
 	// i.e. merge algorithm but with channels
-	iterO := col.(*TreeSet[T]).iterate()
+	iterO := o.iterate()
 	iterS := s.iterate()
 
 	idxO := 0
@@ -364,12 +681,18 @@ func (s *TreeSet[T]) ProperSubset(col Collection[T]) bool {
 }
 
 // Union returns a set that contains all elements of s and col combined.
+//
+// col need not be a TreeSet; its elements are ordered by the CompareFunc of s.
 func (s *TreeSet[T]) Union(col Collection[T]) Collection[T] {
-	tree := NewTreeSet[T](s.comparison)
+	s.mustBeCompatible(col)
+	tree := NewTreeSet[T](s.comparison).OnDuplicate(s.onDuplicate)
 	f := func(n *node[T]) { tree.Insert(n.element) }
 	s.prefix(f, s.root)
-	oSet := col.(*TreeSet[T])
-	oSet.prefix(f, oSet.root)
+	if oSet, ok := col.(*TreeSet[T]); ok {
+		oSet.prefix(f, oSet.root)
+	} else {
+		insert[T](tree, col)
+	}
 	return tree
 }
 
@@ -397,11 +720,64 @@ func (s *TreeSet[T]) Intersect(col Collection[T]) Collection[T] {
 	return tree
 }
 
+// UnionCtx returns a set that contains all elements of s and col combined, as
+// with Union, while periodically checking whether ctx has been canceled.
+//
+// If ctx is canceled before the union is complete, the partial result is
+// returned along with an error wrapping ErrPartialResult and the error of ctx.
+func (s *TreeSet[T]) UnionCtx(ctx context.Context, col Collection[T]) (Collection[T], error) {
+	result := NewTreeSet[T](s.comparison).OnDuplicate(s.onDuplicate)
+	err := insertCtx(ctx, result, s, col)
+	return result, err
+}
+
+// DifferenceCtx returns a set that contains elements of s that are not in col,
+// as with Difference, while periodically checking whether ctx has been
+// canceled.
+//
+// If ctx is canceled before the difference is complete, the partial result is
+// returned along with an error wrapping ErrPartialResult and the error of ctx.
+func (s *TreeSet[T]) DifferenceCtx(ctx context.Context, col Collection[T]) (Collection[T], error) {
+	result := NewTreeSet[T](s.comparison).OnDuplicate(s.onDuplicate)
+	err := differenceCtx(ctx, result, s, col)
+	return result, err
+}
+
+// IntersectCtx returns a set that contains elements that are present in both s
+// and col, as with Intersect, while periodically checking whether ctx has been
+// canceled.
+//
+// If ctx is canceled before the intersection is complete, the partial result
+// is returned along with an error wrapping ErrPartialResult and the error of ctx.
+func (s *TreeSet[T]) IntersectCtx(ctx context.Context, col Collection[T]) (Collection[T], error) {
+	result := NewTreeSet[T](s.comparison).OnDuplicate(s.onDuplicate)
+	err := intersectCtx(ctx, result, s, col)
+	return result, err
+}
+
+// UnionFunc returns a set that contains the elements of s and col combined
+// which satisfy predicate, as with Union followed by filtering the result,
+// but in a single pass without creating the unfiltered union.
+func (s *TreeSet[T]) UnionFunc(col Collection[T], predicate func(T) bool) Collection[T] {
+	result := NewTreeSet[T](s.comparison).OnDuplicate(s.onDuplicate)
+	insertFunc(result, predicate, s, col)
+	return result
+}
+
+// IntersectFunc returns a set that contains the elements present in both s
+// and col which satisfy predicate, as with Intersect followed by filtering the
+// result, but in a single pass without creating the unfiltered intersection.
+func (s *TreeSet[T]) IntersectFunc(col Collection[T], predicate func(T) bool) Collection[T] {
+	result := NewTreeSet[T](s.comparison)
+	intersectFunc(result, s, col, predicate)
+	return result
+}
+
 // Copy creates a copy of s.
 //
 // Individual elements are reference copies.
 func (s *TreeSet[T]) Copy() *TreeSet[T] {
-	tree := NewTreeSet[T](s.comparison)
+	tree := NewTreeSet[T](s.comparison).OnDuplicate(s.onDuplicate)
 	f := func(n *node[T]) {
 		tree.Insert(n.element)
 	}
@@ -409,8 +785,54 @@ func (s *TreeSet[T]) Copy() *TreeSet[T] {
 	return tree
 }
 
+// CopyFunc creates a deep copy of s, where each element of the copy is created
+// by passing an element of s through clone.
+//
+// The elements of the copy are ordered anew, and so if clone produces elements
+// which compare as equal for two elements of s, the copy contains fewer
+// elements than s.
+func (s *TreeSet[T]) CopyFunc(clone func(T) T) *TreeSet[T] {
+	tree := NewTreeSet[T](s.comparison).OnDuplicate(s.onDuplicate)
+	s.prefix(func(n *node[T]) {
+		tree.Insert(clone(n.element))
+	}, s.root)
+	return tree
+}
+
+// SplitN partitions the elements of s into k sets of roughly equal size, each
+// containing a contiguous range of the elements of s. The sizes of the
+// resulting sets differ by at most one, and every element of a set is less
+// than every element of the sets following it.
+//
+// Panics if k is less than 1.
+func (s *TreeSet[T]) SplitN(k int) []*TreeSet[T] {
+	checkSplitN(k)
+	parts := make([]*TreeSet[T], k)
+	for i := range parts {
+		parts[i] = NewTreeSet[T](s.comparison)
+	}
+
+	// the first s.size%k parts receive one additional element
+	quota, extra := s.size/k, s.size%k
+	i, count := 0, 0
+	for item := range s.Items() {
+		limit := quota
+		if i < extra {
+			limit++
+		}
+		if count == limit {
+			i, count = i+1, 0
+		}
+		parts[i].Insert(item)
+		count++
+	}
+	return parts
+}
+
 // Equal return whether s and o contain the same elements.
 func (s *TreeSet[T]) Equal(o *TreeSet[T]) bool {
+	s.mustBeCompatible(o)
+
 	// try the fast fail paths
 	if s.Empty() || o.Empty() {
 		return s.Size() == o.Size()
@@ -437,7 +859,73 @@ func (s *TreeSet[T]) Equal(o *TreeSet[T]) bool {
 	return true
 }
 
-// EqualSet returns s and col contain the same elements.
+// MergeWalk calls visit for each element of s and o combined, in ascending
+// order, along with whether the element is present in s and whether it is
+// present in o. The walk stops early if visit returns false.
+//
+// The elements of both sets are visited in a single ordered pass, taking
+// O(n + m) time, which enables efficient comparisons of two sets, e.g.
+//
+//	s.MergeWalk(o, func(element T, inS, inO bool) bool {
+//	  if inS && !inO {
+//	    removed = append(removed, element)
+//	  }
+//	  return true
+//	})
+//
+// Both s and o must use the same CompareFunc. Where an element is present in
+// both sets, the element of s is visited. Neither set may be modified during
+// the walk.
+func (s *TreeSet[T]) MergeWalk(o *TreeSet[T], visit func(element T, inS, inO bool) bool) {
+	s.mustBeCompatible(o)
+	s.guard.enter()
+	defer s.guard.exit()
+	o.guard.enter()
+	defer o.guard.exit()
+
+	iterS, iterO := s.iterate(), o.iterate()
+	nextS, nextO := iterS(), iterO()
+	for nextS != nil || nextO != nil {
+		var c int
+		switch {
+		case nextO == nil:
+			c = -1
+		case nextS == nil:
+			c = 1
+		default:
+			c = s.compare(nextS, nextO)
+		}
+
+		var proceed bool
+		switch {
+		case c < 0:
+			proceed = visit(nextS.element, true, false)
+			nextS = iterS()
+		case c > 0:
+			proceed = visit(nextO.element, false, true)
+			nextO = iterO()
+		default:
+			proceed = visit(nextS.element, true, true)
+			nextS, nextO = iterS(), iterO()
+		}
+		if !proceed {
+			return
+		}
+	}
+}
+
+// ContentHash returns a hash of the elements of s which does not depend on
+// their order, combining the hash of each element as produced by hash, e.g.
+// for cheaply comparing sets of different types or in different processes.
+//
+// Sets containing the same elements have the same content hash regardless of
+// their type or internal layout. Sets containing different elements are
+// unlikely, but not guaranteed, to have different content hashes.
+func (s *TreeSet[T]) ContentHash(hash func(T) uint64) uint64 {
+	return contentHash(s, hash)
+}
+
+// EqualSet returns whether s and col contain the same elements.
 func (s *TreeSet[T]) EqualSet(col Collection[T]) bool {
 	return equalSet(s, col)
 }
@@ -455,19 +943,14 @@ func (s *TreeSet[T]) EqualSlice(items []T) bool {
 	return s.Equal(other)
 }
 
-// EqualSliceSet returns whether s and items contain exactly the same elements.
-//
-// If items contains duplicates EqualSliceSet will return false. The elements of
-// items are assumed to be set-like. For comparing s to a slice that may contain
-// duplicate elements, use EqualSlice instead.
+// EqualSliceSet returns whether s and items contain exactly the same elements,
+// where the elements of items are expected to be set-like. If items contains
+// duplicates EqualSliceSet returns false. For comparing s to a slice that may
+// contain duplicate elements, use EqualSlice instead.
 //
 // To detect if a slice is a subset of s, use ContainsSlice.
 func (s *TreeSet[T]) EqualSliceSet(items []T) bool {
-	// TODO optimize
-	if s.Size() != len(items) {
-		return false
-	}
-	return s.EqualSlice(items)
+	return equalSliceSet(s, NewTreeSet[T](s.comparison), items)
 }
 
 // String creates a string representation of s, using "%v" printf formatting
@@ -491,9 +974,19 @@ func (s *TreeSet[T]) StringFunc(f func(T) string) string {
 // Items returns a generator function for iterating each element in s by using
 // the range keyword.
 //
-//	for i, element := range s.Items() { ... }
+// The elements of s must not be inserted or removed during iteration, as
+// doing so may rebalance the underlying tree and cause elements to be skipped
+// or produced more than once. Use Snapshot to iterate s while modifying it.
+//
+// Breaking out of the loop ends the iteration; see the Iteration section of
+// the package documentation for consuming an iteration across several calls.
+//
+//	for element := range s.Items() { ... }
 func (s *TreeSet[T]) Items() iter.Seq[T] {
 	return func(yield func(T) bool) {
+		s.guard.enter()
+		defer s.guard.exit()
+
 		iter := s.iterate()
 		n := iter()
 		for i := 0; n != nil; i++ {
@@ -505,6 +998,129 @@ func (s *TreeSet[T]) Items() iter.Seq[T] {
 	}
 }
 
+// WalkPreOrder calls visit for each element of s in a pre-order traversal of
+// the underlying tree, i.e. each element before the elements of its left and
+// then right subtrees, along with the depth of the element in the tree. The
+// root of the tree has a depth of 1, as with TreeSetStats.MaxDepth.
+//
+// The walk stops early if visit returns false. The shape of the tree depends
+// on the order in which elements were inserted and removed, and so is not
+// part of the compatibility guarantees of TreeSet.
+func (s *TreeSet[T]) WalkPreOrder(visit func(element T, depth int) bool) {
+	s.guard.enter()
+	defer s.guard.exit()
+
+	s.walk(s.root, 1, visit, preOrder)
+}
+
+// WalkInOrder calls visit for each element of s in an in-order traversal of
+// the underlying tree (i.e. in ascending order), along with the depth of the
+// element in the tree, as with WalkPreOrder.
+//
+// The walk stops early if visit returns false.
+func (s *TreeSet[T]) WalkInOrder(visit func(element T, depth int) bool) {
+	s.guard.enter()
+	defer s.guard.exit()
+
+	s.walk(s.root, 1, visit, inOrder)
+}
+
+// WalkPostOrder calls visit for each element of s in a post-order traversal
+// of the underlying tree, i.e. each element after the elements of its left and
+// then right subtrees, along with the depth of the element in the tree, as with
+// WalkPreOrder.
+//
+// The walk stops early if visit returns false.
+func (s *TreeSet[T]) WalkPostOrder(visit func(element T, depth int) bool) {
+	s.guard.enter()
+	defer s.guard.exit()
+
+	s.walk(s.root, 1, visit, postOrder)
+}
+
+type walkOrder int
+
+const (
+	preOrder walkOrder = iota
+	inOrder
+	postOrder
+)
+
+// walk visits the subtree rooted at n at the given depth in order, returning
+// false if visit stopped the walk.
+func (s *TreeSet[T]) walk(n *node[T], depth int, visit func(T, int) bool, order walkOrder) bool {
+	if n == nil {
+		return true
+	}
+
+	switch {
+	case order == preOrder && !visit(n.element, depth):
+		return false
+	case !s.walk(n.left, depth+1, visit, order):
+		return false
+	case order == inOrder && !visit(n.element, depth):
+		return false
+	case !s.walk(n.right, depth+1, visit, order):
+		return false
+	case order == postOrder && !visit(n.element, depth):
+		return false
+	}
+	return true
+}
+
+// StreamOrdered returns a channel which produces each element of s in order,
+// and is closed once every element has been produced or ctx is done.
+//
+// The elements are produced by a goroutine walking s, which buffers up to
+// buffer elements ahead of the consumer and otherwise pauses until the next
+// element is received. A consumer which stops receiving before the channel is
+// closed must cancel ctx, so that the goroutine can exit.
+//
+// As with Items, s must not be modified until the channel is closed.
+//
+// StreamOrdered suits a consumer in another goroutine; otherwise ranging over
+// Items, or iter.Pull for a cursor, is simpler and needs no goroutine.
+func (s *TreeSet[T]) StreamOrdered(ctx context.Context, buffer int) <-chan T {
+	ch := make(chan T, max(buffer, 0))
+	go func() {
+		defer close(ch)
+		for item := range s.Items() {
+			select {
+			case ch <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// TreeSetStats contains statistics about the rebalancing work performed by a
+// TreeSet, e.g. for comparing the effect of different CompareFunc
+// implementations or insertion orders.
+type TreeSetStats struct {
+	// Rotations is the number of tree rotations performed.
+	Rotations int
+
+	// Recolorings is the number of times the color of a node was changed.
+	Recolorings int
+
+	// MaxDepth is the greatest depth at which an element was inserted, where
+	// the root of the tree has a depth of 1.
+	MaxDepth int
+}
+
+// Stats returns the rebalancing statistics of s, accumulated since s was
+// created or since the last call to ResetStats.
+func (s *TreeSet[T]) Stats() TreeSetStats {
+	return s.stats
+}
+
+// ResetStats resets the rebalancing statistics of s to zero.
+func (s *TreeSet[T]) ResetStats() {
+	s.stats = TreeSetStats{}
+}
+
 // Red-Black Tree Invariants
 //
 // 1. each node is either red or black
@@ -524,6 +1140,7 @@ const (
 type node[T any] struct {
 	element T
 	color   color
+	size    int // number of nodes in the subtree rooted at this node
 	parent  *node[T]
 	left    *node[T]
 	right   *node[T]
@@ -537,6 +1154,21 @@ func (n *node[T]) red() bool {
 	return n != nil && n.color == red
 }
 
+// count returns the number of nodes in the subtree rooted at n.
+//
+// A node being deleted has a size of zero while the tree is rebalanced.
+func (n *node[T]) count() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// resize recomputes the size of n from the sizes of its children.
+func (n *node[T]) resize() {
+	n.size = 1 + n.left.count() + n.right.count()
+}
+
 func (n *node[T]) get() (T, bool) {
 	if n == nil {
 		var zero T
@@ -546,6 +1178,14 @@ func (n *node[T]) get() (T, bool) {
 }
 
 func (s *TreeSet[T]) locate(start *node[T], target T) *node[T] {
+	if s.native {
+		switch start := any(start).(type) {
+		case *node[int]:
+			return any(locateOrdered(start, any(target).(int))).(*node[T])
+		case *node[uint64]:
+			return any(locateOrdered(start, any(target).(uint64))).(*node[T])
+		}
+	}
 	n := start
 	for {
 		if n == nil {
@@ -563,7 +1203,73 @@ func (s *TreeSet[T]) locate(start *node[T], target T) *node[T] {
 	}
 }
 
+// locateOrdered is locate for a TreeSet whose CompareFunc is cmp.Compare,
+// comparing elements directly rather than via the CompareFunc.
+func locateOrdered[E cmp.Ordered](n *node[E], target E) *node[E] {
+	for n != nil {
+		switch {
+		case n.element < target:
+			n = n.right
+		case n.element > target:
+			n = n.left
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// descend searches s for item from the root, returning the node equal to item
+// if present. Otherwise, it returns the node under which item belongs as its
+// left child if less is set or its right child if not, which is nil if s is
+// empty, along with the depth item would have.
+func (s *TreeSet[T]) descend(item T) (parent, existing *node[T], less bool, depth int) {
+	if s.native {
+		switch root := any(s.root).(type) {
+		case *node[int]:
+			p, e, l, d := descendOrdered(root, any(item).(int))
+			return any(p).(*node[T]), any(e).(*node[T]), l, d
+		case *node[uint64]:
+			p, e, l, d := descendOrdered(root, any(item).(uint64))
+			return any(p).(*node[T]), any(e).(*node[T]), l, d
+		}
+	}
+	depth = 1
+	for n := s.root; n != nil; depth++ {
+		parent = n
+		c := s.comparison(item, n.element)
+		switch {
+		case c < 0:
+			n, less = n.left, true
+		case c > 0:
+			n, less = n.right, false
+		default:
+			return nil, n, false, depth
+		}
+	}
+	return parent, nil, less, depth
+}
+
+// descendOrdered is descend for a TreeSet whose CompareFunc is cmp.Compare,
+// comparing elements directly rather than via the CompareFunc.
+func descendOrdered[E cmp.Ordered](n *node[E], item E) (parent, existing *node[E], less bool, depth int) {
+	depth = 1
+	for ; n != nil; depth++ {
+		parent = n
+		switch {
+		case item < n.element:
+			n, less = n.left, true
+		case item > n.element:
+			n, less = n.right, false
+		default:
+			return nil, n, false, depth
+		}
+	}
+	return parent, nil, less, depth
+}
+
 func (s *TreeSet[T]) rotateRight(n *node[T]) {
+	s.stats.Rotations++
 	parent := n.parent
 	leftChild := n.left
 
@@ -575,10 +1281,14 @@ func (s *TreeSet[T]) rotateRight(n *node[T]) {
 	leftChild.right = n
 	n.parent = leftChild
 
+	n.resize()
+	leftChild.resize()
+
 	s.replaceChild(parent, n, leftChild)
 }
 
 func (s *TreeSet[T]) rotateLeft(n *node[T]) {
+	s.stats.Rotations++
 	parent := n.parent
 	rightChild := n.right
 
@@ -590,6 +1300,9 @@ func (s *TreeSet[T]) rotateLeft(n *node[T]) {
 	rightChild.left = n
 	n.parent = rightChild
 
+	n.resize()
+	rightChild.resize()
+
 	s.replaceChild(parent, n, rightChild)
 }
 
@@ -611,42 +1324,48 @@ func (s *TreeSet[T]) replaceChild(parent, previous, next *node[T]) {
 }
 
 func (s *TreeSet[T]) insert(n *node[T]) bool {
-	var (
-		parent *node[T] = nil
-		tmp    *node[T] = s.root
-	)
-
-	for tmp != nil {
-		parent = tmp
+	s.guard.check("insert")
 
-		cmp := s.compare(n, tmp)
-		switch {
-		case cmp < 0:
-			tmp = tmp.left
-		case cmp > 0:
-			tmp = tmp.right
-		default:
-			// already exists in tree
-			return false
+	parent, existing, less, depth := s.descend(n.element)
+	if existing != nil {
+		// already exists in tree
+		if s.onDuplicate != nil {
+			existing.element = s.onDuplicate(existing.element, n.element)
 		}
+		return false
 	}
 
 	n.color = red
+	n.size = 1
 	switch {
 	case parent == nil:
 		s.root = n
-	case s.compare(n, parent) < 0:
+	case less:
 		parent.left = n
 	default:
 		parent.right = n
 	}
 	n.parent = parent
+	s.stats.MaxDepth = max(s.stats.MaxDepth, depth)
+
+	// account for n in the size of each ancestor
+	for p := parent; p != nil; p = p.parent {
+		p.size++
+	}
 
 	s.rebalanceInsertion(n)
 	s.size++
 	return true
 }
 
+// recolor sets the color of n to c, counting the change in the statistics of s.
+func (s *TreeSet[T]) recolor(n *node[T], c color) {
+	if n.color != c {
+		s.stats.Recolorings++
+	}
+	n.color = c
+}
+
 func (s *TreeSet[T]) rebalanceInsertion(n *node[T]) {
 	parent := n.parent
 
@@ -654,7 +1373,7 @@ func (s *TreeSet[T]) rebalanceInsertion(n *node[T]) {
 	// - means we are the root
 	// - our color must be black
 	if parent == nil {
-		n.color = black
+		s.recolor(n, black)
 		return
 	}
 
@@ -668,7 +1387,7 @@ func (s *TreeSet[T]) rebalanceInsertion(n *node[T]) {
 	// - we must now be black
 	grandparent := parent.parent
 	if grandparent == nil {
-		parent.color = black
+		s.recolor(parent, black)
 		return
 	}
 
@@ -679,9 +1398,9 @@ func (s *TreeSet[T]) rebalanceInsertion(n *node[T]) {
 	// - fix color of parent, grandparent, uncle
 	// - recurse upwards as necessary
 	case uncle != nil && uncle.red():
-		parent.color = black
-		grandparent.color = red
-		uncle.color = black
+		s.recolor(parent, black)
+		s.recolor(grandparent, red)
+		s.recolor(uncle, black)
 		s.rebalanceInsertion(grandparent)
 
 	case parent == grandparent.left:
@@ -697,8 +1416,8 @@ func (s *TreeSet[T]) rebalanceInsertion(n *node[T]) {
 		s.rotateRight(grandparent)
 
 		// fix color of original parent and grandparent
-		parent.color = black
-		grandparent.color = red
+		s.recolor(parent, black)
+		s.recolor(grandparent, red)
 
 		// parent is right child of grandparent
 	default:
@@ -715,59 +1434,74 @@ func (s *TreeSet[T]) rebalanceInsertion(n *node[T]) {
 		s.rotateLeft(grandparent)
 
 		// fix color of original parent and grandparent
-		parent.color = black
-		grandparent.color = red
+		s.recolor(parent, black)
+		s.recolor(grandparent, red)
 	}
 }
 
 func (s *TreeSet[T]) delete(element T) bool {
+	s.guard.check("remove")
+
 	n := s.locate(s.root, element)
 	if n == nil {
 		return false
 	}
+	s.deleteNode(n)
+	return true
+}
 
-	var (
-		moved   *node[T]
-		deleted color
-	)
-
-	if n.left == nil || n.right == nil {
-		// case where deleted node had zero or one child
-		moved = s.delete01(n)
-		deleted = n.color
-	} else {
+// deleteNode removes the element of n from s, and returns the node holding the
+// successor of that element afterwards (which may be n itself), or nil if the
+// element was the largest of s.
+func (s *TreeSet[T]) deleteNode(n *node[T]) *node[T] {
+	next := successor(n)
+	if n.left != nil && n.right != nil {
 		// case where node has two children
 
-		// find minimum of right subtree
-		successor := s.min(n.right)
-
-		// copy successor data into n
-		n.element = successor.element
-
-		// delete successor
-		moved = s.delete01(successor)
-		deleted = successor.color
+		// the successor is the minimum of the right subtree; copy its data
+		// into n, and delete the successor node instead
+		n.element = next.element
+		n, next = next, n
 	}
 
+	// n now has zero or one child
+	s.shrink(n.parent)
+	deleted := n.color
+	moved := s.delete01(n)
+
 	// re-balance if the node was black
-	if deleted == black {
+	if deleted == black && moved != nil {
 		s.rebalanceDeletion(moved)
 
-		// remove marker
-		if moved == s.marker {
-			s.replaceChild(moved.parent, moved, nil)
+		// a black leaf is detached only once the tree is rebalanced
+		if moved == n {
+			s.replaceChild(n.parent, n, nil)
 		}
 	}
 
+	// clear the detached node, which may belong to a slab that is still in use
+	*n = node[T]{}
+
 	// element was removed
 	s.size--
-	s.marker.color = black
-	s.marker.left = nil
-	s.marker.right = nil
-	s.marker.parent = nil
-	return true
+	return next
 }
 
+// shrink decrements the size of n and each of its ancestors, accounting for
+// the removal of a node from beneath n.
+func (s *TreeSet[T]) shrink(n *node[T]) {
+	for ; n != nil; n = n.parent {
+		n.size--
+	}
+}
+
+// delete01 removes n, which has zero or one child, and returns the node which
+// takes its place.
+//
+// A black leaf cannot be removed without unbalancing the tree, and so is left
+// in place with a size of zero and returned itself, to be detached by the
+// caller once the tree is rebalanced around it. This avoids the need for a
+// sentinel node shared between deletions.
 func (s *TreeSet[T]) delete01(n *node[T]) *node[T] {
 	// node only has left child, replace by left child
 	if n.left != nil {
@@ -781,22 +1515,21 @@ func (s *TreeSet[T]) delete01(n *node[T]) *node[T] {
 		return n.right
 	}
 
-	// node has both children
-	// if node is black replace with marker
+	// node has no children
+	// if node is black leave it in place for rebalancing
 	// if node is red we just remove it
 	if n.black() {
-		s.replaceChild(n.parent, n, s.marker)
-		return s.marker
-	} else {
-		s.replaceChild(n.parent, n, nil)
-		return nil
+		n.size = 0
+		return n
 	}
+	s.replaceChild(n.parent, n, nil)
+	return nil
 }
 
 func (s *TreeSet[T]) rebalanceDeletion(n *node[T]) {
 	// base case: node is root
 	if n == s.root {
-		n.color = black
+		s.recolor(n, black)
 		return
 	}
 
@@ -810,11 +1543,11 @@ func (s *TreeSet[T]) rebalanceDeletion(n *node[T]) {
 
 	// case: black sibling with two black children
 	if sibling.left.black() && sibling.right.black() {
-		sibling.color = red
+		s.recolor(sibling, red)
 
 		// case: black sibling with to black children and a red parent
 		if n.parent.red() {
-			n.parent.color = black
+			s.recolor(n.parent, black)
 		} else {
 			// case: black sibling with two black children and black parent
 			s.rebalanceDeletion(n.parent)
@@ -826,8 +1559,8 @@ func (s *TreeSet[T]) rebalanceDeletion(n *node[T]) {
 }
 
 func (s *TreeSet[T]) fixRedSibling(n *node[T], sibling *node[T]) {
-	sibling.color = black
-	n.parent.color = red
+	s.recolor(sibling, black)
+	s.recolor(n.parent, red)
 
 	switch {
 	case n == n.parent.left:
@@ -841,24 +1574,24 @@ func (s *TreeSet[T]) fixBlackSibling(n, sibling *node[T]) {
 	isLeftChild := n == n.parent.left
 
 	if isLeftChild && sibling.right.black() {
-		sibling.left.color = black
-		sibling.color = red
+		s.recolor(sibling.left, black)
+		s.recolor(sibling, red)
 		s.rotateRight(sibling)
 		sibling = n.parent.right
 	} else if !isLeftChild && sibling.left.black() {
-		sibling.right.color = black
-		sibling.color = red
+		s.recolor(sibling.right, black)
+		s.recolor(sibling, red)
 		s.rotateLeft(sibling)
 		sibling = n.parent.left
 	}
 
-	sibling.color = n.parent.color
-	n.parent.color = black
+	s.recolor(sibling, n.parent.color)
+	s.recolor(n.parent, black)
 	if isLeftChild {
-		sibling.right.color = black
+		s.recolor(sibling.right, black)
 		s.rotateLeft(n.parent)
 	} else {
-		sibling.left.color = black
+		s.recolor(sibling.left, black)
 		s.rotateRight(n.parent)
 	}
 }
@@ -894,6 +1627,34 @@ func (s *TreeSet[T]) min(n *node[T]) *node[T] {
 	return n
 }
 
+// successor returns the node following n in order, or nil if n is the last.
+func successor[T any](n *node[T]) *node[T] {
+	if n.right != nil {
+		for n = n.right; n.left != nil; n = n.left {
+		}
+		return n
+	}
+	p := n.parent
+	for p != nil && n == p.right {
+		n, p = p, p.parent
+	}
+	return p
+}
+
+// predecessor returns the node preceding n in order, or nil if n is the first.
+func predecessor[T any](n *node[T]) *node[T] {
+	if n.left != nil {
+		for n = n.left; n.right != nil; n = n.right {
+		}
+		return n
+	}
+	p := n.parent
+	for p != nil && n == p.left {
+		n, p = p, p.parent
+	}
+	return p
+}
+
 func (s *TreeSet[T]) max(n *node[T]) *node[T] {
 	for n.right != nil {
 		n = n.right
@@ -905,6 +1666,18 @@ func (s *TreeSet[T]) compare(a, b *node[T]) int {
 	return s.comparison(a.element, b.element)
 }
 
+// isNativeCompare returns whether compare is cmp.Compare of an integer type
+// for which locate and descend have a fast path.
+func isNativeCompare[T any](compare CompareFunc[T]) bool {
+	switch compare := any(compare).(type) {
+	case CompareFunc[int]:
+		return funcID(compare) == funcID(CompareFunc[int](cmp.Compare[int]))
+	case CompareFunc[uint64]:
+		return funcID(compare) == funcID(CompareFunc[uint64](cmp.Compare[uint64]))
+	}
+	return false
+}
+
 // TreeNodeVisit is a function that is called for each node in the tree.
 type TreeNodeVisit[T any] func(*node[T]) (next bool)
 
@@ -957,6 +1730,38 @@ func (s *TreeSet[T]) fillRight(n *node[T], k *[]T) {
 	}
 }
 
+// rebuild replaces the elements of s with ascending items, building a balanced
+// tree in O(n) time.
+func (s *TreeSet[T]) rebuild(items []T) {
+	s.root = nil
+	s.reclaimSlab()
+	s.root = s.build(items, nil, 0, bits.Len(uint(len(items)))-1)
+	s.size = len(items)
+}
+
+// build creates a balanced subtree from ascending items, returning its root.
+//
+// Every node at the deepest level of the tree (depth) is colored red, and every
+// other node black, so that each path from the root has the same number of black
+// nodes.
+func (s *TreeSet[T]) build(items []T, parent *node[T], level, depth int) *node[T] {
+	if len(items) == 0 {
+		return nil
+	}
+	mid := len(items) / 2
+	n := s.newNode()
+	n.element = items[mid]
+	n.color = black
+	n.size = len(items)
+	n.parent = parent
+	if level == depth && level > 0 {
+		n.color = red
+	}
+	n.left = s.build(items[:mid], n, level+1, depth)
+	n.right = s.build(items[mid+1:], n, level+1, depth)
+	return n
+}
+
 func (s *TreeSet[T]) prefix(visit func(*node[T]), n *node[T]) {
 	if n == nil {
 		return
@@ -967,19 +1772,19 @@ func (s *TreeSet[T]) prefix(visit func(*node[T]), n *node[T]) {
 }
 
 func (s *TreeSet[T]) iterate() func() *node[T] {
-	stck := makeStack[*node[T]]()
+	stck := stack.New[*node[T]]()
 
 	for n := s.root; n != nil; n = n.left {
-		stck.push(n)
+		stck.Push(n)
 	}
 
 	return func() *node[T] {
-		if stck.empty() {
+		if stck.Empty() {
 			return nil
 		}
-		n := stck.pop()
+		n := stck.Pop()
 		for r := n.right; r != nil; r = r.left {
-			stck.push(r)
+			stck.Push(r)
 		}
 		return n
 	}
@@ -991,8 +1796,62 @@ func (s *TreeSet[T]) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// Data produced by MarshalJSONTagged is also accepted, in which case the order
+// tag is ignored. Use UnmarshalJSONTagged to verify the tag.
+//
+// The set must have been created by NewTreeSet, as the zero value has no
+// CompareFunc. Use UnmarshalJSONSets or UnmarshalJSONSetMap for decoding
+// nested sets.
 func (s *TreeSet[T]) UnmarshalJSON(data []byte) error {
-	return unmarshalJSON[T](s, data)
+	if s.comparison == nil {
+		return errNoConstructor("TreeSet")
+	}
+	if !isEnvelope(data) {
+		return unmarshalJSON[T](s, data)
+	}
+	var env envelope[T]
+	if err := json.Unmarshal(data, &env); err != nil {
+		return err
+	}
+	s.InsertSlice(env.Elements)
+	return nil
+}
+
+// MarshalJSONTagged serializes s into a JSON object containing the elements of
+// s in order, along with tag, which identifies the ordering of s, e.g.
+//
+//	{"order":"semver/v1","elements":["1.2.0","1.10.0"]}
+//
+// The tag is chosen by the caller, and should change whenever the CompareFunc
+// of s changes the order of its elements. Use UnmarshalJSONTagged to decode the
+// result into a TreeSet only if it was produced with a compatible ordering.
+func (s *TreeSet[T]) MarshalJSONTagged(tag string) ([]byte, error) {
+	return json.Marshal(envelope[T]{Order: tag, Elements: s.Slice()})
+}
+
+// UnmarshalJSONTagged deserializes data produced by MarshalJSONTagged, inserting
+// each element into s.
+//
+// Returns an error wrapping ErrIncompatibleComparator if data was not produced
+// with the same tag, including if data is a plain JSON array without any tag,
+// in which case s is not modified.
+func (s *TreeSet[T]) UnmarshalJSONTagged(data []byte, tag string) error {
+	if s.comparison == nil {
+		return errNoConstructor("TreeSet")
+	}
+	if !isEnvelope(data) {
+		return fmt.Errorf("%w: expected order %q, got untagged elements", ErrIncompatibleComparator, tag)
+	}
+	var env envelope[T]
+	if err := json.Unmarshal(data, &env); err != nil {
+		return err
+	}
+	if env.Order != tag {
+		return fmt.Errorf("%w: expected order %q, got %q", ErrIncompatibleComparator, tag, env.Order)
+	}
+	s.InsertSlice(env.Elements)
+	return nil
 }
 
 func (s *TreeSet[T]) filterLeft(n *node[T], accept func(element T) bool, result *TreeSet[T]) {
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build setdebug
+
+package set
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestTreeSet_debug_incompatible(t *testing.T) {
+	reverse := func(a, b int) int { return cmp.Compare(b, a) }
+	a := TreeSetFrom[int]([]int{1, 2, 3}, cmp.Compare[int])
+	b := TreeSetFrom[int]([]int{1, 2}, reverse)
+
+	panics := func(f func()) (result bool) {
+		defer func() { result = recover() != nil }()
+		f()
+		return
+	}
+
+	must.True(t, panics(func() { a.Subset(b) }))
+	must.True(t, panics(func() { a.Equal(b) }))
+	must.True(t, panics(func() { a.Union(b) }))
+	must.False(t, panics(func() { a.Subset(a.Copy()) }))
+}
+
+func TestTreeSet_debug_modifyDuringIteration(t *testing.T) {
+	panics := func(f func()) (message any) {
+		defer func() { message = recover() }()
+		f()
+		return
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		ts := TreeSetFrom[int](ints(10), cmp.Compare[int])
+		msg := panics(func() {
+			for item := range ts.Items() {
+				ts.Insert(item + 100)
+			}
+		})
+		must.StrContains(t, msg.(string), "insert during iteration")
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		ts := TreeSetFrom[int](ints(10), cmp.Compare[int])
+		msg := panics(func() {
+			for item := range ts.Items() {
+				ts.Remove(item)
+			}
+		})
+		must.StrContains(t, msg.(string), "remove during iteration")
+	})
+
+	t.Run("after break", func(t *testing.T) {
+		ts := TreeSetFrom[int](ints(10), cmp.Compare[int])
+		for range ts.Items() {
+			break
+		}
+		must.True(t, ts.Insert(11))
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		ts := TreeSetFrom[int](ints(10), cmp.Compare[int])
+		for item := range Snapshot[int](ts) {
+			ts.Remove(item)
+		}
+		must.Empty(t, ts)
+	})
+}
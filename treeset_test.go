@@ -5,10 +5,16 @@ package set
 
 import (
 	"cmp"
+	"context"
 	"fmt"
+	"iter"
 	"math/rand"
+	"runtime"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/shoenig/test/must"
 )
@@ -52,6 +58,15 @@ func TestTreeSetFrom(t *testing.T) {
 	must.NotEmpty(t, ts)
 }
 
+func TestTreeSetOf(t *testing.T) {
+	ts := TreeSetOf(cmp.Compare[int], 3, 1, 2, 1)
+	must.Eq(t, []int{1, 2, 3}, ts.Slice())
+	invariants(t, ts, cmp.Compare[int])
+
+	ts = TreeSetOf[int](cmp.Compare[int])
+	must.Empty(t, ts)
+}
+
 func TestTreeSet_Empty(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		ts := NewTreeSet[int](cmp.Compare[int])
@@ -237,6 +252,75 @@ func TestTreeSet_RemoveFunc(t *testing.T) {
 	must.Eq(t, []byte{'a', 'b', 'c', 'd'}, ts.Slice())
 }
 
+func TestTreeSet_RemoveFuncN(t *testing.T) {
+	ts := TreeSetFrom[int](shuffle(ints(20)), cmp.Compare[int])
+	even := func(i int) bool { return i%2 == 0 }
+
+	// the smallest matching elements are removed first
+	must.Eq(t, 3, ts.RemoveFuncN(even, 3))
+	must.Eq(t, []int{1, 3, 5, 7, 8, 9, 10}, ts.TopK(7))
+	invariants(t, ts, cmp.Compare[int])
+
+	must.Eq(t, 7, ts.RemoveFuncN(even, 100))
+	must.Size(t, 10, ts)
+	invariants(t, ts, cmp.Compare[int])
+}
+
+func TestTreeSet_CountRange(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		ts := NewTreeSet[int](cmp.Compare[int])
+		must.Zero(t, ts.CountRange(1, 10))
+	})
+
+	t.Run("inclusive", func(t *testing.T) {
+		ts := TreeSetFrom[int](shuffle(ints(100)), cmp.Compare[int])
+		must.Eq(t, 100, ts.CountRange(1, 100))
+		must.Eq(t, 100, ts.CountRange(-5, 500))
+		must.Eq(t, 11, ts.CountRange(10, 20))
+		must.Eq(t, 1, ts.CountRange(50, 50))
+		must.Zero(t, ts.CountRange(101, 200))
+		must.Zero(t, ts.CountRange(20, 10))
+	})
+
+	t.Run("sparse", func(t *testing.T) {
+		ts := TreeSetFrom[int]([]int{10, 20, 30, 40, 50}, cmp.Compare[int])
+		must.Eq(t, 2, ts.CountRange(15, 35))
+		must.Eq(t, 3, ts.CountRange(10, 30))
+		must.Zero(t, ts.CountRange(11, 19))
+	})
+
+	t.Run("after removals", func(t *testing.T) {
+		ts := TreeSetFrom[int](shuffle(ints(size)), cmp.Compare[int])
+		for _, i := range shuffle(ints(size)) {
+			if i%3 == 0 {
+				ts.Remove(i)
+			}
+		}
+		invariants(t, ts, cmp.Compare[int])
+		for lo := 0; lo <= size; lo += 37 {
+			hi := lo + 100
+			must.Eq(t, ts.AboveEqual(lo).BelowEqual(hi).Size(), ts.CountRange(lo, hi))
+		}
+	})
+}
+
+func TestTreeSet_CheckCompatible(t *testing.T) {
+	t.Run("same", func(t *testing.T) {
+		a := TreeSetFrom[int]([]int{1, 2, 3}, cmp.Compare[int])
+		b := TreeSetFrom[int]([]int{3, 4, 5}, cmp.Compare[int])
+		must.NoError(t, a.CheckCompatible(b))
+	})
+
+	t.Run("different", func(t *testing.T) {
+		reverse := func(a, b int) int { return cmp.Compare(b, a) }
+		a := TreeSetFrom[int]([]int{1, 2, 3}, cmp.Compare[int])
+		b := TreeSetFrom[int]([]int{3, 4, 5}, reverse)
+		err := a.CheckCompatible(b)
+		must.ErrorIs(t, err, ErrIncompatibleComparator)
+		must.ErrorContains(t, err, "incompatible compare functions")
+	})
+}
+
 func TestTreeSet_Contains(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		ts := NewTreeSet[int](cmp.Compare[int])
@@ -279,6 +363,12 @@ func TestTreeSet_ContainsSlice(t *testing.T) {
 }
 
 func TestTreeSet_Subset(t *testing.T) {
+	t.Run("other collection", func(t *testing.T) {
+		t1 := TreeSetFrom[int]([]int{1, 2, 3}, cmp.Compare[int])
+		must.True(t, t1.Subset(From([]int{1, 3})))
+		must.False(t, t1.Subset(From([]int{1, 4})))
+	})
+
 	t.Run("empty empty", func(t *testing.T) {
 		t1 := NewTreeSet[int](cmp.Compare[int])
 		t2 := NewTreeSet[int](cmp.Compare[int])
@@ -381,6 +471,12 @@ func TestTreeSet_ProperSubset(t *testing.T) {
 }
 
 func TestTreeSet_Union(t *testing.T) {
+	t.Run("other collection", func(t *testing.T) {
+		t1 := TreeSetFrom[int]([]int{3, 1}, cmp.Compare[int])
+		result := t1.Union(From([]int{2, 3}))
+		must.Eq(t, []int{1, 2, 3}, result.Slice())
+	})
+
 	t.Run("empty empty", func(t *testing.T) {
 		t1 := TreeSetFrom[int](nil, cmp.Compare[int])
 		t2 := TreeSetFrom[int](nil, cmp.Compare[int])
@@ -515,6 +611,161 @@ func TestTreeSet_Copy(t *testing.T) {
 	})
 }
 
+func TestTreeSet_OnDuplicate(t *testing.T) {
+	type entry struct {
+		key   string
+		value int
+	}
+	compare := func(a, b entry) int {
+		return cmp.Compare(a.key, b.key)
+	}
+
+	t.Run("keep", func(t *testing.T) {
+		s := TreeSetOf(compare, entry{"a", 1})
+		must.False(t, s.Insert(entry{"a", 2}))
+		must.Eq(t, []entry{{"a", 1}}, s.Slice())
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		s := NewTreeSet(compare).OnDuplicate(ReplaceExisting[entry])
+		must.True(t, s.Insert(entry{"a", 1}))
+		must.False(t, s.Insert(entry{"a", 2}))
+		must.True(t, s.Insert(entry{"b", 3}))
+		must.Eq(t, []entry{{"a", 2}, {"b", 3}}, s.Slice())
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		sum := func(existing, incoming entry) entry {
+			return entry{existing.key, existing.value + incoming.value}
+		}
+		s := NewTreeSet(compare).OnDuplicate(sum)
+		s.InsertSlice([]entry{{"a", 1}, {"b", 1}, {"a", 2}})
+		must.Eq(t, []entry{{"a", 3}, {"b", 1}}, s.Slice())
+
+		// copies and unions resolve duplicates in the same way
+		c := s.Copy()
+		c.Insert(entry{"b", 5})
+		must.Eq(t, []entry{{"a", 3}, {"b", 6}}, c.Slice())
+		u := s.Union(TreeSetOf(compare, entry{"a", 10}))
+		must.Eq(t, []entry{{"a", 13}, {"b", 1}}, u.Slice())
+		uc, err := s.UnionCtx(context.Background(), TreeSetOf(compare, entry{"a", 10}))
+		must.NoError(t, err)
+		must.Eq(t, []entry{{"a", 13}, {"b", 1}}, uc.Slice())
+
+		// as do the results of the other operations taking a context
+		ic, err := s.IntersectCtx(context.Background(), TreeSetOf(compare, entry{"a", 1}))
+		must.NoError(t, err)
+		ic.Insert(entry{"a", 4})
+		must.Eq(t, []entry{{"a", 5}}, ic.Slice())
+		dc, err := s.DifferenceCtx(context.Background(), TreeSetOf(compare, entry{"a", 0}))
+		must.NoError(t, err)
+		dc.Insert(entry{"b", 4})
+		must.Eq(t, []entry{{"b", 5}}, dc.Slice())
+	})
+}
+
+func TestTreeSet_Grow(t *testing.T) {
+	t.Run("allocs", func(t *testing.T) {
+		var s *TreeSet[int]
+		allocs := testing.AllocsPerRun(10, func() {
+			s = NewTreeSet(cmp.Compare[int])
+			s.Grow(100)
+			for i := range 100 {
+				s.Insert(i)
+			}
+		})
+		// the set and the slab
+		must.Eq(t, 2, allocs)
+		invariants(t, s, cmp.Compare[int])
+		must.Size(t, 100, s)
+	})
+
+	t.Run("duplicates", func(t *testing.T) {
+		s := NewTreeSet(cmp.Compare[int])
+		s.Grow(2)
+		must.True(t, s.Insert(1))
+		must.False(t, s.Insert(1))
+		must.True(t, s.Insert(2))
+		// the duplicate did not consume the slab
+		must.SliceEmpty(t, s.slab)
+		must.Eq(t, []int{1, 2}, s.Slice())
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		s := NewTreeSet(cmp.Compare[int])
+		s.Grow(10)
+		s.InsertSlice(ints(50))
+		for i := 1; i <= 50; i += 2 {
+			s.Remove(i)
+		}
+		invariants(t, s, cmp.Compare[int])
+		must.Size(t, 25, s)
+	})
+
+	t.Run("collect removed", func(t *testing.T) {
+		type big struct {
+			id   int
+			data [1 << 10]byte
+		}
+		var collected atomic.Int64
+		s := NewTreeSet(func(a, b *big) int {
+			return cmp.Compare(a.id, b.id)
+		})
+		s.Grow(100)
+		for i := range 100 {
+			b := &big{id: i}
+			runtime.SetFinalizer(b, func(*big) { collected.Add(1) })
+			s.Insert(b)
+		}
+
+		// awaitCollected waits for finalizers to run for n elements in total
+		awaitCollected := func(n int64) {
+			for range 100 {
+				runtime.GC()
+				if collected.Load() >= n {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+			must.Eq(t, n, collected.Load())
+		}
+
+		// the remaining element keeps the slab alive, but not removed elements
+		for i := range 99 {
+			must.True(t, s.Remove(&big{id: i}))
+		}
+		awaitCollected(99)
+		must.Size(t, 1, s)
+
+		s.Clear()
+		awaitCollected(100)
+		runtime.KeepAlive(s)
+	})
+
+	t.Run("opt in", func(t *testing.T) {
+		s := TreeSetFrom([]int{1, 1, 1, 2}, cmp.Compare[int])
+		must.Zero(t, cap(s.slab))
+		must.Zero(t, cap(s.Copy().slab))
+		must.Zero(t, cap(s.CopyFunc(func(i int) int { return i }).slab))
+	})
+}
+
+func TestTreeSet_CopyFunc(t *testing.T) {
+	t1 := TreeSetFrom[*token]([]*token{tokenA, tokenB, tokenC}, compareTokens)
+	c := t1.CopyFunc(func(t *token) *token {
+		return &token{id: t.id}
+	})
+	must.True(t, t1.Equal(c))
+	invariants(t, c, compareTokens)
+
+	// the elements of c are not those of t1
+	for tok := range c.Items() {
+		tok.id += "'"
+	}
+	must.Eq(t, "[A B C]", t1.String())
+	must.Eq(t, "[A' B' C']", c.String())
+}
+
 func TestTreeSet_EqualSlice(t *testing.T) {
 	t.Run("empty empty", func(t *testing.T) {
 		ts := TreeSetFrom[int](nil, cmp.Compare[int])
@@ -575,6 +826,71 @@ func TestTreeSet_Equal(t *testing.T) {
 	})
 }
 
+func TestTreeSet_MergeWalk(t *testing.T) {
+	type visit struct {
+		element  int
+		inS, inO bool
+	}
+	walk := func(s, o *TreeSet[int], limit int) []visit {
+		var visits []visit
+		s.MergeWalk(o, func(element int, inS, inO bool) bool {
+			visits = append(visits, visit{element, inS, inO})
+			return len(visits) < limit
+		})
+		return visits
+	}
+
+	s := TreeSetFrom([]int{1, 3, 5, 6}, cmp.Compare[int])
+	o := TreeSetFrom([]int{2, 3, 6, 8, 9}, cmp.Compare[int])
+	empty := NewTreeSet[int](cmp.Compare[int])
+
+	t.Run("both", func(t *testing.T) {
+		must.Eq(t, []visit{
+			{1, true, false},
+			{2, false, true},
+			{3, true, true},
+			{5, true, false},
+			{6, true, true},
+			{8, false, true},
+			{9, false, true},
+		}, walk(s, o, 100))
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		must.Eq(t, []visit{{1, true, false}, {2, false, true}}, walk(s, o, 2))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		must.SliceEmpty(t, walk(empty, empty, 100))
+		must.Eq(t, []visit{{1, false, true}, {3, false, true}, {5, false, true}, {6, false, true}}, walk(empty, s, 100))
+		must.Eq(t, []visit{{1, true, false}, {3, true, false}, {5, true, false}, {6, true, false}}, walk(s, empty, 100))
+	})
+
+	t.Run("self", func(t *testing.T) {
+		must.Eq(t, []visit{{1, true, true}, {3, true, true}, {5, true, true}, {6, true, true}}, walk(s, s, 100))
+	})
+
+	t.Run("large", func(t *testing.T) {
+		a := TreeSetFrom(shuffle(ints(size)), cmp.Compare[int])
+		b := TreeSetFrom(shuffle(ints(size * 2)[size/2:]), cmp.Compare[int])
+		var both, onlyA, onlyB []int
+		a.MergeWalk(b, func(element int, inA, inB bool) bool {
+			switch {
+			case inA && inB:
+				both = append(both, element)
+			case inA:
+				onlyA = append(onlyA, element)
+			default:
+				onlyB = append(onlyB, element)
+			}
+			return true
+		})
+		must.Eq(t, a.Intersect(b).Slice(), both)
+		must.Eq(t, a.Difference(b).Slice(), onlyA)
+		must.Eq(t, b.Difference(a).Slice(), onlyB)
+	})
+}
+
 func TestTreeSet_EqualSet(t *testing.T) {
 	t.Run("empty empty", func(t *testing.T) {
 		t1 := TreeSetFrom[int](nil, cmp.Compare[int])
@@ -644,6 +960,54 @@ func TestTreeSet_BottomK(t *testing.T) {
 	})
 }
 
+func TestTreeSet_TruncateToSmallestK(t *testing.T) {
+	t.Run("few", func(t *testing.T) {
+		ts := TreeSetFrom[int](shuffle(ints(100)), cmp.Compare[int])
+		must.Eq(t, 10, ts.TruncateToSmallestK(90))
+		must.Eq(t, ints(90), ts.Slice())
+		invariants(t, ts, cmp.Compare[int])
+		must.Positive(t, blackHeight(t, ts.root))
+	})
+
+	t.Run("many", func(t *testing.T) {
+		for k := range 20 {
+			ts := TreeSetFrom[int](shuffle(ints(100)), cmp.Compare[int])
+			must.Eq(t, 100-k, ts.TruncateToSmallestK(k))
+			must.Eq(t, ints(k), ts.Slice())
+			invariants(t, ts, cmp.Compare[int])
+			blackHeight(t, ts.root)
+
+			// the rebuilt tree remains usable
+			ts.InsertSlice([]int{0, 200})
+			ts.Remove(0)
+			invariants(t, ts, cmp.Compare[int])
+			blackHeight(t, ts.root)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		ts := TreeSetFrom[int](ints(5), cmp.Compare[int])
+		must.Zero(t, ts.TruncateToSmallestK(5))
+		must.Zero(t, ts.TruncateToSmallestK(10))
+		must.Size(t, 5, ts)
+		must.Eq(t, 5, ts.TruncateToSmallestK(-1))
+		must.Empty(t, ts)
+	})
+}
+
+func TestTreeSet_TruncateToLargestK(t *testing.T) {
+	ts := TreeSetFrom[int](shuffle(ints(100)), cmp.Compare[int])
+	must.Eq(t, 10, ts.TruncateToLargestK(90))
+	must.Eq(t, ints(100)[10:], ts.Slice())
+	invariants(t, ts, cmp.Compare[int])
+	blackHeight(t, ts.root)
+
+	must.Eq(t, 87, ts.TruncateToLargestK(3))
+	must.Eq(t, []int{98, 99, 100}, ts.Slice())
+	invariants(t, ts, cmp.Compare[int])
+	blackHeight(t, ts.root)
+}
+
 func TestTreeSet_FirstBelow(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		ts := NewTreeSet[int](cmp.Compare[int])
@@ -870,6 +1234,14 @@ func TestTreeSet_Slice(t *testing.T) {
 	})
 }
 
+func TestTreeSet_AppendSlice(t *testing.T) {
+	ts := TreeSetFrom[int]([]int{3, 1, 2}, cmp.Compare[int])
+	buf := make([]int, 0, 10)
+	buf = ts.AppendSlice(append(buf, 9))
+	must.Eq(t, []int{9, 1, 2, 3}, buf)
+	must.Eq(t, 10, cap(buf))
+}
+
 func TestTreeSet_String(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		ts := NewTreeSet[int](cmp.Compare[int])
@@ -959,6 +1331,39 @@ func invariants[T any](t *testing.T, tree *TreeSet[T], cmp CompareFunc[T]) {
 
 	// assert slice[len(slice)-1] is the maximum
 	must.Max(t, slice[len(slice)-1], tree)
+
+	// assert subtree sizes are consistent
+	must.Eq(t, size, sizes(t, tree.root))
+}
+
+// sizes asserts the size of each subtree rooted at n is correct
+func sizes[T any](t *testing.T, n *node[T]) int {
+	if n == nil {
+		return 0
+	}
+	count := 1 + sizes(t, n.left) + sizes(t, n.right)
+	must.Eq(t, count, n.size, must.Sprintf("wrong subtree size at %v", n.element))
+	return count
+}
+
+// blackHeight asserts the subtree rooted at n satisfies the red-black
+// properties, returning its black height
+func blackHeight[T any](t *testing.T, n *node[T]) int {
+	if n == nil {
+		return 1
+	}
+	if n.red() {
+		must.True(t, n.left.black() && n.right.black(), must.Sprintf("red node %v has a red child", n.element))
+	}
+	if n.parent == nil {
+		must.True(t, n.black(), must.Sprint("root is red"))
+	}
+	left, right := blackHeight(t, n.left), blackHeight(t, n.right)
+	must.Eq(t, left, right, must.Sprintf("unequal black heights at %v", n.element))
+	if n.black() {
+		left++
+	}
+	return left
 }
 
 // ints will create a []int from 1 to n
@@ -1024,3 +1429,249 @@ func TestTreeSet_Items(t *testing.T) {
 
 	must.Eq(t, exp, result)
 }
+
+func TestTreeSet_StreamOrdered(t *testing.T) {
+	t.Run("all", func(t *testing.T) {
+		ts := TreeSetFrom[int](shuffle(ints(100)), cmp.Compare[int])
+		result := make([]int, 0, 100)
+		for item := range ts.StreamOrdered(context.Background(), 10) {
+			result = append(result, item)
+		}
+		must.Eq(t, ints(100), result)
+	})
+
+	t.Run("unbuffered", func(t *testing.T) {
+		ts := TreeSetFrom[int](ints(3), cmp.Compare[int])
+		ch := ts.StreamOrdered(context.Background(), 0)
+		must.Eq(t, 1, <-ch)
+		must.Eq(t, 2, <-ch)
+		must.Eq(t, 3, <-ch)
+		_, ok := <-ch
+		must.False(t, ok)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ts := TreeSetFrom[int](ints(100), cmp.Compare[int])
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := ts.StreamOrdered(ctx, 1)
+		must.Eq(t, 1, <-ch)
+		cancel()
+
+		// the channel is closed without producing every element
+		count := 0
+		for range ch {
+			count++
+		}
+		must.Less(t, 99, count)
+	})
+}
+
+func TestTreeSet_Pull(t *testing.T) {
+	ts := TreeSetFrom[int](shuffle(ints(100)), cmp.Compare[int])
+
+	// a cursor consumed across several calls, stopped before the end
+	next, stop := iter.Pull(ts.Items())
+	for i := 1; i <= 3; i++ {
+		item, ok := next()
+		must.True(t, ok)
+		must.Eq(t, i, item)
+	}
+	stop()
+
+	// the set may be modified once the cursor is stopped
+	must.True(t, ts.Remove(1))
+	_, ok := next()
+	must.False(t, ok)
+}
+
+func TestTreeSet_Walk(t *testing.T) {
+	// ascending insertion of 1 through 5 creates the tree
+	//
+	//       2
+	//     /   \
+	//    1     4
+	//         / \
+	//        3   5
+	ts := TreeSetFrom[int](ints(5), cmp.Compare[int])
+
+	type visit struct{ element, depth int }
+	collect := func(walk func(func(int, int) bool), limit int) []visit {
+		var result []visit
+		walk(func(element, depth int) bool {
+			result = append(result, visit{element, depth})
+			return len(result) < limit
+		})
+		return result
+	}
+
+	must.Eq(t, []visit{{2, 1}, {1, 2}, {4, 2}, {3, 3}, {5, 3}}, collect(ts.WalkPreOrder, 10))
+	must.Eq(t, []visit{{1, 2}, {2, 1}, {3, 3}, {4, 2}, {5, 3}}, collect(ts.WalkInOrder, 10))
+	must.Eq(t, []visit{{1, 2}, {3, 3}, {5, 3}, {4, 2}, {2, 1}}, collect(ts.WalkPostOrder, 10))
+
+	// the walk stops early
+	must.Eq(t, []visit{{2, 1}, {1, 2}}, collect(ts.WalkPreOrder, 2))
+	must.Eq(t, []visit{{1, 2}, {2, 1}, {3, 3}}, collect(ts.WalkInOrder, 3))
+	must.Eq(t, []visit{{1, 2}}, collect(ts.WalkPostOrder, 1))
+
+	must.SliceEmpty(t, collect(NewTreeSet[int](cmp.Compare[int]).WalkInOrder, 10))
+}
+
+func TestTreeSet_Stats(t *testing.T) {
+	ts := NewTreeSet[int](cmp.Compare[int])
+	must.Eq(t, TreeSetStats{}, ts.Stats())
+
+	// the root is inserted red and recolored black
+	ts.Insert(2)
+	must.Eq(t, TreeSetStats{Recolorings: 1, MaxDepth: 1}, ts.Stats())
+
+	// ascending insertions require a rotation
+	ts.Insert(3)
+	ts.Insert(4)
+	must.Eq(t, TreeSetStats{Rotations: 1, Recolorings: 3, MaxDepth: 3}, ts.Stats())
+
+	ts.ResetStats()
+	must.Eq(t, TreeSetStats{}, ts.Stats())
+
+	ts.InsertSlice(ints(1000))
+	stats := ts.Stats()
+	must.Positive(t, stats.Rotations)
+	must.Positive(t, stats.Recolorings)
+	must.Between(t, 10, stats.MaxDepth, 2*10)
+}
+
+func TestTreeSet_SplitN(t *testing.T) {
+	s := TreeSetFrom(ints(10), cmp.Compare[int])
+
+	parts := s.SplitN(3)
+	must.SliceLen(t, 3, parts)
+	must.Eq(t, []int{1, 2, 3, 4}, parts[0].Slice())
+	must.Eq(t, []int{5, 6, 7}, parts[1].Slice())
+	must.Eq(t, []int{8, 9, 10}, parts[2].Slice())
+	for _, part := range parts {
+		invariants(t, part, cmp.Compare[int])
+	}
+
+	parts = s.SplitN(1)
+	must.Eq(t, s.Slice(), parts[0].Slice())
+
+	parts = TreeSetFrom([]int{1, 2}, cmp.Compare[int]).SplitN(4)
+	must.Eq(t, []int{1}, parts[0].Slice())
+	must.Eq(t, []int{2}, parts[1].Slice())
+	must.Empty(t, parts[2])
+	must.Empty(t, parts[3])
+
+	for _, k := range []int{0, -1} {
+		msg := fmt.Sprintf("set: SplitN into %d sets, must be at least 1", k)
+		must.Eq[any](t, msg, recovered(func() { NewTreeSet[int](cmp.Compare[int]).SplitN(k) }))
+		must.Eq[any](t, msg, recovered(func() { TreeSetFrom(ints(3), cmp.Compare[int]).SplitN(k) }))
+	}
+}
+
+func TestTreeSet_TryMin(t *testing.T) {
+	ts := NewTreeSet[int](cmp.Compare[int])
+	_, err := ts.TryMin()
+	must.ErrorIs(t, err, ErrEmptySet)
+	_, err = ts.TryMax()
+	must.ErrorIs(t, err, ErrEmptySet)
+
+	ts.InsertSlice([]int{3, 1, 2})
+	least, err := ts.TryMin()
+	must.NoError(t, err)
+	must.Eq(t, 1, least)
+	greatest, err := ts.TryMax()
+	must.NoError(t, err)
+	must.Eq(t, 3, greatest)
+}
+
+func TestTreeSet_Find(t *testing.T) {
+	t.Run("navigate", func(t *testing.T) {
+		ts := TreeSetFrom(shuffle(ints(size)), cmp.Compare[int])
+
+		must.False(t, ts.Find(0).Valid())
+		must.False(t, Handle[int]{}.Valid())
+
+		var forward []int
+		for h := ts.Find(1); h.Valid(); h = h.Next() {
+			forward = append(forward, h.Element())
+		}
+		must.Eq(t, ints(size), forward)
+
+		var backward []int
+		for h := ts.Find(size); h.Valid(); h = h.Prev() {
+			backward = append(backward, h.Element())
+		}
+		slices.Reverse(backward)
+		must.Eq(t, ints(size), backward)
+
+		// handles survive insertions
+		h := ts.Find(10)
+		ts.InsertSlice([]int{-1, -2, size + 1, size + 2})
+		must.Eq(t, 10, h.Element())
+		must.Eq(t, 11, h.Next().Element())
+		must.Eq(t, 9, h.Prev().Element())
+		invariants(t, ts, cmp.Compare[int])
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		ts := TreeSetFrom(shuffle(ints(size)), cmp.Compare[int])
+
+		// remove every even element
+		for h := ts.Find(1); h.Valid(); {
+			if h.Element()%2 == 0 {
+				h = h.Remove()
+			} else {
+				h = h.Next()
+			}
+			invariants(t, ts, cmp.Compare[int])
+		}
+		must.Eq(t, size/2, ts.Size())
+		for _, element := range ts.Slice() {
+			must.Eq(t, 1, element%2)
+		}
+
+		// remove returns the next element
+		h := ts.Find(5).Remove()
+		must.Eq(t, 7, h.Element())
+		must.NotContains[int](t, 5, ts)
+
+		// removing the largest element leaves an invalid handle
+		must.False(t, ts.Find(ts.Max()).Remove().Valid())
+		invariants(t, ts, cmp.Compare[int])
+	})
+
+	t.Run("window", func(t *testing.T) {
+		ts := TreeSetFrom(ints(10), cmp.Compare[int])
+
+		// slide a window of width 3 along the set, removing its leading element
+		for lo := ts.Find(1); lo.Next().Next().Next().Valid(); {
+			lo = lo.Remove()
+			must.Eq(t, lo.Element(), ts.Min())
+		}
+		must.Eq(t, []int{8, 9, 10}, ts.Slice())
+		invariants(t, ts, cmp.Compare[int])
+	})
+}
+
+func TestTreeSet_native(t *testing.T) {
+	must.True(t, NewTreeSet(cmp.Compare[int]).native)
+	must.True(t, NewTreeSet(cmp.Compare[uint64]).native)
+	must.False(t, NewTreeSet(cmp.Compare[string]).native)
+	must.False(t, NewTreeSet(cmp.Compare[int32]).native)
+	must.False(t, NewTreeSet(func(a, b int) int { return cmp.Compare(a, b) }).native)
+	must.False(t, NewTreeSet(func(a, b int) int { return cmp.Compare(b, a) }).native)
+
+	ts := TreeSetFrom([]uint64{5, 1, 1 << 63, 3}, cmp.Compare[uint64])
+	must.Eq(t, []uint64{1, 3, 5, 1 << 63}, ts.Slice())
+	must.Contains[uint64](t, 1<<63, ts)
+	must.NotContains[uint64](t, 2, ts)
+	invariants(t, ts, cmp.Compare[uint64])
+
+	// the fast path orders elements as cmp.Compare does
+	items := shuffle(ints(size))
+	native := TreeSetFrom(items, cmp.Compare[int])
+	reversed := TreeSetFrom(items, func(a, b int) int { return cmp.Compare(b, a) })
+	slice := reversed.Slice()
+	slices.Reverse(slice)
+	must.Eq(t, native.Slice(), slice)
+	invariants(t, native, cmp.Compare[int])
+}
@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// defaultValidatorLimit is the number of allowed values listed by the errors
+// of a Validator, unless configured otherwise via WithLimit.
+const defaultValidatorLimit = 10
+
+// Validator checks values against a fixed set of allowed values, e.g. for
+// validating an enumerated field of a configuration file, as created by
+// AllowedValues.
+//
+// A Validator is not modified by Check, and so is safe for concurrent use by
+// multiple goroutines once configured.
+type Validator[T cmp.Ordered] struct {
+	allowed *Set[T]
+	sorted  []T
+	limit   int
+}
+
+// AllowedValues creates a Validator which allows each of values, e.g.
+//
+//	var modes = set.AllowedValues("client", "server")
+//
+//	if err := modes.Check(config.Mode); err != nil { ... }
+func AllowedValues[T cmp.Ordered](values ...T) *Validator[T] {
+	allowed := From(values)
+	sorted := allowed.Slice()
+	slices.Sort(sorted)
+	return &Validator[T]{
+		allowed: allowed,
+		sorted:  sorted,
+		limit:   defaultValidatorLimit,
+	}
+}
+
+// WithLimit configures v to list at most n of the allowed values in the errors
+// returned by Check, summarizing the rest by their number. A limit of zero or
+// less lists every allowed value. Returns v.
+func (v *Validator[T]) WithLimit(n int) *Validator[T] {
+	v.limit = n
+	return v
+}
+
+// Check returns nil if value is allowed by v.
+//
+// Otherwise returns an error wrapping ErrNotAllowed, which lists the allowed
+// values in ascending order, e.g.
+//
+//	set: value not allowed: "proxy" is not one of "client", "server"
+func (v *Validator[T]) Check(value T) error {
+	switch {
+	case v.allowed.Contains(value):
+		return nil
+	case v.allowed.Empty():
+		return fmt.Errorf("%w: %#v, as no values are allowed", ErrNotAllowed, value)
+	}
+
+	listed := v.sorted
+	if v.limit > 0 && len(listed) > v.limit {
+		listed = listed[:v.limit]
+	}
+	var sb strings.Builder
+	for i, item := range listed {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%#v", item)
+	}
+	if rest := len(v.sorted) - len(listed); rest > 0 {
+		fmt.Fprintf(&sb, ", ... (%d more)", rest)
+	}
+	return fmt.Errorf("%w: %#v is not one of %s", ErrNotAllowed, value, sb.String())
+}
@@ -0,0 +1,36 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package set
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestValidator_Check(t *testing.T) {
+	v := AllowedValues("server", "client", "server")
+	must.NoError(t, v.Check("client"))
+	must.NoError(t, v.Check("server"))
+
+	err := v.Check("proxy")
+	must.ErrorIs(t, err, ErrNotAllowed)
+	must.EqError(t, err, `set: value not allowed: "proxy" is not one of "client", "server"`)
+
+	t.Run("limit", func(t *testing.T) {
+		v := AllowedValues(shuffle(ints(15))...)
+		must.EqError(t, v.Check(0), "set: value not allowed: 0 is not one of 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, ... (5 more)")
+
+		v.WithLimit(2)
+		must.EqError(t, v.Check(16), "set: value not allowed: 16 is not one of 1, 2, ... (13 more)")
+
+		v.WithLimit(0)
+		must.StrContains(t, v.Check(16).Error(), "14, 15")
+	})
+
+	t.Run("none", func(t *testing.T) {
+		v := AllowedValues[string]()
+		must.EqError(t, v.Check(""), `set: value not allowed: "", as no values are allowed`)
+	})
+}